@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/Vilsol/lakta/pkg/config"
+	"github.com/Vilsol/lakta/pkg/lakta"
+	"github.com/Vilsol/lakta/pkg/reflectcfg"
+	"github.com/knadh/koanf/v2"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// parityKey is a key no config struct declares.
+const parityKey = "zz_parity"
+
+// opaqueTypes are field types reflectcfg documents without describing their
+// values: a config block from another package, and untyped map values. The
+// config module checks both, so the probes leave such fields out.
+var opaqueTypes = map[string]bool{
+	"config.TLS":     true,
+	"map[string]any": true,
+}
+
+// entryModule hands an Entry's config struct to the config module's schema.
+type entryModule struct {
+	entry reflectcfg.Entry
+}
+
+func (m entryModule) ConfigPath() string          { return m.entry.Path }
+func (entryModule) LoadConfig(*koanf.Koanf) error { return nil }
+func (m entryModule) ConfigStruct() any           { return m.entry.Config }
+
+// parityProbe is a config document both schemas judge.
+type parityProbe struct {
+	name string
+	doc  map[string]any
+}
+
+// TestSchemaParity asserts the config module's schema validation and the JSON
+// Schema reflectcfg generates agree on every built-in module config: a config
+// setting every documented key, and that config with one key unknown or one
+// value of the wrong type. Fields of opaqueTypes are not compared.
+//
+//nolint:paralleltest // t.Chdir is incompatible with t.Parallel
+func TestSchemaParity(t *testing.T) {
+	chdirRepoRoot(t)
+
+	out := reflectcfg.Reflect(defaultEntries, nil)
+
+	var buf bytes.Buffer
+	testza.AssertNoError(t, reflectcfg.EncodeSchema(&buf, out, schemaID))
+	doc, err := jsonschema.UnmarshalJSON(&buf)
+	testza.AssertNoError(t, err)
+
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(jsonschema.Draft2020)
+	testza.AssertNoError(t, c.AddResource(schemaID, doc))
+	schema, err := c.Compile(schemaID)
+	testza.AssertNoError(t, err)
+
+	for i, module := range out.Modules {
+		entry := defaultEntries[i]
+		modules := []lakta.Configurable{entryModule{entry: entry}}
+
+		for _, probe := range parityProbes(module) {
+			k := koanf.New(".")
+			testza.AssertNoError(t, k.Set(entry.Path, probe.doc))
+
+			violations := config.ValidateSchema(k, modules)
+			jsonErr := schema.Validate(jsonValue(t, k.Raw()))
+
+			testza.AssertEqual(t, jsonErr == nil, len(violations) == 0,
+				fmt.Sprintf("%s %s: config violations %v, JSON Schema error %v", entry.Path, probe.name, violations, jsonErr))
+		}
+	}
+}
+
+// parityProbes returns a config setting every field of module, then that
+// config with one mutation each.
+func parityProbes(module reflectcfg.ModuleDoc) []parityProbe {
+	valid := func() map[string]any { return sampleObject(module.Fields) }
+
+	probes := []parityProbe{{name: "with every key", doc: valid()}}
+
+	unknown := valid()
+	unknown[parityKey] = 1
+	probes = append(probes, parityProbe{name: "with an unknown key", doc: unknown})
+
+	var walk func(fields []reflectcfg.FieldDoc, path []string)
+	walk = func(fields []reflectcfg.FieldDoc, path []string) {
+		for _, f := range fields {
+			if opaqueTypes[f.Type] {
+				continue
+			}
+			key := append(path, f.Key) //nolint:gocritic // a fresh path per field
+
+			// Env vars and flags only carry strings, so the config module
+			// reads a word as a one-item list and a scalar as any map value;
+			// a nested object fits neither a scalar nor a map of scalars.
+			wrong := map[string]any{"an object": map[string]any{parityKey: map[string]any{parityKey: 1}}}
+			if !strings.HasPrefix(f.Type, "[]") {
+				wrong["a word"] = "not-a-value"
+			}
+			for name, value := range wrong {
+				doc := valid()
+				setPath(doc, key, value)
+				probes = append(probes, parityProbe{name: strings.Join(key, ".") + " set to " + name, doc: doc})
+			}
+
+			if len(f.Fields) > 0 && !strings.HasPrefix(f.Type, "[]") && !strings.HasPrefix(f.Type, "map[") {
+				doc := valid()
+				setPath(doc, append(key, parityKey), 1)
+				probes = append(probes, parityProbe{name: strings.Join(key, ".") + " with an unknown key", doc: doc})
+
+				walk(f.Fields, key)
+			}
+		}
+	}
+	walk(module.Fields, nil)
+
+	return probes
+}
+
+// sampleObject is a value for every field in fields but those of opaqueTypes.
+func sampleObject(fields []reflectcfg.FieldDoc) map[string]any {
+	obj := make(map[string]any, len(fields))
+	for _, f := range fields {
+		if !opaqueTypes[f.Type] {
+			obj[f.Key] = sampleField(f)
+		}
+	}
+	return obj
+}
+
+// sampleField is a value f accepts.
+func sampleField(f reflectcfg.FieldDoc) any {
+	if len(f.Fields) > 0 {
+		obj := sampleObject(f.Fields)
+		switch {
+		case strings.HasPrefix(f.Type, "[]"):
+			return []any{obj}
+		case strings.HasPrefix(f.Type, "map["):
+			return map[string]any{"key": obj}
+		}
+		return obj
+	}
+
+	if f.Enum != "" {
+		first, _, _ := strings.Cut(f.Enum, ",")
+		return first
+	}
+
+	return sampleType(strings.TrimPrefix(f.Type, "*"))
+}
+
+// sampleType is a value of the Go type named t, as reflectcfg formats it.
+func sampleType(t string) any {
+	switch {
+	case t == "time.Duration":
+		return "30s"
+	case strings.HasPrefix(t, "map["):
+		_, elem, _ := strings.Cut(t, "]")
+		return map[string]any{"key": sampleType(strings.TrimPrefix(elem, "*"))}
+	case strings.HasPrefix(t, "[]"):
+		return []any{sampleType(strings.TrimPrefix(t[2:], "*"))}
+	case t == "bool":
+		return true
+	case strings.HasPrefix(t, "int"), strings.HasPrefix(t, "uint"):
+		return 1
+	case strings.HasPrefix(t, "float"):
+		return 1.5
+	default:
+		return "x"
+	}
+}
+
+// setPath sets the key path in doc, creating the objects along it.
+func setPath(doc map[string]any, path []string, value any) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := doc[key].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			doc[key] = next
+		}
+		doc = next
+	}
+	doc[path[len(path)-1]] = value
+}
+
+// jsonValue round-trips v through JSON, into the values the validator takes.
+func jsonValue(t *testing.T, v any) any {
+	t.Helper()
+
+	raw, err := json.Marshal(v)
+	testza.AssertNoError(t, err)
+	value, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	testza.AssertNoError(t, err)
+	return value
+}
@@ -4,6 +4,7 @@ go 1.26.4
 
 require (
 	github.com/MarvinJWendt/testza v0.5.2
+	github.com/Vilsol/lakta v0.4.1
 	github.com/Vilsol/lakta/pkg/auth/verifier v0.4.1
 	github.com/Vilsol/lakta/pkg/cache/memory v0.4.1
 	github.com/Vilsol/lakta/pkg/db/drivers/pgx v0.0.0-00010101000000-000000000000
@@ -21,6 +22,8 @@ require (
 	github.com/Vilsol/lakta/pkg/workers/pool v0.4.1
 	github.com/Vilsol/lakta/pkg/workers/scheduler v0.4.1
 	github.com/Vilsol/lakta/pkg/workflows/temporal v0.0.0-00010101000000-000000000000
+	github.com/knadh/koanf/v2 v2.3.5
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.2
 	golang.org/x/mod v0.38.0
 )
 
@@ -67,7 +70,6 @@ require (
 	atomicgo.dev/cursor v0.2.0 // indirect
 	atomicgo.dev/keyboard v0.2.10 // indirect
 	atomicgo.dev/schedule v0.1.0 // indirect
-	github.com/Vilsol/lakta v0.4.1 // indirect
 	github.com/Vilsol/lakta/pkg/reflectcfg v0.0.0-00010101000000-000000000000
 	github.com/Vilsol/slox v0.1.0 // indirect
 	github.com/andybalholm/brotli v1.2.2 // indirect
@@ -105,7 +107,6 @@ require (
 	github.com/knadh/koanf/parsers/yaml v1.1.0 // indirect
 	github.com/knadh/koanf/providers/file v1.2.1 // indirect
 	github.com/knadh/koanf/providers/posflag v1.0.1 // indirect
-	github.com/knadh/koanf/v2 v2.3.5 // indirect
 	github.com/lithammer/fuzzysearch v1.1.8 // indirect
 	github.com/lmittmann/tint v1.2.0 // indirect
 	github.com/mattn/go-colorable v0.1.15 // indirect
@@ -49,8 +49,5 @@ func main() {
 		),
 	)
 
-	if err := runtime.Run(); err != nil {
-		os.Exit(1)
-		return
-	}
+	runtime.RunAndExit()
 }
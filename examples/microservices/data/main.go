@@ -58,10 +58,7 @@ func main() {
 		),
 	)
 
-	if err := runtime.Run(); err != nil {
-		os.Exit(1)
-		return
-	}
+	runtime.RunAndExit()
 }
 
 // runMigrate loads this service's pgx config from the standard config sources
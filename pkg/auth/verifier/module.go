@@ -27,6 +27,7 @@ const minRefreshInterval = 15 * time.Minute
 // Module provides a *Registry at modules.auth.verifier.<instance> via DI.
 type Module struct {
 	config   Config
+	koanf    *koanf.Koanf // the config last loaded; reloads are diffed against it
 	registry *Registry
 
 	cacheCancel context.CancelFunc
@@ -46,9 +47,15 @@ func (m *Module) ConfigPath() string {
 
 // LoadConfig loads configuration from koanf.
 func (m *Module) LoadConfig(k *koanf.Koanf) error {
+	m.koanf = k
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
 // Init builds the Registry from config and provides it into DI.
 func (m *Module) Init(ctx context.Context) error {
 	state, cancel, err := m.buildState(ctx, m.config)
@@ -222,42 +229,51 @@ func (m *Module) Dependencies() ([]reflect.Type, []reflect.Type) {
 	}
 }
 
-// OnReload rebuilds the verifiers only when issuers/audience/static_key (or the
-// claim paths) change; JWKS rotation is already automatic via the cache.
+// OnReload prepares and commits a reload on its own, keeping the previous
+// verifiers when the rebuild fails. The runtime uses PrepareReload instead.
 func (m *Module) OnReload(k *koanf.Koanf) {
+	prepared, err := m.PrepareReload(k)
+	if err != nil {
+		slox.Error(context.Background(), "auth: config reload failed; keeping previous verifiers", slog.Any("error", err))
+		return
+	}
+	if prepared.Commit != nil {
+		prepared.Commit()
+	}
+}
+
+// PrepareReload rebuilds the verifiers only when the reload changes a key of
+// the module's config; JWKS rotation is already automatic via the cache. The
+// commit swaps them in, the abort stops their JWKS refresh.
+func (m *Module) PrepareReload(k *koanf.Koanf) (lakta.PreparedReload, error) {
 	ctx := context.Background()
 
-	newCfg := m.config
-	if err := newCfg.LoadFromKoanf(k, m.ConfigPath()); err != nil {
-		slox.Error(ctx, "auth: config reload failed to unmarshal; keeping previous verifiers", slog.Any("error", err))
-		return
+	if len(config.DiffKoanf(m.koanf, k).Under(m.ConfigPath())) == 0 {
+		return lakta.PreparedReload{}, nil
 	}
 
-	if !relevantConfigChanged(m.config, newCfg) {
-		return
+	newCfg := m.config
+	if err := newCfg.LoadFromKoanf(k, m.ConfigPath()); err != nil {
+		return lakta.PreparedReload{}, oops.Wrapf(err, "auth: config reload failed to unmarshal")
 	}
 
 	state, cancel, err := m.buildState(ctx, newCfg)
 	if err != nil {
-		slox.Error(ctx, "auth: config reload failed to rebuild verifiers; keeping previous", slog.Any("error", err))
-		return
-	}
-
-	m.config = newCfg
-	m.registry.state.Store(state)
-	if m.cacheCancel != nil {
-		m.cacheCancel()
+		return lakta.PreparedReload{}, oops.Wrapf(err, "auth: config reload failed to rebuild verifiers")
 	}
-	m.cacheCancel = cancel
-}
 
-// relevantConfigChanged reports whether a reload touches fields that require
-// rebuilding the verifiers.
-func relevantConfigChanged(oldCfg, newCfg Config) bool {
-	return !reflect.DeepEqual(oldCfg.Issuers, newCfg.Issuers) ||
-		!reflect.DeepEqual(oldCfg.StaticKey, newCfg.StaticKey) ||
-		oldCfg.ScopeClaim != newCfg.ScopeClaim ||
-		oldCfg.RolesClaim != newCfg.RolesClaim
+	return lakta.PreparedReload{
+		Commit: func() {
+			m.config = newCfg
+			m.koanf = k
+			m.registry.state.Store(state)
+			if m.cacheCancel != nil {
+				m.cacheCancel()
+			}
+			m.cacheCancel = cancel
+		},
+		Abort: cancel,
+	}, nil
 }
 
 // Shutdown stops the background JWKS refresh.
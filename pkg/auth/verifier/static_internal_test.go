@@ -8,6 +8,7 @@ import (
 
 	"github.com/MarvinJWendt/testza"
 	"github.com/Vilsol/lakta/pkg/testkit"
+	"github.com/knadh/koanf/v2"
 	"github.com/lestrrat-go/jwx/v3/jwt"
 )
 
@@ -129,3 +130,32 @@ func TestInit_NoStaticKey_Succeeds(t *testing.T) {
 	testza.AssertNil(t, m.registry.state.Load().staticKey)
 	t.Cleanup(func() { _ = m.Shutdown(context.Background()) })
 }
+
+func TestPrepareReload_OnlyRebuildsOnOwnChanges(t *testing.T) {
+	t.Setenv(profileEnvVar, "dev")
+	h := testkit.NewHarness(t)
+
+	load := func(data map[string]any) *koanf.Koanf {
+		k := koanf.New(".")
+		for key, value := range data {
+			testza.AssertNoError(t, k.Set(key, value))
+		}
+		return k
+	}
+	const path = "modules.auth.verifier.default."
+
+	m := NewModule(WithName("default"))
+	testza.AssertNoError(t, m.LoadConfig(load(map[string]any{path + "static_key.secret": staticSecret, "other": 1})))
+	testza.AssertNoError(t, m.Init(h.Ctx()))
+	t.Cleanup(func() { _ = m.Shutdown(context.Background()) })
+
+	prepared, err := m.PrepareReload(load(map[string]any{path + "static_key.secret": staticSecret, "other": 2}))
+	testza.AssertNoError(t, err)
+	testza.AssertNil(t, prepared.Commit)
+
+	prepared, err = m.PrepareReload(load(map[string]any{path + "static_key.secret": staticSecret, path + "roles_claim": "groups"}))
+	testza.AssertNoError(t, err)
+	testza.AssertNotNil(t, prepared.Commit)
+	prepared.Commit()
+	testza.AssertEqual(t, "groups", m.registry.state.Load().rolesClaim)
+}
@@ -52,6 +52,11 @@ func (m *Module) LoadConfig(k *koanf.Koanf) error {
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
 // Init resolves the optional DI MeterProvider (noop when otel is absent), then
 // registers a Builder per MergedCaches() entry into a fresh *cache.Registry.
 // Otter needs concrete [K,V] at build time but config only knows sizing, so each
@@ -131,26 +136,75 @@ func (m *Module) Shutdown(_ context.Context) error {
 	return nil
 }
 
-// OnReload re-loads config then diffs MergedCaches() against the live caches:
-// a max_size-only change resizes live (no entry loss, no warning); a ttl/
-// ttl_access/record_stats change rebuilds the otter cache and logs a loud
-// warning that entries were dropped; added names register a lazy builder;
-// removed names stop and drop the cache.
+// OnReload prepares and commits a reload on its own, keeping the previous
+// caches when the prepare fails. The runtime uses PrepareReload instead.
 func (m *Module) OnReload(k *koanf.Koanf) {
-	ctx := m.reloadCtx
+	prepared, err := m.PrepareReload(k)
+	if err != nil {
+		slox.Error(m.reloadCtx, "failed to reload cache config", slog.Any("error", err))
+		return
+	}
+	prepared.Commit()
+}
 
+// PrepareReload re-loads config and builds the otter cache of every live
+// cache whose ttl/ttl_access/record_stats changed, so a build failure leaves
+// every cache as it was. The commit then diffs MergedCaches() against the
+// live caches: a max_size-only change resizes live (no entry loss, no
+// warning); a ttl/ttl_access/record_stats change swaps in the prebuilt cache
+// and logs a loud warning that entries were dropped; added names register a
+// lazy builder; removed names stop and drop the cache.
+func (m *Module) PrepareReload(k *koanf.Koanf) (lakta.PreparedReload, error) {
 	reloaded := NewDefaultConfig()
 	reloaded.Name = m.config.Name
 	reloaded.CodeCaches = m.config.CodeCaches
 
 	if err := reloaded.LoadFromKoanf(k, m.ConfigPath()); err != nil {
-		slox.Error(ctx, "failed to reload cache config", slog.Any("error", err))
-		return
+		return lakta.PreparedReload{}, oops.Wrapf(err, "failed to reload cache config")
 	}
 
-	m.config = reloaded
 	desired := reloaded.MergedCaches()
 
+	m.mu.Lock()
+	old := m.specs
+	live := maps.Clone(m.live)
+	m.mu.Unlock()
+
+	fresh := make(map[string]*otterBox)
+	discard := func() {
+		for _, b := range fresh {
+			b.stop()
+		}
+	}
+
+	for name, spec := range desired {
+		oldSpec, existed := old[name]
+		if _, built := live[name]; !built || !existed || oldSpec == spec || (onlyMaxSizeChanged(oldSpec, spec) && spec.MaxSize > 0) {
+			continue
+		}
+
+		b, err := buildOtter(name, spec, m.mp)
+		if err != nil {
+			discard()
+			return lakta.PreparedReload{}, oops.With("cache", name).Wrapf(err, "failed to rebuild cache %q", name)
+		}
+		fresh[name] = b
+	}
+
+	return lakta.PreparedReload{
+		Commit: func() { m.commitReload(reloaded, desired, fresh) },
+		Abort:  discard,
+	}, nil
+}
+
+// commitReload swaps in the config PrepareReload loaded and the caches it
+// built. A cache built between prepare and commit has no prebuilt
+// replacement, so it is rebuilt here.
+func (m *Module) commitReload(reloaded Config, desired map[string]Spec, fresh map[string]*otterBox) {
+	ctx := m.reloadCtx
+
+	m.config = reloaded
+
 	m.mu.Lock()
 	old := m.specs
 	m.specs = desired
@@ -192,7 +246,9 @@ func (m *Module) OnReload(k *koanf.Koanf) {
 			continue
 		}
 
-		if err := b.rebuild(spec, m.mp); err != nil {
+		if prebuilt, ok := fresh[name]; ok {
+			b.swap(prebuilt, spec)
+		} else if err := b.rebuild(spec, m.mp); err != nil {
 			slox.Error(ctx, "failed to rebuild cache on reload", slog.String("cache", name), slog.Any("error", err))
 			continue
 		}
@@ -284,6 +340,13 @@ func (b *otterBox) rebuild(spec Spec, mp otelmetric.MeterProvider) error {
 		return err
 	}
 
+	b.swap(fresh, spec)
+
+	return nil
+}
+
+// swap moves the otter cache of fresh into b and stops the one it replaces.
+func (b *otterBox) swap(fresh *otterBox, spec Spec) {
 	b.mu.Lock()
 	oldOC, oldReg := b.oc, b.sizeReg
 	b.oc, b.rec, b.sizeReg, b.spec = fresh.oc, fresh.rec, fresh.sizeReg, spec
@@ -293,8 +356,6 @@ func (b *otterBox) rebuild(spec Spec, mp otelmetric.MeterProvider) error {
 		_ = oldReg.Unregister()
 	}
 	oldOC.StopAllGoroutines()
-
-	return nil
 }
 
 // stop unregisters the size gauge and stops the cache's background goroutines.
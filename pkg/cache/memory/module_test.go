@@ -226,6 +226,32 @@ func TestReload_TTLChangeRebuildsAndWarns(t *testing.T) {
 	testza.AssertTrue(t, spy.contains("dropped"))
 }
 
+func TestPrepareReload_AbortKeepsCaches(t *testing.T) {
+	t.Parallel()
+	reg, m, spy := setup(t, map[string]any{
+		prefix + "r.max_size": 100,
+		prefix + "r.ttl":      "1h",
+	})
+
+	c, err := cache.Named[string, int](reg, "r")
+	testza.AssertNoError(t, err)
+	c.Set("a", 1)
+
+	prepared, err := m.PrepareReload(loadKoanf(t, map[string]any{
+		prefix + "r.max_size": 100,
+		prefix + "r.ttl":      "2h",
+	}))
+	testza.AssertNoError(t, err)
+	prepared.Abort()
+
+	_, ok := c.Get("a")
+	testza.AssertTrue(t, ok) // aborted -> the live cache is untouched
+	testza.AssertFalse(t, spy.contains("dropped"))
+
+	_, err = m.PrepareReload(loadKoanf(t, map[string]any{prefix + "r.ttl": "soon"}))
+	testza.AssertNotNil(t, err)
+}
+
 func TestReload_MaxSizeChangeKeepsEntries(t *testing.T) {
 	t.Parallel()
 	reg, m, spy := setup(t, map[string]any{prefix + "r.max_size": 100})
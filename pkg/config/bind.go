@@ -137,6 +137,10 @@ func (m *bindModule[T]) LoadConfig(k *koanf.Koanf) error {
 	return nil
 }
 
+func (m *bindModule[T]) ConfigStruct() any {
+	return new(T)
+}
+
 // Get returns the cached config value from DI. Zero-alloc hot path.
 func Get[T any](ctx context.Context) *T {
 	return do.MustInvoke[*Binding[T]](lakta.GetInjector(ctx)).Get()
@@ -44,6 +44,19 @@ type Config struct {
 	// after the base config so its keys win. Empty disables the overlay.
 	// Defaults from the LAKTA_PROFILE env var.
 	Profile string
+
+	// Sources are config layers outside the files, env vars and flags, such
+	// as a KV store or an HTTP endpoint. Register them with WithSource.
+	Sources []RegisteredSource
+
+	// Resolvers resolve ${scheme:reference} secret references in config
+	// values, by scheme. Defaults to "file" and "env"; add more with
+	// WithResolver.
+	Resolvers map[string]Resolver
+
+	// SchemaMode sets how config keys that do not match the config structs of
+	// the modules in the runtime are handled. Defaults to SchemaWarn.
+	SchemaMode SchemaMode
 }
 
 // Option manipulates Config.
@@ -58,6 +71,7 @@ func NewDefaultConfig() Config {
 		Args:          nil,
 		DebounceDelay: defaultDebounceDelay,
 		Profile:       os.Getenv(envProfileVar),
+		Resolvers:     defaultResolvers(),
 	}
 }
 
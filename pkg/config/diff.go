@@ -0,0 +1,205 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Vilsol/slox"
+	"github.com/knadh/koanf/v2"
+)
+
+// ChangeKind says how a reload changed a key.
+type ChangeKind string
+
+const (
+	// ChangeAdded marks a key the reload added.
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved marks a key the reload removed.
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeModified marks a key whose value the reload changed.
+	ChangeModified ChangeKind = "changed"
+)
+
+// Change is one changed key of a reload. Lists are leaves: a changed item
+// changes the whole list.
+type Change struct {
+	Key  string     `json:"key"`
+	Kind ChangeKind `json:"kind"`
+	Old  any        `json:"old,omitempty"` // nil when added
+	New  any        `json:"new,omitempty"` // nil when removed
+}
+
+// Diff is the key-level difference between two configs, sorted by key.
+type Diff []Change
+
+// Under returns the changes of prefix and of the keys below it. An empty
+// prefix matches every key.
+func (d Diff) Under(prefix string) Diff {
+	if prefix == "" {
+		return d
+	}
+
+	var out Diff
+	for _, c := range d {
+		if c.Key == prefix || strings.HasPrefix(c.Key, prefix+".") {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Keys returns the changed keys.
+func (d Diff) Keys() []string {
+	keys := make([]string, len(d))
+	for i, c := range d {
+		keys[i] = c.Key
+	}
+	return keys
+}
+
+// ChangeLog records a committed reload, with secrets masked as in
+// RedactedProvenance: values resolved from a secret reference become
+// RedactMask too.
+type ChangeLog struct {
+	Time    time.Time      `json:"time"`
+	Source  string         `json:"source,omitempty"` // the source that triggered the reload; empty for files
+	Changes Diff           `json:"changes"`
+	Modules []ReloadResult `json:"modules,omitempty"` // the OnPrepare modules, with their phase timings
+}
+
+// changeSubscriber is a callback registered with OnChange.
+type changeSubscriber struct {
+	prefix string
+	fn     func(k *koanf.Koanf, diff Diff)
+}
+
+// OnChange registers a callback invoked after a reload that changes prefix or
+// a key below it, with only those changes. An empty prefix matches every key.
+// Callbacks run under the module's write lock, so they must not call back into the config module.
+func (m *Module) OnChange(prefix string, fn func(k *koanf.Koanf, diff Diff)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onChange = append(m.onChange, changeSubscriber{prefix: prefix, fn: fn})
+}
+
+// OnChangeLog registers a callback receiving the change log of every committed
+// reload, in commit order. Unlike other callbacks it runs after the write lock
+// is released, so it may read the config module.
+func (m *Module) OnChangeLog(fn func(ctx context.Context, entry ChangeLog)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onChangeLog = append(m.onChangeLog, fn)
+}
+
+// DiffKoanf returns the key-level difference from oldK to newK. A nil config
+// counts as empty. Modules that must decide during PrepareReload whether a
+// reload concerns them diff the config they last loaded against the new one.
+func DiffKoanf(oldK, newK *koanf.Koanf) Diff {
+	oldAll, newAll := flatten(oldK), flatten(newK)
+
+	var diff Diff
+	for key, oldValue := range oldAll {
+		newValue, ok := newAll[key]
+		switch {
+		case !ok:
+			diff = append(diff, Change{Key: key, Kind: ChangeRemoved, Old: oldValue})
+		case !reflect.DeepEqual(oldValue, newValue):
+			diff = append(diff, Change{Key: key, Kind: ChangeModified, Old: oldValue, New: newValue})
+		}
+	}
+	for key, newValue := range newAll {
+		if _, ok := oldAll[key]; !ok {
+			diff = append(diff, Change{Key: key, Kind: ChangeAdded, New: newValue})
+		}
+	}
+
+	slices.SortFunc(diff, func(a, b Change) int { return strings.Compare(a.Key, b.Key) })
+	return diff
+}
+
+// flatten returns the flattened keys of k, or nil when k is nil.
+func flatten(k *koanf.Koanf) map[string]any {
+	if k == nil {
+		return nil
+	}
+	return k.All()
+}
+
+// redactDiff masks the secrets of diff: values whose key is in either secret
+// reference set, and whatever redactLeaf masks.
+func redactDiff(diff Diff, oldSecrets, newSecrets map[string]any) Diff {
+	out := make(Diff, len(diff))
+	for i, c := range diff {
+		_, oldSecret := oldSecrets[c.Key]
+		_, newSecret := newSecrets[c.Key]
+		c.Old = redactChange(c.Key, c.Old, oldSecret)
+		c.New = redactChange(c.Key, c.New, newSecret)
+		out[i] = c
+	}
+	return out
+}
+
+func redactChange(key string, value any, secret bool) any {
+	switch {
+	case value == nil:
+		return nil
+	case secret:
+		return RedactMask
+	default:
+		return redactLeaf(key, value)
+	}
+}
+
+// notifyChange runs the OnChange callbacks whose prefix diff touches. Must
+// hold the write lock.
+func (m *Module) notifyChange(k *koanf.Koanf, diff Diff) {
+	for _, sub := range m.onChange {
+		if relevant := diff.Under(sub.prefix); len(relevant) > 0 {
+			m.safeCallback(func(k *koanf.Koanf) { sub.fn(k, relevant) }, k)
+		}
+	}
+}
+
+// publishChangeLog releases the write lock the caller holds, then logs entry
+// and hands it to the OnChangeLog callbacks. logMu, taken before the write
+// lock is released, keeps entries of concurrent reloads in commit order.
+func (m *Module) publishChangeLog(ctx context.Context, entry ChangeLog) {
+	sinks := slices.Clone(m.onChangeLog)
+
+	m.logMu.Lock()
+	defer m.logMu.Unlock()
+	m.mu.Unlock()
+
+	attrs := []any{slog.Int("changes", len(entry.Changes))}
+	if entry.Source != "" {
+		attrs = append(attrs, slog.String("source", entry.Source))
+	}
+	changes := make([]any, 0, len(entry.Changes))
+	for _, c := range entry.Changes {
+		group := []any{slog.String("kind", string(c.Kind))}
+		if c.Kind != ChangeAdded {
+			group = append(group, slog.Any("old", c.Old))
+		}
+		if c.Kind != ChangeRemoved {
+			group = append(group, slog.Any("new", c.New))
+		}
+		changes = append(changes, slog.Group(c.Key, group...))
+	}
+	attrs = append(attrs, slog.Group("diff", changes...), slog.Group("modules", reloadResultAttrs(entry.Modules)...))
+	slox.Info(ctx, "config reloaded", attrs...)
+
+	for _, fn := range sinks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					slox.Error(ctx, "config change log callback panicked", slog.Any("panic", r))
+				}
+			}()
+			fn(ctx, entry)
+		}()
+	}
+}
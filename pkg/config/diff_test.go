@@ -0,0 +1,103 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/Vilsol/slox"
+	"github.com/knadh/koanf/v2"
+)
+
+func TestDiffKoanf(t *testing.T) {
+	t.Parallel()
+
+	load := func(data map[string]any) *koanf.Koanf {
+		k := koanf.New(".")
+		for key, value := range data {
+			testza.AssertNoError(t, k.Set(key, value))
+		}
+		return k
+	}
+
+	diff := DiffKoanf(
+		load(map[string]any{"a.b": 1, "a.c": "x", "list": []any{1, 2}, "gone": true}),
+		load(map[string]any{"a.b": 1, "a.c": "y", "list": []any{1, 3}, "a.d.e": 2}),
+	)
+
+	testza.AssertEqual(t, Diff{
+		{Key: "a.c", Kind: ChangeModified, Old: "x", New: "y"},
+		{Key: "a.d.e", Kind: ChangeAdded, New: 2},
+		{Key: "gone", Kind: ChangeRemoved, Old: true},
+		{Key: "list", Kind: ChangeModified, Old: []any{1, 2}, New: []any{1, 3}},
+	}, diff)
+
+	testza.AssertEqual(t, []string{"a.c", "a.d.e"}, diff.Under("a").Keys())
+	testza.AssertEqual(t, []string{"a.d.e"}, diff.Under("a.d.e").Keys())
+	testza.AssertLen(t, diff.Under("a.d.e.f"), 0)
+	testza.AssertLen(t, diff.Under("li"), 0)
+	testza.AssertLen(t, diff.Under(""), 4)
+
+	testza.AssertEqual(t, Diff{{Key: "a", Kind: ChangeAdded, New: 1}}, DiffKoanf(nil, load(map[string]any{"a": 1})))
+}
+
+func TestOnChange_ReceivesPrefixDiff(t *testing.T) {
+	t.Parallel()
+
+	dir := writeSecretConfig(t, "app:\n  port: 80\n  host: a\nother: 1\n")
+
+	m := NewModule(WithConfigDirs(dir))
+	testza.AssertNil(t, m.Init(setupModuleCtx(t)))
+
+	var appDiffs, otherDiffs []Diff
+	m.OnChange("app", func(_ *koanf.Koanf, diff Diff) { appDiffs = append(appDiffs, diff) })
+	m.OnChange("other", func(_ *koanf.Koanf, diff Diff) { otherDiffs = append(otherDiffs, diff) })
+
+	testza.AssertNoError(t, os.WriteFile(filepath.Join(dir, "lakta.yaml"), []byte("app:\n  port: 81\n  host: a\nother: 1\n"), 0o600))
+	testza.AssertNoError(t, m.reload(t.Context()))
+
+	// Nothing changed: the reload commits, but no subscriber hears of it.
+	testza.AssertNoError(t, m.reload(t.Context()))
+
+	testza.AssertEqual(t, []Diff{{{Key: "app.port", Kind: ChangeModified, Old: 80, New: 81}}}, appDiffs)
+	testza.AssertLen(t, otherDiffs, 0)
+}
+
+func TestChangeLog_Redacted(t *testing.T) {
+	t.Setenv("LAKTATESTDIFF_PASS", "v1")
+
+	dir := writeSecretConfig(t, "db:\n  url: postgres://app:pw1@db/app\n  login: ${env:LAKTATESTDIFF_PASS}\n  token: t1\n")
+
+	m := NewModule(WithConfigDirs(dir))
+	testza.AssertNil(t, m.Init(setupModuleCtx(t)))
+
+	var entries []ChangeLog
+	m.OnChangeLog(func(_ context.Context, entry ChangeLog) {
+		// Runs outside the write lock, so the config module is readable.
+		testza.AssertEqual(t, "v2", m.Koanf().String("db.login"))
+		entries = append(entries, entry)
+	})
+
+	t.Setenv("LAKTATESTDIFF_PASS", "v2")
+	testza.AssertNoError(t, os.WriteFile(filepath.Join(dir, "lakta.yaml"),
+		[]byte("db:\n  url: postgres://app:pw2@db/app\n  login: ${env:LAKTATESTDIFF_PASS}\n  token: t2\n  pool: 5\n"), 0o600))
+	var logs bytes.Buffer
+	testza.AssertNoError(t, m.reload(slox.Into(t.Context(), slog.New(slog.NewTextHandler(&logs, nil)))))
+
+	testza.AssertLen(t, entries, 1)
+	testza.AssertFalse(t, entries[0].Time.IsZero())
+	testza.AssertEqual(t, Diff{
+		{Key: "db.login", Kind: ChangeModified, Old: RedactMask, New: RedactMask},
+		{Key: "db.pool", Kind: ChangeAdded, New: 5},
+		{Key: "db.token", Kind: ChangeModified, Old: RedactMask, New: RedactMask},
+		{Key: "db.url", Kind: ChangeModified, Old: "postgres://app:******@db/app", New: "postgres://app:******@db/app"},
+	}, entries[0].Changes)
+
+	// The entry is logged through the reload's context logger, masked too.
+	testza.AssertContains(t, logs.String(), "config reloaded")
+	testza.AssertNotContains(t, logs.String(), "pw2")
+}
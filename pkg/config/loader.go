@@ -40,8 +40,11 @@ func getSupportedFormats() []formatDef {
 //  3. env vars       LAKTA_*                      (loadEnvVars)
 //  4. CLI flags       --modules.…=…               (loadCLIFlags)
 //
+// Sources registered with WithSource slot in at their Precedence: BelowFiles
+// before 1, AboveFiles after 2, AboveEnv after 3 and AboveFlags after 4.
+//
 // Init loads files -> env -> flags in that order; the profile overlay only
-// extends the file list, so reload() replays it for free and the watcher (which
+// extends the file list, so reload replays it for free and the watcher (which
 // watches every discovered file) needs no change.
 func (m *Module) discoverConfigFiles() []configFile {
 	var files []configFile
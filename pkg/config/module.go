@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Vilsol/lakta/pkg/lakta"
 	"github.com/knadh/koanf/providers/env/v2"
@@ -34,40 +35,116 @@ type Module struct {
 	koanf          *koanf.Koanf
 	mu             sync.RWMutex
 	configFiles    []configFile
+	sources        []*sourceLayer
+	secrets        resolvedSecrets
+	secretDirs     map[string]struct{} // secret file directories the watcher watches
+	watcher        fileWatcher         // nil unless watching
+	schemas        moduleSchemas       // nil until SetConfigModules
 	flagSet        *pflag.FlagSet
 	onReload       []func(k *koanf.Koanf)
+	onChange       []changeSubscriber
 	onValidate     []func(k *koanf.Koanf) error
+	onPrepare      []preparer
+	onChangeLog    []func(ctx context.Context, entry ChangeLog)
+	logMu          sync.Mutex // keeps change logs in commit order once mu is released
 	watcherFactory func() (fileWatcher, error)
+	preloaded      bool // LoadConfigSource ran; the next Init skips loading
 }
 
 // NewModule creates a new config module.
 func NewModule(options ...Option) *Module {
+	cfg := NewConfig(options...)
+
 	return &Module{
-		config:         NewConfig(options...),
+		config:         cfg,
 		koanf:          koanf.New("."),
+		sources:        newSourceLayers(cfg.Sources),
+		secretDirs:     make(map[string]struct{}),
 		watcherFactory: defaultWatcherFactory,
 	}
 }
 
-// Init initializes the config module, loading configuration from files, env vars, and CLI flags.
+// LoadConfigSource loads configuration from files, env vars, CLI flags and
+// sources ahead of Init, so the runtime can evaluate module conditions before
+// sorting. The following Init reuses the loaded tree.
+func (m *Module) LoadConfigSource(ctx context.Context) (*koanf.Koanf, error) {
+	if !m.preloaded {
+		if err := m.load(ctx); err != nil {
+			return nil, err
+		}
+		m.preloaded = true
+	}
+
+	return m.koanf, nil
+}
+
+// Init initializes the config module, loading configuration from files, env
+// vars, CLI flags and sources unless LoadConfigSource already did, and checks
+// it against the module schema. Files and sources are watched for changes
+// until ctx ends.
 func (m *Module) Init(ctx context.Context) error {
+	if !m.preloaded {
+		if err := m.load(ctx); err != nil {
+			return err
+		}
+	}
+	m.preloaded = false
+
+	if err := m.checkSchema(ctx, m.koanf, m.secrets.refs, nil, nil); err != nil {
+		return err
+	}
+
+	m.startWatcher(ctx)
+	m.startSourceWatchers(ctx)
+
+	lakta.ProvideValue(ctx, m.koanf)
+	lakta.ProvideValue[ReloadNotifier](ctx, m)
+	lakta.ProvideValue(ctx, m)
+
+	return nil
+}
+
+// load populates m.koanf from files, env vars, and CLI flags, in that order,
+// with each source merged in at its precedence, then resolves secret
+// references.
+func (m *Module) load(ctx context.Context) error {
+	if err := m.loadSources(ctx); err != nil {
+		return err
+	}
+
+	if err := m.mergeSources(m.koanf, BelowFiles, nil, nil); err != nil {
+		return err
+	}
+
 	if err := m.loadConfigFiles(m.koanf); err != nil {
 		return oops.Wrapf(err, "failed to load config files")
 	}
 
+	if err := m.mergeSources(m.koanf, AboveFiles, nil, nil); err != nil {
+		return err
+	}
+
 	if err := m.loadEnvVars(); err != nil {
 		return oops.Wrapf(err, "failed to load environment variables")
 	}
 
+	if err := m.mergeSources(m.koanf, AboveEnv, nil, nil); err != nil {
+		return err
+	}
+
 	if err := m.loadCLIFlags(); err != nil {
 		return oops.Wrapf(err, "failed to load CLI flags")
 	}
 
-	m.startWatcher(ctx)
+	if err := m.mergeSources(m.koanf, AboveFlags, nil, nil); err != nil {
+		return err
+	}
 
-	lakta.ProvideValue(ctx, m.koanf)
-	lakta.ProvideValue[ReloadNotifier](ctx, m)
-	lakta.ProvideValue(ctx, m)
+	resolved, err := m.resolveSecrets(ctx, m.koanf, nil, nil)
+	if err != nil {
+		return err
+	}
+	m.secrets = resolved
 
 	return nil
 }
@@ -183,46 +260,98 @@ func (m *Module) OnValidate(fn func(k *koanf.Koanf) error) {
 	m.onValidate = append(m.onValidate, fn)
 }
 
-func (m *Module) reload() error {
+func (m *Module) reload(ctx context.Context) error {
 	m.mu.Lock()
-	defer m.mu.Unlock()
 
+	entry, err := m.rebuild(ctx, nil, nil)
+	if err != nil {
+		m.mu.Unlock()
+		return err
+	}
+
+	m.publishChangeLog(ctx, entry)
+
+	return nil
+}
+
+// rebuild replays every layer into a new koanf, with data in place of the
+// config of the pending source layer if one is set, resolves its secret
+// references and swaps it in once the module schema and the validators accept
+// it and every OnPrepare phase succeeds. Returns the change log of the swap
+// for publishChangeLog. Must hold the write lock.
+func (m *Module) rebuild(ctx context.Context, pending *sourceLayer, data map[string]any) (ChangeLog, error) {
 	newKoanf := koanf.New(".")
 
+	if err := m.mergeSources(newKoanf, BelowFiles, pending, data); err != nil {
+		return ChangeLog{}, err
+	}
+
 	for _, cf := range m.configFiles {
 		if err := newKoanf.Load(file.Provider(cf.path), cf.parser); err != nil {
-			return oops.Wrapf(err, "failed to reload config file: %s", cf.path)
+			return ChangeLog{}, oops.Wrapf(err, "failed to reload config file: %s", cf.path)
 		}
 	}
 
+	if err := m.mergeSources(newKoanf, AboveFiles, pending, data); err != nil {
+		return ChangeLog{}, err
+	}
+
 	if err := newKoanf.Load(env.Provider(".", env.Opt{
 		Prefix: m.config.EnvPrefix,
 		TransformFunc: func(k, v string) (string, any) {
 			return envKeyTransform(m.config.EnvPrefix, k), v
 		},
 	}), nil); err != nil {
-		return oops.Wrapf(err, "failed to reload env vars")
+		return ChangeLog{}, oops.Wrapf(err, "failed to reload env vars")
+	}
+
+	if err := m.mergeSources(newKoanf, AboveEnv, pending, data); err != nil {
+		return ChangeLog{}, err
 	}
 
 	if m.flagSet != nil {
 		if err := newKoanf.Load(posflag.Provider(m.flagSet, ".", newKoanf), nil); err != nil {
-			return oops.Wrapf(err, "failed to reload CLI flags")
+			return ChangeLog{}, oops.Wrapf(err, "failed to reload CLI flags")
 		}
 	}
 
+	if err := m.mergeSources(newKoanf, AboveFlags, pending, data); err != nil {
+		return ChangeLog{}, err
+	}
+
+	resolved, err := m.resolveSecrets(ctx, newKoanf, pending, data)
+	if err != nil {
+		return ChangeLog{}, err
+	}
+
+	if err := m.checkSchema(ctx, newKoanf, resolved.refs, pending, data); err != nil {
+		return ChangeLog{}, oops.Wrapf(err, "config reload rejected by schema")
+	}
+
 	for _, validate := range m.onValidate {
 		if err := validate(newKoanf); err != nil {
-			return oops.Wrapf(err, "config reload rejected by validator")
+			return ChangeLog{}, oops.Wrapf(err, "config reload rejected by validator")
 		}
 	}
 
+	prepared, results, err := m.prepareReload(newKoanf)
+	if err != nil {
+		return ChangeLog{}, oops.Wrapf(err, "config reload rolled back")
+	}
+
+	diff := DiffKoanf(m.koanf, newKoanf)
+	entry := ChangeLog{Time: time.Now(), Changes: redactDiff(diff, m.secrets.refs, resolved.refs), Modules: results}
+
 	m.koanf = newKoanf
+	m.commitSecrets(resolved)
+	commitReload(prepared, results)
 
 	for _, fn := range m.onReload {
 		m.safeCallback(fn, newKoanf)
 	}
+	m.notifyChange(newKoanf, diff)
 
-	return nil
+	return entry, nil
 }
 
 // safeCallback runs a reload callback, isolating panics so one bad module does
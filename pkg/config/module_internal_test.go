@@ -27,13 +27,13 @@ func TestReload_ValidatorVetoKeepsOldConfig(t *testing.T) {
 
 	m := NewModule()
 	m.configFiles = []configFile{{path: path, parser: yaml.Parser()}}
-	testza.AssertNoError(t, m.reload())
+	testza.AssertNoError(t, m.reload(t.Context()))
 	testza.AssertEqual(t, "original", m.Koanf().String("foo"))
 
 	writeReloadFile(t, path, "foo: changed\n")
 	m.OnValidate(func(*koanf.Koanf) error { return errors.New("veto") })
 
-	err := m.reload()
+	err := m.reload(t.Context())
 	testza.AssertNotNil(t, err)
 	testza.AssertEqual(t, "original", m.Koanf().String("foo")) // unchanged after veto
 }
@@ -47,13 +47,13 @@ func TestReload_CallbackPanicIsolated(t *testing.T) {
 
 	m := NewModule()
 	m.configFiles = []configFile{{path: path, parser: yaml.Parser()}}
-	testza.AssertNoError(t, m.reload())
+	testza.AssertNoError(t, m.reload(t.Context()))
 
 	ran := false
 	m.OnReload(func(*koanf.Koanf) { panic("callback boom") })
 	m.OnReload(func(*koanf.Koanf) { ran = true })
 
 	writeReloadFile(t, path, "foo: v2\n")
-	testza.AssertNoError(t, m.reload())
+	testza.AssertNoError(t, m.reload(t.Context()))
 	testza.AssertTrue(t, ran, "later callback must still run after an earlier one panics")
 }
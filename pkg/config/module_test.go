@@ -14,8 +14,9 @@ import (
 )
 
 var (
-	_ lakta.Module   = (*Module)(nil)
-	_ lakta.Provider = (*Module)(nil)
+	_ lakta.Module       = (*Module)(nil)
+	_ lakta.Provider     = (*Module)(nil)
+	_ lakta.ConfigSource = (*Module)(nil)
 )
 
 func setupModuleCtx(t *testing.T) context.Context {
@@ -50,6 +51,25 @@ func TestConfigModule_ProvidesReloadNotifier(t *testing.T) {
 	testza.AssertNotNil(t, n)
 }
 
+func TestConfigModule_LoadConfigSourceBeforeInit(t *testing.T) {
+	// Not parallel — t.Setenv requires sequential execution.
+	t.Setenv("LAKTATESTSRC_FOO", "bar")
+
+	ctx := setupModuleCtx(t)
+	m := NewModule(WithConfigDirs("/nonexistent"), WithEnvPrefix("LAKTATESTSRC_"))
+
+	preloaded, err := m.LoadConfigSource(ctx)
+	testza.AssertNil(t, err)
+	testza.AssertEqual(t, "bar", preloaded.String("foo"))
+
+	testza.AssertNil(t, m.Init(ctx))
+
+	k, err := do.Invoke[*koanf.Koanf](lakta.GetInjector(ctx))
+	testza.AssertNil(t, err)
+	testza.AssertEqual(t, preloaded, k)
+	testza.AssertEqual(t, "bar", k.String("foo"))
+}
+
 func TestConfigModule_EnvVarOverride(t *testing.T) {
 	// Not parallel — t.Setenv requires sequential execution.
 	t.Setenv("LAKTATEST_FOO", "bar")
@@ -122,7 +142,7 @@ func TestConfigModule_ReloadFiresCallbacks(t *testing.T) {
 		received = k
 	})
 
-	testza.AssertNil(t, m.reload())
+	testza.AssertNil(t, m.reload(t.Context()))
 	testza.AssertNotNil(t, received)
 }
 
@@ -135,7 +155,7 @@ func TestConfigModule_ReloadUpdatesKoanf(t *testing.T) {
 	testza.AssertNil(t, m.Init(ctx))
 
 	original := m.Koanf()
-	testza.AssertNil(t, m.reload())
+	testza.AssertNil(t, m.reload(t.Context()))
 	reloaded := m.Koanf()
 
 	testza.AssertFalse(t, original == reloaded)
@@ -289,7 +309,7 @@ func TestConfigModule_Reload_FileGoneReturnsError(t *testing.T) {
 	testza.AssertNil(t, m.Init(ctx))
 
 	testza.AssertNil(t, os.Remove(cfgPath))
-	testza.AssertNotNil(t, m.reload())
+	testza.AssertNotNil(t, m.reload(t.Context()))
 }
 
 func TestConfigModule_Reload_WithCLIFlags(t *testing.T) {
@@ -303,7 +323,7 @@ func TestConfigModule_Reload_WithCLIFlags(t *testing.T) {
 		WithArgs([]string{"--key=override"}),
 	)
 	testza.AssertNil(t, m.Init(ctx))
-	testza.AssertNil(t, m.reload())
+	testza.AssertNil(t, m.reload(t.Context()))
 
 	testza.AssertEqual(t, "override", m.Koanf().String("key"))
 }
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// yamlDocument is a parsed config file, for finding the line of a key.
+type yamlDocument struct {
+	root *yaml.Node // nil when the file has no lines to offer
+}
+
+// parseYAMLDocument parses the YAML or JSON config file at path. Other
+// formats, and files that fail to parse, yield a document without lines.
+func parseYAMLDocument(path string) *yamlDocument {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return &yamlDocument{}
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // a discovered config file
+	if err != nil {
+		return &yamlDocument{}
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 {
+		return &yamlDocument{}
+	}
+
+	return &yamlDocument{root: root.Content[0]}
+}
+
+// line returns the line of key, a dotted path whose list items are addressed
+// as key[index], or of the deepest part of it the document holds. Zero when
+// the document holds none of it.
+func (d *yamlDocument) line(key string) int {
+	if d.root == nil {
+		return 0
+	}
+
+	node, line := d.root, 0
+	for segment := range strings.SplitSeq(key, ".") {
+		name, indexes, _ := strings.Cut(segment, "[")
+
+		value, keyLine := mappingValue(node, name)
+		if value == nil {
+			return line
+		}
+		node, line = value, keyLine
+
+		for indexes != "" {
+			index, rest, _ := strings.Cut(indexes, "]")
+			indexes = strings.TrimPrefix(rest, "[")
+
+			i, err := strconv.Atoi(index)
+			if err != nil || node.Kind != yaml.SequenceNode || i >= len(node.Content) {
+				return line
+			}
+			node, line = node.Content[i], node.Content[i].Line
+		}
+	}
+
+	return line
+}
+
+// mappingValue returns the value of name in the mapping node and the line of
+// its key; nil when node is not a mapping holding name.
+func mappingValue(node *yaml.Node, name string) (*yaml.Node, int) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, 0
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			return node.Content[i+1], node.Content[i].Line
+		}
+	}
+
+	return nil, 0
+}
@@ -145,6 +145,6 @@ func TestProfile_HotReloadOverlay(t *testing.T) {
 
 	// Mutate the overlay and reload -> new profile value propagates.
 	writeFile(t, dir, "lakta.prod.yaml", "shared: prod2\n")
-	testza.AssertNil(t, m.reload())
+	testza.AssertNil(t, m.reload(t.Context()))
 	testza.AssertEqual(t, "prod2", m.Koanf().String("shared"))
 }
@@ -14,27 +14,69 @@ const (
 	OriginFile    = "file"
 	OriginEnv     = "env"
 	OriginFlag    = "flag"
+	OriginSource  = "source"
 	OriginDefault = "default"
 )
 
 // ProvenanceEntry attributes a config key to the highest layer that set it.
 type ProvenanceEntry struct {
 	Key    string `json:"key"`
-	Origin string `json:"origin"` // file|env|flag|default
-	Value  any    `json:"value"`  // pre-redaction; caller redacts before display
+	Origin string `json:"origin"`           // file|env|flag|source|default
+	Source string `json:"source,omitempty"` // Source.Name() when Origin is "source"
+	File   string `json:"file,omitempty"`   // the config file setting Key when Origin is "file"
+	Secret bool   `json:"secret,omitempty"` // Value is the secret reference, not the resolved secret
+	Value  any    `json:"value"`            // pre-redaction; caller redacts before display
+}
+
+// provenanceLayer is one layer replayed by ProvenanceSnapshot.
+type provenanceLayer struct {
+	origin   string
+	source   string
+	file     string
+	k        *koanf.Koanf
+	resolves bool // the secret references the layer sets are resolved
 }
 
 // ProvenanceSnapshot reconstructs per-key origin by replaying the module's
-// layers (files -> env -> flag) into throwaway koanf instances and attributing
-// each key to the highest layer containing it; keys present in none are
-// "default". koanf has no native per-key origin tracking. Read under RLock.
+// layers (files -> env -> flag, with sources at their precedence) into
+// throwaway koanf instances and attributing each key to the highest layer
+// containing it; keys present in none are "default". A value resolved from a
+// secret reference is reported as the reference. koanf has no native per-key
+// origin tracking. Read under RLock.
 func (m *Module) ProvenanceSnapshot() []ProvenanceEntry {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	fileK := koanf.New(".")
+	layers := m.provenanceLayers(nil, nil)
+
+	all := m.koanf.All()
+	entries := make([]ProvenanceEntry, 0, len(all))
+	for key, val := range all {
+		entry := ProvenanceEntry{Key: key, Origin: OriginDefault, Value: val}
+		if ref, ok := m.secrets.refs[key]; ok {
+			entry.Value = ref
+			entry.Secret = true
+		}
+		if layer := topLayer(layers, key); layer != nil {
+			entry.Origin, entry.Source, entry.File = layer.origin, layer.source, layer.file
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	return entries
+}
+
+// provenanceLayers replays every layer of the config, lowest first, with data
+// in place of the committed config of pending, if set. Each config file is a
+// layer of its own. Must hold the lock.
+func (m *Module) provenanceLayers(pending *sourceLayer, data map[string]any) []provenanceLayer {
+	var fileLayers []provenanceLayer
 	for _, cf := range m.configFiles {
+		fileK := koanf.New(".")
 		_ = fileK.Load(file.Provider(cf.path), cf.parser)
+		fileLayers = append(fileLayers, provenanceLayer{origin: OriginFile, file: cf.path, k: fileK, resolves: true})
 	}
 
 	envK := koanf.New(".")
@@ -56,30 +98,47 @@ func (m *Module) ProvenanceSnapshot() []ProvenanceEntry {
 		})
 	}
 
-	all := m.koanf.All()
-	entries := make([]ProvenanceEntry, 0, len(all))
-	for key, val := range all {
-		entries = append(entries, ProvenanceEntry{
-			Key:    key,
-			Origin: originOf(key, fileK, envK, flagK),
-			Value:  val,
-		})
-	}
+	layers := m.sourceProvenance(BelowFiles, pending, data)
+	layers = append(layers, fileLayers...)
+	layers = append(layers, m.sourceProvenance(AboveFiles, pending, data)...)
+	layers = append(layers, provenanceLayer{origin: OriginEnv, k: envK, resolves: true})
+	layers = append(layers, m.sourceProvenance(AboveEnv, pending, data)...)
+	layers = append(layers, provenanceLayer{origin: OriginFlag, k: flagK, resolves: true})
+	layers = append(layers, m.sourceProvenance(AboveFlags, pending, data)...)
 
-	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return layers
+}
 
-	return entries
+// topLayer returns the highest of layers containing key, nil if none does.
+func topLayer(layers []provenanceLayer, key string) *provenanceLayer {
+	for i := len(layers) - 1; i >= 0; i-- {
+		if layers[i].k.Exists(key) {
+			return &layers[i]
+		}
+	}
+	return nil
 }
 
-func originOf(key string, fileK, envK, flagK *koanf.Koanf) string {
-	switch {
-	case flagK.Exists(key):
-		return OriginFlag
-	case envK.Exists(key):
-		return OriginEnv
-	case fileK.Exists(key):
-		return OriginFile
-	default:
-		return OriginDefault
+// sourceProvenance returns a layer for every source at precedence, with data
+// in place of the committed config of pending, if set.
+func (m *Module) sourceProvenance(precedence Precedence, pending *sourceLayer, data map[string]any) []provenanceLayer {
+	var layers []provenanceLayer
+	for _, layer := range m.sources {
+		if layer.Precedence != precedence {
+			continue
+		}
+
+		layerData := layer.data
+		if layer == pending {
+			layerData = data
+		}
+
+		k, err := sourceKoanf(layerData)
+		if err != nil {
+			continue
+		}
+
+		layers = append(layers, provenanceLayer{origin: OriginSource, source: layer.Source.Name(), k: k, resolves: layer.ResolveSecrets})
 	}
+	return layers
 }
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SecretKeyPattern is the regular expression alternation matching config key
+// segments whose values are secrets. Matching is case-insensitive; the
+// actuator extends it with its redact_patterns.
+const SecretKeyPattern = `password|secret|token|key|credential|apikey|dsn|conn.*string`
+
+// RedactMask replaces redacted values.
+const RedactMask = "******"
+
+// provenanceSection names the crash report section of RedactedProvenance.
+const provenanceSection = "config_provenance"
+
+var (
+	secretKeyPattern = regexp.MustCompile(`(?i)(` + SecretKeyPattern + `)`)
+
+	// Value-level scrubbers catch secrets embedded in string values under
+	// innocuous keys (url, addr): URI userinfo and DSN/query password-style
+	// parameters.
+	uriUserinfoPattern = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://[^:/?#@\s]+:)([^@\s]+)(@)`)
+	dsnParamPattern    = regexp.MustCompile(`(?i)((?:password|secret|token)=)([^&\s;]+)`)
+)
+
+// ScrubSecrets masks secrets embedded in a string value: URI userinfo
+// passwords and password, secret or token DSN/query parameters. Best-effort;
+// it cannot catch every embedded secret.
+func ScrubSecrets(s string) string {
+	s = uriUserinfoPattern.ReplaceAllString(s, `${1}`+RedactMask+`${3}`)
+	s = dsnParamPattern.ReplaceAllString(s, `${1}`+RedactMask)
+	return s
+}
+
+// RedactedProvenance is ProvenanceSnapshot with secrets masked: a key with a
+// segment matching SecretKeyPattern, or under a Passthrough ("raw") subtree,
+// has its value replaced by RedactMask, and every other string value goes
+// through ScrubSecrets.
+func (m *Module) RedactedProvenance() []ProvenanceEntry {
+	entries := m.ProvenanceSnapshot()
+	for i := range entries {
+		entries[i].Value = redactLeaf(entries[i].Key, entries[i].Value)
+	}
+	return entries
+}
+
+// CrashReportSection adds RedactedProvenance to runtime crash reports.
+func (m *Module) CrashReportSection() (string, any) {
+	return provenanceSection, m.RedactedProvenance()
+}
+
+// redactLeaf redacts the value of the flattened key.
+func redactLeaf(key string, value any) any {
+	for segment := range strings.SplitSeq(key, ".") {
+		if secretKeyPattern.MatchString(segment) || strings.EqualFold(segment, "raw") {
+			return RedactMask
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		return ScrubSecrets(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			if s, ok := item.(string); ok {
+				out[i] = ScrubSecrets(s)
+				continue
+			}
+			out[i] = item
+		}
+		return out
+	default:
+		return value
+	}
+}
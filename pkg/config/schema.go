@@ -0,0 +1,662 @@
+package config
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vilsol/lakta/pkg/lakta"
+	"github.com/Vilsol/slox"
+	"github.com/knadh/koanf/v2"
+	"github.com/samber/oops"
+)
+
+const (
+	// modulesKey is the root of the module config tree.
+	modulesKey = "modules"
+
+	// enabledKey is the key, relative to a module's config path, the runtime
+	// reads to skip the module.
+	enabledKey = "enabled"
+)
+
+// SchemaMode sets what the config module does with config keys that do not
+// match the config structs of the modules in the runtime.
+type SchemaMode int
+
+const (
+	// SchemaWarn logs every violation and keeps the config.
+	SchemaWarn SchemaMode = iota
+
+	// SchemaStrict fails Init, and rejects a reload, on any violation.
+	SchemaStrict
+
+	// SchemaOff skips schema validation.
+	SchemaOff
+)
+
+// WithSchemaMode sets how violations of the module schema are handled
+// (default: SchemaWarn).
+func WithSchemaMode(mode SchemaMode) Option {
+	return func(cfg *Config) {
+		cfg.SchemaMode = mode
+	}
+}
+
+// SchemaViolation is a config key that does not match the config struct of the
+// module reading it: an unknown key, a value of the wrong type or a value
+// outside an enum.
+type SchemaViolation struct {
+	Key     string `json:"key"` // a list item is addressed as key[index]
+	Problem string `json:"problem"`
+	Origin  string `json:"origin"`           // file|env|flag|source|default
+	Source  string `json:"source,omitempty"` // Source.Name() when Origin is "source"
+	File    string `json:"file,omitempty"`   // the config file setting Key when Origin is "file"
+	Line    int    `json:"line,omitempty"`   // Key's line in File; zero when unknown
+}
+
+// String renders the violation as "key: problem (where)".
+func (v SchemaViolation) String() string {
+	var where string
+	switch {
+	case v.File != "" && v.Line > 0:
+		where = v.File + ":" + strconv.Itoa(v.Line)
+	case v.File != "":
+		where = v.File
+	case v.Source != "":
+		where = v.Origin + " " + v.Source
+	default:
+		where = v.Origin
+	}
+
+	return v.Key + ": " + v.Problem + " (" + where + ")"
+}
+
+// SchemaError fails Init or a reload in SchemaStrict mode.
+type SchemaError struct {
+	Violations []SchemaViolation
+}
+
+func (e *SchemaError) Error() string {
+	problems := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		problems[i] = v.String()
+	}
+
+	return "config does not match the module schema: " + strings.Join(problems, "; ")
+}
+
+// SetConfigModules builds the schema Init and every reload validate the
+// config against from the config structs of modules. A module that does not
+// implement lakta.ConfigSchema accepts any keys under its ConfigPath. Without
+// a call, as outside a runtime, the config is not validated.
+func (m *Module) SetConfigModules(modules []lakta.Configurable) {
+	schemas := newModuleSchemas(modules)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.schemas = schemas
+}
+
+// ValidateSchema checks k against the config structs of modules as the config
+// module does once SetConfigModules handed it modules, for checking a config
+// file in a test or CI. Only Key and Problem of each violation are set.
+func ValidateSchema(k *koanf.Koanf, modules []lakta.Configurable) []SchemaViolation {
+	return newModuleSchemas(modules).violations(k, nil)
+}
+
+// checkSchema validates k against the module schema. In SchemaStrict mode a
+// violation fails; in SchemaWarn mode each is logged. secrets are the keys
+// resolved from secret references, whose values violations never show;
+// pending and data are as for rebuild.
+func (m *Module) checkSchema(ctx context.Context, k *koanf.Koanf, secrets map[string]any, pending *sourceLayer, data map[string]any) error {
+	if m.schemas == nil || m.config.SchemaMode == SchemaOff {
+		return nil
+	}
+
+	violations := m.schemas.violations(k, secrets)
+	if len(violations) == 0 {
+		return nil
+	}
+	m.locateViolations(violations, pending, data)
+
+	if m.config.SchemaMode == SchemaStrict {
+		return oops.Wrap(&SchemaError{Violations: violations})
+	}
+
+	for _, v := range violations {
+		slox.Warn(ctx, "config key does not match the module schema",
+			slog.String("key", v.Key),
+			slog.String("problem", v.Problem),
+			slog.String("origin", v.Origin),
+			slog.String("source", v.Source),
+			slog.String("file", v.File),
+			slog.Int("line", v.Line),
+		)
+	}
+
+	return nil
+}
+
+// moduleSchemas maps each module config path to its schema, nil for a module
+// without one.
+type moduleSchemas map[string]*schemaNode
+
+func newModuleSchemas(modules []lakta.Configurable) moduleSchemas {
+	schemas := make(moduleSchemas, len(modules))
+	for _, c := range modules {
+		var node *schemaNode
+		if s, ok := c.(lakta.ConfigSchema); ok {
+			node = moduleSchema(reflect.TypeOf(s.ConfigStruct()))
+		}
+		schemas[c.ConfigPath()] = node
+	}
+	return schemas
+}
+
+// violations checks every module's subtree of k against its schema, and
+// reports the unknown instances of the module types present. Sorted by key.
+func (s moduleSchemas) violations(k *koanf.Koanf, secrets map[string]any) []SchemaViolation {
+	check := &schemaCheck{secrets: secrets}
+
+	for path, node := range s {
+		if node != nil && k.Exists(path) {
+			node.validate(check, path, k.Get(path))
+		}
+	}
+
+	if k.Exists(modulesKey) {
+		s.checkModuleTree(check, modulesKey, k.Get(modulesKey))
+	}
+
+	sort.Slice(check.violations, func(i, j int) bool { return check.violations[i].Key < check.violations[j].Key })
+
+	return check.violations
+}
+
+// checkModuleTree reports the keys below prefix, a level of the modules tree
+// above the module config paths, that lead to no module. Only the levels
+// holding the instances of a module type present are checked: the categories
+// and types of other modules belong to the other runtimes sharing the config.
+func (s moduleSchemas) checkModuleTree(check *schemaCheck, prefix string, value any) {
+	if _, ok := s[prefix]; ok {
+		return
+	}
+
+	tree, ok := value.(map[string]any)
+	if !ok {
+		if value != nil {
+			check.report(prefix, "expected an object, got %s", check.show(prefix, value))
+		}
+		return
+	}
+
+	instances := s.holdsInstances(prefix)
+	for key, child := range tree {
+		path := prefix + "." + key
+		switch {
+		case s.leadsToModule(path):
+			s.checkModuleTree(check, path, child)
+		case instances:
+			check.report(path, "%s", unknownKey(key, s.moduleKeysBelow(prefix)))
+		}
+	}
+}
+
+// leadsToModule reports whether path is a module's config path or a parent of
+// one.
+func (s moduleSchemas) leadsToModule(path string) bool {
+	for modulePath := range s {
+		if modulePath == path || strings.HasPrefix(modulePath, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// holdsInstances reports whether path is the parent of a module's config
+// path, the level naming the instances of a module type.
+func (s moduleSchemas) holdsInstances(path string) bool {
+	for modulePath := range s {
+		if parent, _, ok := cutLast(modulePath); ok && parent == path {
+			return true
+		}
+	}
+	return false
+}
+
+// cutLast splits path around its last dot.
+func cutLast(path string) (string, string, bool) {
+	i := strings.LastIndexByte(path, '.')
+	if i < 0 {
+		return "", path, false
+	}
+	return path[:i], path[i+1:], true
+}
+
+// moduleKeysBelow returns the keys one level below prefix that lead to a
+// module, sorted.
+func (s moduleSchemas) moduleKeysBelow(prefix string) []string {
+	var keys []string
+	for modulePath := range s {
+		rest, ok := strings.CutPrefix(modulePath, prefix+".")
+		if !ok {
+			continue
+		}
+		key, _, _ := strings.Cut(rest, ".")
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// locateViolations attributes every violation to the highest layer setting its
+// key, and to the key's line when that layer is a YAML or JSON file.
+func (m *Module) locateViolations(violations []SchemaViolation, pending *sourceLayer, data map[string]any) {
+	layers := m.provenanceLayers(pending, data)
+	documents := make(map[string]*yamlDocument)
+
+	for i := range violations {
+		v := &violations[i]
+		key, _, _ := strings.Cut(v.Key, "[")
+
+		v.Origin = OriginDefault
+		layer := topLayer(layers, key)
+		if layer == nil {
+			continue
+		}
+
+		v.Origin, v.Source, v.File = layer.origin, layer.source, layer.file
+		if v.File == "" {
+			continue
+		}
+
+		doc, ok := documents[v.File]
+		if !ok {
+			doc = parseYAMLDocument(v.File)
+			documents[v.File] = doc
+		}
+		v.Line = doc.line(v.Key)
+	}
+}
+
+// schemaKind is the kind of value a schemaNode accepts.
+type schemaKind int
+
+const (
+	kindAny schemaKind = iota
+	kindObject
+	kindArray
+	kindString
+	kindInteger
+	kindNumber
+	kindBoolean
+	kindDuration
+)
+
+// schemaNode describes the values koanf can unmarshal into a Go type. Checks
+// follow koanf's weakly typed decoding: a string holding a number is a valid
+// integer, as env vars and flags only carry strings.
+type schemaNode struct {
+	kind   schemaKind
+	fields map[string]*schemaNode // struct keys; nil for a map
+	values *schemaNode            // map values and list items
+	open   bool                   // also accepts unknown keys, as Passthrough does
+	enum   []string
+}
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// moduleSchema describes a module's config struct, with the enabled key every
+// module accepts. Nil for a type that is not a struct.
+//
+// reflectcfg.BuildSchema cannot be reused here: it takes the output of
+// reflectcfg.Reflect, which runs `go list` to read doc comments from source,
+// and reflectcfg is its own module depending on yaml and x/mod, which the core
+// module must not import. cmd/docgen's schema parity test keeps the two in
+// agreement.
+func moduleSchema(t reflect.Type) *schemaNode {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	node := typeSchema(t, make(map[reflect.Type]bool))
+	if node.kind != kindObject {
+		return nil
+	}
+
+	node.fields[enabledKey] = &schemaNode{kind: kindBoolean}
+
+	return node
+}
+
+// typeSchema describes t. seen guards against recursive types.
+func typeSchema(t reflect.Type, seen map[reflect.Type]bool) *schemaNode {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == durationType:
+		return &schemaNode{kind: kindDuration}
+	case reflect.PointerTo(t).Implements(textUnmarshalerType):
+		return &schemaNode{kind: kindAny}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return structSchema(t, seen)
+	case reflect.Map:
+		return &schemaNode{kind: kindObject, values: typeSchema(t.Elem(), seen)}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &schemaNode{kind: kindString}
+		}
+		return &schemaNode{kind: kindArray, values: typeSchema(t.Elem(), seen)}
+	case reflect.Bool:
+		return &schemaNode{kind: kindBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &schemaNode{kind: kindInteger}
+	case reflect.Float32, reflect.Float64:
+		return &schemaNode{kind: kindNumber}
+	case reflect.String:
+		return &schemaNode{kind: kindString}
+	default:
+		return &schemaNode{kind: kindAny}
+	}
+}
+
+// structSchema describes the keys koanf unmarshals into a struct: the koanf
+// tag of each exported field, or the field name without one. A ",remain" field
+// opens the struct to unknown keys and embedded structs share its keys. A
+// struct with no such fields accepts anything.
+func structSchema(t reflect.Type, seen map[reflect.Type]bool) *schemaNode {
+	if seen[t] {
+		return &schemaNode{kind: kindAny}
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	node := &schemaNode{kind: kindObject, fields: make(map[string]*schemaNode)}
+	for f := range t.Fields() {
+		if !f.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(f.Tag.Get("koanf"), ",")
+		switch {
+		case name == "-":
+			continue
+		case slices.Contains(strings.Split(opts, ","), "remain"):
+			node.open = true
+			continue
+		case f.Anonymous && name == "" || slices.Contains(strings.Split(opts, ","), "squash"):
+			embedded := typeSchema(f.Type, seen)
+			if embedded.kind != kindObject || embedded.fields == nil {
+				node.open = true
+				continue
+			}
+			maps.Copy(node.fields, embedded.fields)
+			node.open = node.open || embedded.open
+			continue
+		case name == "":
+			name = f.Name
+		}
+
+		field := typeSchema(f.Type, seen)
+		if enum := f.Tag.Get("enum"); enum != "" {
+			field.enum = strings.Split(enum, ",")
+		}
+		node.fields[name] = field
+	}
+
+	if len(node.fields) == 0 && !node.open {
+		return &schemaNode{kind: kindAny}
+	}
+
+	return node
+}
+
+// field returns the node of the struct key name, matched case-insensitively
+// as koanf does when no key matches exactly.
+func (n *schemaNode) field(name string) *schemaNode {
+	if field, ok := n.fields[name]; ok {
+		return field
+	}
+	for key, field := range n.fields {
+		if strings.EqualFold(key, name) {
+			return field
+		}
+	}
+	return nil
+}
+
+// validate reports every way value, found at key, does not fit n.
+func (n *schemaNode) validate(check *schemaCheck, key string, value any) {
+	if value == nil {
+		return
+	}
+
+	switch n.kind {
+	case kindAny:
+		return
+	case kindObject:
+		n.validateObject(check, key, value)
+		return
+	case kindArray:
+		switch v := value.(type) {
+		case []any:
+			for i, item := range v {
+				n.values.validate(check, key+"["+strconv.Itoa(i)+"]", item)
+			}
+		case map[string]any:
+			check.report(key, "expected a list, got %s", check.show(key, value))
+		}
+		// A scalar is split or wrapped into a list when decoded.
+		return
+	case kindString, kindInteger, kindNumber, kindBoolean, kindDuration:
+	}
+
+	if !n.scalarFits(value) {
+		check.report(key, "expected %s, got %s", n.kindName(), check.show(key, value))
+		return
+	}
+
+	if len(n.enum) > 0 && !slices.Contains(n.enum, fmt.Sprint(value)) {
+		check.report(key, "must be one of %s, got %s", strings.Join(n.enum, ", "), check.show(key, value))
+	}
+}
+
+func (n *schemaNode) validateObject(check *schemaCheck, key string, value any) {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		check.report(key, "expected an object, got %s", check.show(key, value))
+		return
+	}
+
+	for name, child := range obj {
+		path := key + "." + name
+
+		if n.fields == nil {
+			n.values.validate(check, path, child)
+			continue
+		}
+
+		if field := n.field(name); field != nil {
+			field.validate(check, path, child)
+			continue
+		}
+
+		if !n.open {
+			check.report(path, "%s", unknownKey(name, slices.Sorted(maps.Keys(n.fields))))
+		}
+	}
+}
+
+// scalarFits reports whether koanf can decode value into n's scalar kind.
+func (n *schemaNode) scalarFits(value any) bool {
+	switch value.(type) {
+	case map[string]any, []any:
+		return false
+	}
+
+	s, isString := value.(string)
+	s = strings.TrimSpace(s)
+
+	switch n.kind {
+	case kindInteger:
+		if isString {
+			_, err := strconv.ParseInt(s, 0, 64)
+			return err == nil
+		}
+		f, ok := number(value)
+		return ok && f == math.Trunc(f)
+	case kindNumber:
+		if isString {
+			_, err := strconv.ParseFloat(s, 64)
+			return err == nil
+		}
+		_, ok := number(value)
+		return ok
+	case kindBoolean:
+		if isString {
+			_, err := strconv.ParseBool(s)
+			return err == nil
+		}
+		_, ok := value.(bool)
+		return ok
+	case kindDuration:
+		if isString {
+			_, err := time.ParseDuration(s)
+			return err == nil
+		}
+		f, ok := number(value)
+		return ok && f == math.Trunc(f)
+	case kindAny, kindObject, kindArray, kindString:
+	}
+
+	return true
+}
+
+func (n *schemaNode) kindName() string {
+	switch n.kind {
+	case kindInteger:
+		return "an integer"
+	case kindNumber:
+		return "a number"
+	case kindBoolean:
+		return "a boolean"
+	case kindDuration:
+		return "a duration such as 30s"
+	case kindAny, kindObject, kindArray, kindString:
+	}
+	return "a string"
+}
+
+// number converts a decoded numeric value to float64.
+func number(value any) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// schemaCheck collects the violations of one validation.
+type schemaCheck struct {
+	secrets    map[string]any
+	violations []SchemaViolation
+}
+
+func (c *schemaCheck) report(key, format string, args ...any) {
+	c.violations = append(c.violations, SchemaViolation{Key: key, Problem: fmt.Sprintf(format, args...)})
+}
+
+// show describes value for a violation at key, masking secrets like
+// RedactedProvenance does.
+func (c *schemaCheck) show(key string, value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "a list"
+	}
+
+	plain, _, _ := strings.Cut(key, "[")
+	if _, ok := c.secrets[plain]; ok {
+		return strconv.Quote(RedactMask)
+	}
+
+	if s, ok := redactLeaf(plain, value).(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(value)
+}
+
+// unknownKey describes an unknown key, suggesting the closest of the known
+// keys when one is a likely typo of it.
+func unknownKey(key string, known []string) string {
+	best, bestDistance := "", max(1, len(key)/4)+1
+	for _, candidate := range known {
+		if d := editDistance(key, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+
+	if best == "" {
+		return "unknown key"
+	}
+	return "unknown key, did you mean " + best + "?"
+}
+
+// editDistance is the optimal string alignment distance between a and b:
+// insertions, deletions, substitutions and adjacent transpositions.
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(a)][len(b)]
+}
@@ -0,0 +1,254 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/Vilsol/lakta/pkg/lakta"
+	"github.com/knadh/koanf/v2"
+)
+
+type schemaTestPool struct {
+	MaxOpenConns int           `koanf:"max_open_conns"`
+	IdleTimeout  time.Duration `koanf:"idle_timeout"`
+}
+
+type schemaTestReplica struct {
+	Host string `koanf:"host"`
+	Port int    `koanf:"port"`
+}
+
+type schemaTestConfig struct {
+	Name     string                `koanf:"-"`
+	LogLevel string                `koanf:"log_level" enum:"debug,info,warn,error"`
+	Pool     schemaTestPool        `koanf:"pool"`
+	Replicas []schemaTestReplica   `koanf:"replicas"`
+	Labels   map[string]int        `koanf:"labels"`
+	Password string                `koanf:"password"`
+	Raw      Passthrough[struct{}] `koanf:",remain"`
+}
+
+// schemaTestModule is a Configurable module without a ConfigStruct, so it
+// accepts any keys.
+type schemaTestModule struct {
+	path string
+}
+
+func (m *schemaTestModule) ConfigPath() string            { return m.path }
+func (m *schemaTestModule) LoadConfig(*koanf.Koanf) error { return nil }
+
+// describedModule is a schemaTestModule reading a schemaTestConfig.
+type describedModule struct{ schemaTestModule }
+
+func (m *describedModule) ConfigStruct() any { return &schemaTestConfig{} }
+
+func schemaModules() []lakta.Configurable {
+	return []lakta.Configurable{
+		&describedModule{schemaTestModule{path: "modules.db.fake.default"}},
+		&schemaTestModule{path: "modules.db.opaque.default"},
+		Bind[schemaTestPool]("app", "pool"),
+	}
+}
+
+const schemaTestYAML = `modules:
+  db:
+    fake:
+      default:
+        enabled: false
+        log_level: verbose
+        pool:
+          max_open_con: 10
+          idle_timeout: soon
+        replicas:
+          - host: a
+            port: 5432
+          - host: b
+            port: many
+        labels:
+          tier: gold
+        password: hunter2
+        anything: goes
+      defualt: {}
+    opaque:
+      default:
+        whatever: 1
+    fakr:
+      default: {}
+app:
+  pool:
+    max_open_conns: "25"
+unrelated:
+  key: kept
+`
+
+func TestSchema_Strict(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "lakta.yaml")
+	testza.AssertNoError(t, os.WriteFile(path, []byte(schemaTestYAML), 0o600))
+
+	m := NewModule(WithConfigDirs(dir), WithSchemaMode(SchemaStrict))
+	m.SetConfigModules(schemaModules())
+
+	err := m.Init(setupModuleCtx(t))
+	testza.AssertNotNil(t, err)
+
+	var schemaErr *SchemaError
+	testza.AssertTrue(t, errors.As(err, &schemaErr))
+
+	want := []SchemaViolation{
+		{Key: "modules.db.fake.default.labels.tier", Problem: `expected an integer, got "gold"`, Line: 16},
+		{Key: "modules.db.fake.default.log_level", Problem: `must be one of debug, info, warn, error, got "verbose"`, Line: 6},
+		{Key: "modules.db.fake.default.pool.idle_timeout", Problem: `expected a duration such as 30s, got "soon"`, Line: 9},
+		{Key: "modules.db.fake.default.pool.max_open_con", Problem: "unknown key, did you mean max_open_conns?", Line: 8},
+		{Key: "modules.db.fake.default.replicas[1].port", Problem: `expected an integer, got "many"`, Line: 14},
+		{Key: "modules.db.fake.defualt", Problem: "unknown key, did you mean default?", Line: 19},
+	}
+	for i := range want {
+		want[i].Origin = OriginFile
+		want[i].File = path
+	}
+	testza.AssertEqual(t, want, schemaErr.Violations)
+	testza.AssertContains(t, err.Error(), "modules.db.fake.defualt: unknown key, did you mean default? ("+path+":19)")
+}
+
+func TestSchema_WarnKeepsConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := writeSecretConfig(t, schemaTestYAML)
+
+	m := NewModule(WithConfigDirs(dir))
+	m.SetConfigModules(schemaModules())
+	testza.AssertNil(t, m.Init(setupModuleCtx(t)))
+	testza.AssertEqual(t, "verbose", m.Koanf().String("modules.db.fake.default.log_level"))
+
+	testza.AssertLen(t, m.schemas.violations(m.Koanf(), nil), 6)
+}
+
+func TestSchema_OffAndWithoutModules(t *testing.T) {
+	t.Parallel()
+
+	dir := writeSecretConfig(t, schemaTestYAML)
+
+	m := NewModule(WithConfigDirs(dir), WithSchemaMode(SchemaOff))
+	m.SetConfigModules(schemaModules())
+	testza.AssertNil(t, m.Init(setupModuleCtx(t)))
+
+	// Outside a runtime nothing hands the module a schema.
+	testza.AssertNil(t, NewModule(WithConfigDirs(dir), WithSchemaMode(SchemaStrict)).Init(setupModuleCtx(t)))
+}
+
+func TestSchema_EnvOriginAndSecrets(t *testing.T) {
+	t.Setenv("LAKTATESTSCHEMA_MODULES__DB__FAKE__DEFAULT__POOL__MAX_OPEN_CONNS", "lots")
+	t.Setenv("LAKTATESTSCHEMA_LEVEL", "loud")
+
+	dir := writeSecretConfig(t, "modules:\n  db:\n    fake:\n      default:\n        log_level: ${env:LAKTATESTSCHEMA_LEVEL}\n        password: 5\n")
+
+	m := NewModule(WithConfigDirs(dir), WithEnvPrefix("LAKTATESTSCHEMA_"), WithSchemaMode(SchemaStrict))
+	m.SetConfigModules(schemaModules())
+
+	var schemaErr *SchemaError
+	testza.AssertTrue(t, errors.As(m.Init(setupModuleCtx(t)), &schemaErr))
+	testza.AssertEqual(t, []SchemaViolation{
+		{
+			Key:     "modules.db.fake.default.log_level",
+			Problem: `must be one of debug, info, warn, error, got "******"`,
+			Origin:  OriginFile,
+			File:    filepath.Join(dir, "lakta.yaml"),
+			Line:    5,
+		},
+		{
+			Key:     "modules.db.fake.default.pool.max_open_conns",
+			Problem: `expected an integer, got "lots"`,
+			Origin:  OriginEnv,
+		},
+	}, schemaErr.Violations)
+}
+
+func TestSchema_StrictRejectsReload(t *testing.T) {
+	t.Parallel()
+
+	dir := writeSecretConfig(t, "modules:\n  db:\n    fake:\n      default:\n        pool:\n          max_open_conns: 5\n")
+
+	m := NewModule(WithConfigDirs(dir), WithSchemaMode(SchemaStrict))
+	m.SetConfigModules(schemaModules())
+	testza.AssertNil(t, m.Init(setupModuleCtx(t)))
+
+	testza.AssertNoError(t, os.WriteFile(filepath.Join(dir, "lakta.yaml"),
+		[]byte("modules:\n  db:\n    fake:\n      default:\n        pool:\n          max_open_cons: 10\n"), 0o600))
+
+	err := m.reload(t.Context())
+	testza.AssertNotNil(t, err)
+	testza.AssertContains(t, err.Error(), "max_open_cons: unknown key, did you mean max_open_conns?")
+	testza.AssertEqual(t, 5, m.Koanf().Int("modules.db.fake.default.pool.max_open_conns"))
+}
+
+func TestUnknownKey(t *testing.T) {
+	t.Parallel()
+
+	known := []string{"idle_timeout", "max_open_conns", "port"}
+
+	testza.AssertEqual(t, "unknown key, did you mean max_open_conns?", unknownKey("max_open_con", known))
+	testza.AssertEqual(t, "unknown key, did you mean port?", unknownKey("prot", known))
+	testza.AssertEqual(t, "unknown key", unknownKey("hostname", known))
+	testza.AssertEqual(t, "unknown key", unknownKey("x", nil))
+}
+
+func TestYAMLDocumentLine(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "lakta.json")
+	testza.AssertNoError(t, os.WriteFile(path, []byte(`{
+  "a": {
+    "list": [
+      {"b": 1},
+      {"b": 2}
+    ]
+  }
+}`), 0o600))
+
+	doc := parseYAMLDocument(path)
+	testza.AssertEqual(t, 2, doc.line("a"))
+	testza.AssertEqual(t, 5, doc.line("a.list[1].b"))
+	testza.AssertEqual(t, 3, doc.line("a.list[7]"))
+	testza.AssertEqual(t, 0, doc.line("missing"))
+	testza.AssertEqual(t, 0, parseYAMLDocument(filepath.Join(dir, "lakta.toml")).line("a"))
+}
+
+// TestSchema_SharedConfigFile boots the api service's modules of the
+// microservices example against the config file its services share: the
+// modules of the other services are not unknown keys.
+func TestSchema_SharedConfigFile(t *testing.T) {
+	t.Parallel()
+
+	var api []lakta.Configurable
+	for _, path := range []string{
+		"modules.logging.tint.default",
+		"modules.logging.slog.default",
+		"modules.otel.otel.default",
+		"modules.health.health.default",
+		"modules.grpc.client.data",
+		"modules.grpc.client.orchestrator",
+		"modules.http.fiber.default",
+	} {
+		api = append(api, &schemaTestModule{path: path})
+	}
+
+	m := NewModule(WithConfigDirs(filepath.Join("..", "..", "examples", "microservices")), WithSchemaMode(SchemaStrict))
+	m.SetConfigModules(api)
+	testza.AssertNil(t, m.Init(setupModuleCtx(t)))
+	testza.AssertTrue(t, m.Koanf().Exists("modules.grpc.server.default"))
+
+	// An instance of a module type the service runs must still match one.
+	k := m.Koanf().Copy()
+	testza.AssertNoError(t, k.Set("modules.grpc.client.dta.target", "localhost:50051"))
+	testza.AssertEqual(t, []SchemaViolation{
+		{Key: "modules.grpc.client.dta", Problem: "unknown key, did you mean data?"},
+	}, ValidateSchema(k, api))
+}
@@ -0,0 +1,253 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/samber/oops"
+)
+
+// Resolver resolves the reference of a ${scheme:reference} config value to the
+// secret it names. Register one per scheme with WithResolver.
+type Resolver interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// ResolverFunc adapts a function to a Resolver.
+type ResolverFunc func(ctx context.Context, ref string) (string, error)
+
+// Resolve calls f.
+func (f ResolverFunc) Resolve(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+// WithResolver registers the resolver for ${scheme:reference} config values,
+// such as a "vault" resolver reading ${vault:secret/db#password}. It replaces
+// any resolver already registered for scheme, including the built-in "file"
+// and "env" ones.
+func WithResolver(scheme string, resolver Resolver) Option {
+	return func(cfg *Config) {
+		cfg.Resolvers = maps.Clone(cfg.Resolvers)
+		if cfg.Resolvers == nil {
+			cfg.Resolvers = make(map[string]Resolver)
+		}
+		cfg.Resolvers[scheme] = resolver
+	}
+}
+
+// fileScheme is the scheme whose references name files; the module watches
+// them so a rotated secret reloads the config.
+const fileScheme = "file"
+
+// secretRefPattern matches a ${scheme:reference} secret reference.
+var secretRefPattern = regexp.MustCompile(`\$\{([a-z][a-z0-9+.-]*):([^}]+)\}`)
+
+// defaultResolvers returns the built-in resolvers: "file" reads a file, with
+// trailing newlines trimmed, and "env" reads an environment variable.
+func defaultResolvers() map[string]Resolver {
+	return map[string]Resolver{
+		fileScheme: ResolverFunc(func(_ context.Context, ref string) (string, error) {
+			data, err := os.ReadFile(ref) //nolint:gosec // reading the referenced file is the point
+			if err != nil {
+				return "", oops.Wrapf(err, "failed to read secret file")
+			}
+			return strings.TrimRight(string(data), "\r\n"), nil
+		}),
+		"env": ResolverFunc(func(_ context.Context, ref string) (string, error) {
+			value, ok := os.LookupEnv(ref)
+			if !ok {
+				return "", oops.Errorf("environment variable %s is not set", ref)
+			}
+			return value, nil
+		}),
+	}
+}
+
+// resolvedSecrets records what resolveSecrets replaced.
+type resolvedSecrets struct {
+	refs  map[string]any      // key -> the value before resolution
+	files map[string]struct{} // cleaned paths of ${file:...} references
+}
+
+// resolveSecrets replaces every secret reference in the string values, and
+// strings in list values, of k. A value may hold several references and text
+// around them, as in postgres://app:${env:DB_PASS}@db/app. A value set by a
+// source without WithSecretResolution stays as it is; pending and data are as
+// for rebuild. Fails on a scheme with no resolver or a reference its resolver
+// cannot resolve; the error names the key but never a value.
+func (m *Module) resolveSecrets(ctx context.Context, k *koanf.Koanf, pending *sourceLayer, data map[string]any) (resolvedSecrets, error) {
+	resolved := resolvedSecrets{refs: make(map[string]any), files: make(map[string]struct{})}
+
+	// Replayed only once a value holds a reference.
+	var layers []provenanceLayer
+	trusted := func(key string) bool {
+		if layers == nil {
+			layers = m.provenanceLayers(pending, data)
+		}
+		layer := topLayer(layers, key)
+		return layer == nil || layer.resolves
+	}
+
+	for key, value := range k.All() {
+		if !hasSecretRef(value) || !trusted(key) {
+			continue
+		}
+
+		var (
+			out any
+			ok  bool
+			err error
+		)
+
+		switch v := value.(type) {
+		case string:
+			out, ok, err = m.resolveString(ctx, v, resolved.files)
+		case []any:
+			list := slices.Clone(v)
+			for i, item := range list {
+				s, isString := item.(string)
+				if !isString {
+					continue
+				}
+
+				var itemOK bool
+				list[i], itemOK, err = m.resolveString(ctx, s, resolved.files)
+				if err != nil {
+					break
+				}
+				ok = ok || itemOK
+			}
+			out = list
+		}
+
+		if err != nil {
+			return resolvedSecrets{}, oops.With("key", key).Wrapf(err, "failed to resolve secret reference in %s", key)
+		}
+
+		if !ok {
+			continue
+		}
+
+		if err := k.Set(key, out); err != nil {
+			return resolvedSecrets{}, oops.Wrapf(err, "failed to set resolved %s", key)
+		}
+		resolved.refs[key] = value
+	}
+
+	return resolved, nil
+}
+
+// hasSecretRef reports whether value, a string or list, holds a reference.
+func hasSecretRef(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return secretRefPattern.MatchString(v)
+	case []any:
+		return slices.ContainsFunc(v, func(item any) bool {
+			s, ok := item.(string)
+			return ok && secretRefPattern.MatchString(s)
+		})
+	}
+	return false
+}
+
+// resolveString resolves the references in s. Reports whether s held any.
+func (m *Module) resolveString(ctx context.Context, s string, files map[string]struct{}) (string, bool, error) {
+	matches := secretRefPattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, false, nil
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		scheme, ref := s[match[2]:match[3]], s[match[4]:match[5]]
+
+		resolver, ok := m.config.Resolvers[scheme]
+		if !ok {
+			return "", false, oops.With("scheme", scheme).Errorf("no resolver for scheme %q", scheme)
+		}
+
+		secret, err := resolver.Resolve(ctx, ref)
+		if err != nil {
+			return "", false, oops.With("scheme", scheme).Wrapf(err, "%s resolver failed", scheme)
+		}
+
+		if scheme == fileScheme {
+			files[filepath.Clean(ref)] = struct{}{}
+		}
+
+		b.WriteString(s[last:match[0]])
+		b.WriteString(secret)
+		last = match[1]
+	}
+	b.WriteString(s[last:])
+
+	return b.String(), true, nil
+}
+
+// commitSecrets records the secrets resolved for the config just swapped in
+// and watches the directories of newly referenced files. Must hold the write
+// lock.
+func (m *Module) commitSecrets(resolved resolvedSecrets) {
+	m.secrets = resolved
+	m.watchSecretFiles()
+}
+
+// watchSecretFiles adds the directory of every referenced secret file to the
+// file watcher, if it runs. Watching the directory rather than the file also
+// catches a file replaced by a rename, and the ..data symlink swap Kubernetes
+// uses to update mounted secrets. Must hold the write lock.
+func (m *Module) watchSecretFiles() {
+	if m.watcher == nil {
+		return
+	}
+
+	for path := range m.secrets.files {
+		dir := filepath.Dir(path)
+		if _, ok := m.secretDirs[dir]; ok {
+			continue
+		}
+
+		if err := m.watcher.Add(dir); err != nil {
+			slog.Warn("failed to watch secret directory", slog.String("path", dir), slog.Any("error", err))
+			continue
+		}
+		m.secretDirs[dir] = struct{}{}
+	}
+}
+
+// IsSecret reports whether the value at key was resolved from a secret
+// reference.
+func (m *Module) IsSecret(key string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, ok := m.secrets.refs[key]
+	return ok
+}
+
+// SafeRaw returns the current config as a nested map, like koanf's Raw, with
+// every value resolved from a secret reference put back to the reference, such
+// as ${file:/run/secrets/db}, so it is safe to display.
+func (m *Module) SafeRaw() map[string]any {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.secrets.refs) == 0 {
+		return m.koanf.Raw()
+	}
+
+	k := m.koanf.Copy()
+	for key, ref := range m.secrets.refs {
+		_ = k.Set(key, ref)
+	}
+	return k.Raw()
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/samber/oops"
+)
+
+// vaultResolver resolves path#key references from a fixed table.
+var vaultResolver = ResolverFunc(func(_ context.Context, ref string) (string, error) {
+	secrets := map[string]string{"secret/db#password": "vault-pass"}
+	if secret, ok := secrets[ref]; ok {
+		return secret, nil
+	}
+	return "", oops.Errorf("no secret at %s", ref)
+})
+
+func writeSecretConfig(t *testing.T, body string) string {
+	t.Helper()
+	dir := t.TempDir()
+	testza.AssertNoError(t, os.WriteFile(filepath.Join(dir, "lakta.yaml"), []byte(body), 0o600))
+	return dir
+}
+
+func TestSecrets_Resolve(t *testing.T) {
+	t.Setenv("LAKTATESTSECRET_API_KEY", "env-key")
+
+	secretDir := t.TempDir()
+	tokenFile := filepath.Join(secretDir, "token")
+	testza.AssertNoError(t, os.WriteFile(tokenFile, []byte("file-token\n"), 0o600))
+
+	dir := writeSecretConfig(t, `
+db:
+  dsn: postgres://app:${vault:secret/db#password}@db:5432/app
+api:
+  key: ${env:LAKTATESTSECRET_API_KEY}
+  headers:
+    - "Authorization: Bearer ${file:`+tokenFile+`}"
+    - "X-Plain: yes"
+plain: ${HOME} stays
+`)
+
+	m := NewModule(WithConfigDirs(dir), WithResolver("vault", vaultResolver))
+	testza.AssertNil(t, m.Init(setupModuleCtx(t)))
+
+	k := m.Koanf()
+	testza.AssertEqual(t, "postgres://app:vault-pass@db:5432/app", k.String("db.dsn"))
+	testza.AssertEqual(t, "env-key", k.String("api.key"))
+	testza.AssertEqual(t, []string{"Authorization: Bearer file-token", "X-Plain: yes"}, k.Strings("api.headers"))
+	testza.AssertEqual(t, "${HOME} stays", k.String("plain"))
+
+	testza.AssertTrue(t, m.IsSecret("db.dsn"))
+	testza.AssertTrue(t, m.IsSecret("api.headers"))
+	testza.AssertFalse(t, m.IsSecret("plain"))
+
+	for _, e := range m.ProvenanceSnapshot() {
+		if e.Key == "api.key" {
+			testza.AssertTrue(t, e.Secret)
+			testza.AssertEqual(t, "${env:LAKTATESTSECRET_API_KEY}", e.Value)
+		}
+	}
+
+	safe := m.SafeRaw()
+	api, ok := safe["api"].(map[string]any)
+	testza.AssertTrue(t, ok)
+	testza.AssertEqual(t, "${env:LAKTATESTSECRET_API_KEY}", api["key"])
+	testza.AssertEqual(t, "env-key", k.String("api.key"), "SafeRaw must not touch the live config")
+}
+
+func TestSecrets_SourcesNeedSecretResolution(t *testing.T) {
+	t.Setenv("LAKTATESTSECRET_SOURCE", "local-secret")
+
+	const ref = "${env:LAKTATESTSECRET_SOURCE}"
+	dir := writeSecretConfig(t, "file: "+ref+"\nshadowed: "+ref+"\n")
+
+	remote := NewMemoryKV()
+	remote.Put("app/remote", ref)
+	remote.Put("app/shadowed", ref)
+	trusted := NewMemoryKV()
+	trusted.Put("app/trusted", ref)
+
+	ctx, cancel := context.WithCancel(setupModuleCtx(t))
+	defer cancel()
+
+	m := NewModule(WithConfigDirs(dir),
+		WithSource(NewKVSource("remote", remote, "app"), AboveFiles),
+		WithSource(NewKVSource("trusted", trusted, "app"), AboveFiles, WithSecretResolution(true)))
+	testza.AssertNil(t, m.Init(ctx))
+	ch := reloads(m)
+
+	k := m.Koanf()
+	testza.AssertEqual(t, "local-secret", k.String("file"))
+	testza.AssertEqual(t, "local-secret", k.String("trusted"))
+	testza.AssertEqual(t, ref, k.String("remote"))
+	testza.AssertEqual(t, ref, k.String("shadowed"), "the remote source set the value, not the file")
+	testza.AssertFalse(t, m.IsSecret("remote"))
+
+	// A reload from the remote source resolves nothing either.
+	remote.Put("app/remote", "${file:"+filepath.Join(dir, "lakta.yaml")+"}")
+	testza.AssertEqual(t, "${file:"+filepath.Join(dir, "lakta.yaml")+"}", waitReload(t, ch).String("remote"))
+}
+
+func TestSecrets_ResolveErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "unknown scheme", value: "${vault:secret/db#password}", want: `no resolver for scheme "vault"`},
+		{name: "missing env", value: "${env:LAKTATESTSECRET_UNSET}", want: "LAKTATESTSECRET_UNSET is not set"},
+		{name: "missing file", value: "${file:/nonexistent/secret}", want: "failed to read secret file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := writeSecretConfig(t, "db:\n  password: \""+tt.value+"\"\n")
+
+			err := NewModule(WithConfigDirs(dir)).Init(setupModuleCtx(t))
+			testza.AssertNotNil(t, err)
+			testza.AssertContains(t, err.Error(), "db.password")
+			testza.AssertContains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestSecrets_RotatedFileReloads(t *testing.T) {
+	t.Parallel()
+
+	secretDir := t.TempDir()
+	secret := filepath.Join(secretDir, "db-password")
+	testza.AssertNoError(t, os.WriteFile(secret, []byte("v1"), 0o600))
+
+	dir := writeSecretConfig(t, "db:\n  password: ${file:"+secret+"}\n")
+
+	ctx, cancel := context.WithCancel(setupModuleCtx(t))
+	defer cancel()
+
+	m := NewModule(WithConfigDirs(dir), WithDebounceDelay(time.Millisecond))
+	testza.AssertNil(t, m.Init(ctx))
+	testza.AssertEqual(t, "v1", m.Koanf().String("db.password"))
+	ch := reloads(m)
+
+	// Rewritten in place.
+	testza.AssertNoError(t, os.WriteFile(secret, []byte("v2"), 0o600))
+	testza.AssertEqual(t, "v2", waitReload(t, ch).String("db.password"))
+
+	// Replaced by a rename, as atomic writers and secret mounts do.
+	tmp := filepath.Join(secretDir, "..tmp")
+	testza.AssertNoError(t, os.WriteFile(tmp, []byte("v3"), 0o600))
+	testza.AssertNoError(t, os.Rename(tmp, secret))
+	deadline := time.Now().Add(2 * time.Second)
+	for m.Koanf().String("db.password") != "v3" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	testza.AssertEqual(t, "v3", m.Koanf().String("db.password"))
+}
+
+func TestAffectsConfig(t *testing.T) {
+	t.Parallel()
+
+	m := NewModule()
+	m.configFiles = []configFile{{path: "/etc/app/lakta.yaml"}}
+	m.secrets.files = map[string]struct{}{"/etc/app/db-password": {}}
+	m.secretDirs["/etc/app"] = struct{}{}
+
+	for path, want := range map[string]bool{
+		"/etc/app/lakta.yaml":       true,
+		"/etc/app/db-password":      true,
+		"/etc/app/..data":           true,
+		"/etc/app/unrelated":        false,
+		"/etc/other/lakta.yaml":     true,
+		"/etc/app/./db-password":    true,
+		"/etc/app/db-password.swp~": false,
+	} {
+		testza.AssertEqual(t, want, m.affectsConfig(path), path)
+	}
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/samber/oops"
+)
+
+// Source is a config layer kept outside the local files, env vars and CLI
+// flags, such as a KV store or an HTTP endpoint. Register one with WithSource.
+type Source interface {
+	// Name identifies the source in logs, errors and provenance.
+	Name() string
+
+	// Load returns the source's current config as a nested map, a map with
+	// dotted keys, or a mix of both. The config module calls it once while
+	// loading; an error fails startup.
+	Load(ctx context.Context) (map[string]any, error)
+
+	// Watch blocks until ctx ends, calling update with the source's new
+	// config whenever it changes. update returns nil once the config is
+	// applied, and the reload's error when it was rejected: a source that
+	// skips config it already delivered must offer a rejected one again. A
+	// source that cannot watch returns nil right away.
+	Watch(ctx context.Context, update func(data map[string]any) error) error
+}
+
+// Precedence places a Source in the config merge order. Each layer overrides
+// the layers loaded before it; sources sharing a precedence load in the order
+// they were registered.
+type Precedence int
+
+const (
+	// BelowFiles loads the source first, so config files override it.
+	BelowFiles Precedence = iota
+
+	// AboveFiles loads the source after the config files and before env vars.
+	AboveFiles
+
+	// AboveEnv loads the source after env vars and before CLI flags.
+	AboveEnv
+
+	// AboveFlags loads the source last, so it overrides everything.
+	AboveFlags
+)
+
+// RegisteredSource is a Source and its place in the merge order.
+type RegisteredSource struct {
+	Source     Source
+	Precedence Precedence
+
+	// ResolveSecrets resolves the ${scheme:reference} values the source
+	// sets. Off by default: whoever controls a remote source could otherwise
+	// read local files and env vars into the config.
+	ResolveSecrets bool
+}
+
+// SourceOption configures a source registered with WithSource.
+type SourceOption func(source *RegisteredSource)
+
+// WithSecretResolution sets whether the secret references in the values the
+// source sets are resolved (default: false, they stay literal strings). Only
+// enable it for a source as trusted as the local config files.
+func WithSecretResolution(enabled bool) SourceOption {
+	return func(source *RegisteredSource) {
+		source.ResolveSecrets = enabled
+	}
+}
+
+// WithSource registers a config source at the given precedence. Its changes
+// reload the config like a file change does: the merged config must pass the
+// OnValidate validators before the OnReload callbacks see it.
+func WithSource(source Source, precedence Precedence, options ...SourceOption) Option {
+	registered := RegisteredSource{Source: source, Precedence: precedence}
+	for _, option := range options {
+		option(&registered)
+	}
+
+	return func(cfg *Config) {
+		cfg.Sources = append(cfg.Sources, registered)
+	}
+}
+
+// sourceLayer is a registered source and the config it last contributed.
+type sourceLayer struct {
+	RegisteredSource
+
+	data map[string]any // the last config loaded or accepted on reload
+}
+
+func newSourceLayers(sources []RegisteredSource) []*sourceLayer {
+	layers := make([]*sourceLayer, len(sources))
+	for i, source := range sources {
+		layers[i] = &sourceLayer{RegisteredSource: source}
+	}
+	return layers
+}
+
+// loadSources calls Load on every source.
+func (m *Module) loadSources(ctx context.Context) error {
+	for _, layer := range m.sources {
+		data, err := layer.Source.Load(ctx)
+		if err != nil {
+			return oops.With("source", layer.Source.Name()).Wrapf(err, "failed to load config source %s", layer.Source.Name())
+		}
+		layer.data = data
+	}
+
+	return nil
+}
+
+// mergeSources merges into k the config of every source at precedence, with
+// data in place of the committed config of pending, if set.
+func (m *Module) mergeSources(k *koanf.Koanf, precedence Precedence, pending *sourceLayer, data map[string]any) error {
+	for _, layer := range m.sources {
+		if layer.Precedence != precedence {
+			continue
+		}
+
+		layerData := layer.data
+		if layer == pending {
+			layerData = data
+		}
+
+		sk, err := sourceKoanf(layerData)
+		if err != nil {
+			return oops.Wrapf(err, "failed to load config source %s", layer.Source.Name())
+		}
+
+		if err := k.Merge(sk); err != nil {
+			return oops.Wrapf(err, "failed to merge config source %s", layer.Source.Name())
+		}
+	}
+
+	return nil
+}
+
+// sourceKoanf builds a koanf from a source's config, splitting dotted keys.
+func sourceKoanf(data map[string]any) (*koanf.Koanf, error) {
+	k := koanf.New(".")
+	for key, value := range data {
+		if err := k.Set(key, value); err != nil {
+			return nil, oops.Wrapf(err, "failed to set key %q", key)
+		}
+	}
+	return k, nil
+}
+
+// startSourceWatchers watches every source until ctx ends.
+func (m *Module) startSourceWatchers(ctx context.Context) {
+	for _, layer := range m.sources {
+		go m.watchSource(ctx, layer)
+	}
+}
+
+func (m *Module) watchSource(ctx context.Context, layer *sourceLayer) {
+	name := layer.Source.Name()
+
+	err := layer.Source.Watch(ctx, func(data map[string]any) error {
+		_, err := m.updateSource(ctx, layer, data)
+		if err != nil {
+			slog.Error("failed to reload config", slog.String("source", name), slog.Any("error", err))
+		}
+		return err
+	})
+	if err != nil && ctx.Err() == nil {
+		slog.Error("config source watch failed", slog.String("source", name), slog.Any("error", err))
+	}
+}
+
+// updateSource reloads the config with data as the new config of layer. The
+// layer keeps its previous config when the reload fails. Returns false
+// without reloading when data is what the layer already holds.
+func (m *Module) updateSource(ctx context.Context, layer *sourceLayer, data map[string]any) (bool, error) {
+	m.mu.Lock()
+
+	if reflect.DeepEqual(layer.data, data) {
+		m.mu.Unlock()
+		return false, nil
+	}
+
+	entry, err := m.rebuild(ctx, layer, data)
+	if err != nil {
+		m.mu.Unlock()
+		return false, err
+	}
+
+	layer.data = data
+	entry.Source = layer.Source.Name()
+	m.publishChangeLog(ctx, entry)
+
+	return true, nil
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/knadh/koanf/parsers/json"
+	"github.com/samber/oops"
+)
+
+const (
+	defaultPollInterval = 30 * time.Second
+	maxHTTPSourceBody   = 10 << 20
+)
+
+// HTTPSource is a Source polling an HTTP endpoint that serves the config as a
+// JSON object. Polls send the ETag of the last applied config, so a 304 Not
+// Modified costs no reload; an unchanged body does not reload either. A
+// config the reload rejected is fetched and offered again at every poll.
+type HTTPSource struct {
+	url      string
+	client   *http.Client
+	header   http.Header
+	interval time.Duration
+
+	mu   sync.Mutex
+	etag string // of the last applied config
+	body []byte
+}
+
+// HTTPSourceOption configures an HTTPSource.
+type HTTPSourceOption func(s *HTTPSource)
+
+// WithPollInterval sets how often the endpoint is polled for changes (default: 30s).
+func WithPollInterval(d time.Duration) HTTPSourceOption {
+	return func(s *HTTPSource) {
+		s.interval = d
+	}
+}
+
+// WithHTTPClient sets the client requests are sent with (default: http.DefaultClient).
+func WithHTTPClient(client *http.Client) HTTPSourceOption {
+	return func(s *HTTPSource) {
+		s.client = client
+	}
+}
+
+// WithHTTPHeader adds a header to every request, such as Authorization.
+func WithHTTPHeader(key, value string) HTTPSourceOption {
+	return func(s *HTTPSource) {
+		s.header.Add(key, value)
+	}
+}
+
+// NewHTTPSource creates a Source reading the JSON config served at url.
+func NewHTTPSource(url string, options ...HTTPSourceOption) *HTTPSource {
+	s := &HTTPSource{
+		url:      url,
+		client:   http.DefaultClient,
+		header:   make(http.Header),
+		interval: defaultPollInterval,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// Name returns "http " and the URL, with any credentials in it masked.
+func (s *HTTPSource) Name() string {
+	return "http " + ScrubSecrets(s.url)
+}
+
+// Load fetches the config.
+func (s *HTTPSource) Load(ctx context.Context) (map[string]any, error) {
+	data, commit, err := s.fetch(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	commit()
+
+	return data, nil
+}
+
+// Watch polls the endpoint every poll interval and calls update when the
+// config changed. A failed poll is logged and retried at the next one.
+func (s *HTTPSource) Watch(ctx context.Context, update func(data map[string]any) error) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		data, commit, err := s.fetch(ctx, true)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			slog.Warn("failed to poll config source", slog.String("source", s.Name()), slog.Any("error", err))
+		case commit != nil && update(data) == nil:
+			commit()
+		}
+	}
+}
+
+// fetch requests the config. With conditional it sends the last ETag and
+// returns no data and a nil commit when the config is unchanged. Otherwise
+// commit records the response as the applied config, for the caller to call
+// once it is.
+func (s *HTTPSource) fetch(ctx context.Context, conditional bool) (map[string]any, func(), error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, nil, oops.Wrapf(err, "failed to create request")
+	}
+
+	req.Header = s.header.Clone()
+	req.Header.Set("Accept", "application/json")
+
+	s.mu.Lock()
+	etag := s.etag
+	s.mu.Unlock()
+
+	if conditional && etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, oops.Wrapf(err, "request failed")
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if conditional && resp.StatusCode == http.StatusNotModified {
+		return nil, nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, oops.With("status", resp.StatusCode).Errorf("unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPSourceBody))
+	if err != nil {
+		return nil, nil, oops.Wrapf(err, "failed to read response")
+	}
+
+	s.mu.Lock()
+	unchanged := s.body != nil && bytes.Equal(body, s.body)
+	s.mu.Unlock()
+
+	if conditional && unchanged {
+		return nil, nil, nil
+	}
+
+	data, err := json.Parser().Unmarshal(body)
+	if err != nil {
+		return nil, nil, oops.Wrapf(err, "failed to decode config")
+	}
+
+	newETag := resp.Header.Get("ETag")
+	commit := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.etag = newETag
+		s.body = body
+	}
+
+	return data, commit, nil
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samber/oops"
+)
+
+// kvRetryDelay is how long a KVSource waits after a failed watch query.
+const kvRetryDelay = time.Second
+
+// KV is a key-value store holding config under a key prefix, such as Consul
+// or etcd. Keys are slash-separated below the prefix.
+type KV interface {
+	// List returns every key under prefix with its value, and the store's
+	// index for them. With waitIndex above zero it first blocks until the
+	// index moves past waitIndex or ctx ends, like a Consul blocking query or
+	// an etcd watch from a revision.
+	List(ctx context.Context, prefix string, waitIndex uint64) (map[string]string, uint64, error)
+}
+
+// KVSource is a Source reading the keys of a KV under a prefix: the key
+// <prefix>/modules/grpc/server/default/port sets
+// modules.grpc.server.default.port. Values are strings, like env vars.
+type KVSource struct {
+	name   string
+	kv     KV
+	prefix string
+
+	mu    sync.Mutex
+	index uint64
+}
+
+// NewKVSource creates a Source reading the keys of kv under prefix. name
+// identifies it in logs and provenance.
+func NewKVSource(name string, kv KV, prefix string) *KVSource {
+	return &KVSource{
+		name:   name,
+		kv:     kv,
+		prefix: strings.TrimSuffix(prefix, "/") + "/",
+	}
+}
+
+// Name returns the name the source was created with.
+func (s *KVSource) Name() string {
+	return s.name
+}
+
+// Load lists the keys under the prefix.
+func (s *KVSource) Load(ctx context.Context) (map[string]any, error) {
+	pairs, index, err := s.kv.List(ctx, s.prefix, 0)
+	if err != nil {
+		return nil, oops.With("prefix", s.prefix).Wrapf(err, "failed to list keys")
+	}
+
+	s.mu.Lock()
+	s.index = index
+	s.mu.Unlock()
+
+	return s.decode(pairs), nil
+}
+
+// Watch waits for the store's index to move and calls update with the keys
+// under the prefix each time it does. A failed query is logged and retried.
+func (s *KVSource) Watch(ctx context.Context, update func(data map[string]any) error) error {
+	s.mu.Lock()
+	index := s.index
+	s.mu.Unlock()
+
+	for {
+		// Index zero would not block; treat it as the first index.
+		pairs, next, err := s.kv.List(ctx, s.prefix, max(index, 1))
+
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			slog.Warn("failed to watch config source", slog.String("source", s.name), slog.Any("error", err))
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(kvRetryDelay):
+			}
+		case next != index:
+			index = next
+			// A rejected config comes back with the store's next change.
+			_ = update(s.decode(pairs))
+		}
+	}
+}
+
+// decode turns the listed keys into dotted config keys. Keys ending in "/",
+// such as Consul folders, are skipped.
+func (s *KVSource) decode(pairs map[string]string) map[string]any {
+	data := make(map[string]any, len(pairs))
+	for key, value := range pairs {
+		key = strings.TrimPrefix(key, s.prefix)
+		if key == "" || strings.HasSuffix(key, "/") {
+			continue
+		}
+		data[strings.ReplaceAll(key, "/", ".")] = value
+	}
+	return data
+}
+
+// MemoryKV is a KV held in memory, for tests and local development. Safe for
+// concurrent use.
+type MemoryKV struct {
+	mu      sync.Mutex
+	data    map[string]string
+	index   uint64
+	changed chan struct{} // closed and replaced on every change
+}
+
+// NewMemoryKV creates an empty MemoryKV.
+func NewMemoryKV() *MemoryKV {
+	return &MemoryKV{
+		data:    make(map[string]string),
+		index:   1,
+		changed: make(chan struct{}),
+	}
+}
+
+// Put sets key to value.
+func (kv *MemoryKV) Put(key, value string) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	kv.data[key] = value
+	kv.bump()
+}
+
+// Delete removes key.
+func (kv *MemoryKV) Delete(key string) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	delete(kv.data, key)
+	kv.bump()
+}
+
+// bump advances the index and wakes blocked List calls. Must hold mu.
+func (kv *MemoryKV) bump() {
+	kv.index++
+	close(kv.changed)
+	kv.changed = make(chan struct{})
+}
+
+// List implements KV.
+func (kv *MemoryKV) List(ctx context.Context, prefix string, waitIndex uint64) (map[string]string, uint64, error) {
+	kv.mu.Lock()
+	for waitIndex > 0 && kv.index <= waitIndex {
+		changed := kv.changed
+		kv.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, 0, oops.Wrapf(ctx.Err(), "waiting for index %d", waitIndex)
+		}
+
+		kv.mu.Lock()
+	}
+	defer kv.mu.Unlock()
+
+	pairs := make(map[string]string)
+	for key, value := range kv.data {
+		if strings.HasPrefix(key, prefix) {
+			pairs[key] = value
+		}
+	}
+
+	return pairs, kv.index, nil
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/Vilsol/lakta/pkg/lakta"
+	"github.com/knadh/koanf/v2"
+	"github.com/samber/oops"
+)
+
+// ReloadStatus is how a module came out of a two-phase reload.
+type ReloadStatus string
+
+const (
+	// ReloadCommitted marks a module that prepared and committed the reload.
+	ReloadCommitted ReloadStatus = "committed"
+	// ReloadFailed marks the module whose prepare failed.
+	ReloadFailed ReloadStatus = "failed"
+	// ReloadAborted marks a module that prepared, then was aborted because
+	// another module failed.
+	ReloadAborted ReloadStatus = "aborted"
+	// ReloadSkipped marks a module not prepared because an earlier one failed.
+	ReloadSkipped ReloadStatus = "skipped"
+)
+
+// ReloadResult is how one module registered with OnPrepare took part in a
+// reload, and how long each phase took.
+type ReloadResult struct {
+	Module  string        `json:"module"`
+	Status  ReloadStatus  `json:"status"`
+	Prepare time.Duration `json:"prepare"`
+	Commit  time.Duration `json:"commit,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// ReloadError reports a reload rolled back because a module failed to prepare
+// it. Every module kept its previous state.
+type ReloadError struct {
+	Module  string
+	Err     error
+	Results []ReloadResult
+}
+
+func (e *ReloadError) Error() string {
+	return fmt.Sprintf("%s failed to prepare the reload: %v", e.Module, e.Err)
+}
+
+func (e *ReloadError) Unwrap() error {
+	return e.Err
+}
+
+// preparer is a prepare phase registered with OnPrepare.
+type preparer struct {
+	name string
+	fn   func(k *koanf.Koanf) (lakta.PreparedReload, error)
+}
+
+// OnPrepare registers the prepare phase of a two-phase reload for the module
+// called name. Once the validators accept a reload, every prepare phase runs
+// in registration order; if one fails, those already prepared are aborted in
+// reverse order and the reload is rejected with a *ReloadError. Otherwise the
+// new config is swapped in and every prepared reload committed, before the
+// OnReload callbacks run. Prepare phases run under the module's write lock, so
+// they must not call back into the config module.
+func (m *Module) OnPrepare(name string, fn func(k *koanf.Koanf) (lakta.PreparedReload, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onPrepare = append(m.onPrepare, preparer{name: name, fn: fn})
+}
+
+// prepareReload runs every prepare phase with k. On failure it aborts the
+// prepared ones and returns a *ReloadError. Must hold the write lock.
+func (m *Module) prepareReload(k *koanf.Koanf) ([]lakta.PreparedReload, []ReloadResult, error) {
+	prepared := make([]lakta.PreparedReload, 0, len(m.onPrepare))
+	results := make([]ReloadResult, len(m.onPrepare))
+
+	for i, p := range m.onPrepare {
+		results[i].Module = p.name
+
+		start := time.Now()
+		reload, err := safePrepare(p.fn, k)
+		results[i].Prepare = time.Since(start)
+
+		if err == nil {
+			prepared = append(prepared, reload)
+			continue
+		}
+
+		results[i].Status = ReloadFailed
+		results[i].Error = err.Error()
+		for j := i + 1; j < len(results); j++ {
+			results[j] = ReloadResult{Module: m.onPrepare[j].name, Status: ReloadSkipped}
+		}
+		for j, reload := range slices.Backward(prepared) {
+			results[j].Status = ReloadAborted
+			if reload.Abort != nil {
+				safeRun("abort", results[j].Module, reload.Abort)
+			}
+		}
+
+		slog.Warn("config reload rolled back", slog.Group("modules", reloadResultAttrs(results)...))
+
+		return nil, nil, &ReloadError{Module: p.name, Err: err, Results: results}
+	}
+
+	return prepared, results, nil
+}
+
+// commitReload commits every prepared reload, in registration order. Must
+// hold the write lock.
+func commitReload(prepared []lakta.PreparedReload, results []ReloadResult) {
+	for i, reload := range prepared {
+		start := time.Now()
+		if reload.Commit != nil {
+			safeRun("commit", results[i].Module, reload.Commit)
+		}
+		results[i].Commit = time.Since(start)
+		results[i].Status = ReloadCommitted
+	}
+}
+
+// safePrepare runs a prepare phase, turning a panic into its error.
+func safePrepare(fn func(k *koanf.Koanf) (lakta.PreparedReload, error), k *koanf.Koanf) (reload lakta.PreparedReload, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = oops.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(k)
+}
+
+// safeRun runs the commit or abort of a prepared reload, isolating panics like
+// safeCallback does.
+func safeRun(phase, module string, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("config reload "+phase+" panicked", slog.String("module", module), slog.Any("panic", r))
+		}
+	}()
+	fn()
+}
+
+// reloadResultAttrs renders results as one log group per module.
+func reloadResultAttrs(results []ReloadResult) []any {
+	attrs := make([]any, 0, len(results))
+	for _, r := range results {
+		group := []any{slog.String("status", string(r.Status)), slog.Duration("prepare", r.Prepare)}
+		if r.Status == ReloadCommitted {
+			group = append(group, slog.Duration("commit", r.Commit))
+		}
+		if r.Error != "" {
+			group = append(group, slog.String("error", r.Error))
+		}
+		attrs = append(attrs, slog.Group(r.Module, group...))
+	}
+	return attrs
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/Vilsol/lakta/pkg/lakta"
+	"github.com/knadh/koanf/v2"
+)
+
+// recordPrepare registers a prepare phase for name that appends its commit and
+// abort to events, or fails with err.
+func recordPrepare(m *Module, name string, events *[]string, err error) {
+	m.OnPrepare(name, func(*koanf.Koanf) (lakta.PreparedReload, error) {
+		*events = append(*events, "prepare "+name)
+		if err != nil {
+			return lakta.PreparedReload{}, err
+		}
+		return lakta.PreparedReload{
+			Commit: func() { *events = append(*events, "commit "+name) },
+			Abort:  func() { *events = append(*events, "abort "+name) },
+		}, nil
+	})
+}
+
+func TestTransactionalReload_Commits(t *testing.T) {
+	t.Parallel()
+
+	dir := writeSecretConfig(t, "port: 1\n")
+	m := NewModule(WithConfigDirs(dir))
+	testza.AssertNil(t, m.Init(setupModuleCtx(t)))
+
+	var events []string
+	recordPrepare(m, "a", &events, nil)
+	recordPrepare(m, "b", &events, nil)
+	m.OnReload(func(*koanf.Koanf) { events = append(events, "reload") })
+
+	var entry ChangeLog
+	m.OnChangeLog(func(_ context.Context, e ChangeLog) { entry = e })
+
+	testza.AssertNoError(t, os.WriteFile(filepath.Join(dir, "lakta.yaml"), []byte("port: 2\n"), 0o600))
+	testza.AssertNoError(t, m.reload(t.Context()))
+
+	testza.AssertEqual(t, []string{"prepare a", "prepare b", "commit a", "commit b", "reload"}, events)
+	testza.AssertEqual(t, 2, m.Koanf().Int("port"))
+
+	testza.AssertLen(t, entry.Modules, 2)
+	for i, name := range []string{"a", "b"} {
+		testza.AssertEqual(t, name, entry.Modules[i].Module)
+		testza.AssertEqual(t, ReloadCommitted, entry.Modules[i].Status)
+		testza.AssertEqual(t, "", entry.Modules[i].Error)
+	}
+}
+
+func TestTransactionalReload_RollsBack(t *testing.T) {
+	t.Parallel()
+
+	dir := writeSecretConfig(t, "port: 1\n")
+	m := NewModule(WithConfigDirs(dir))
+	testza.AssertNil(t, m.Init(setupModuleCtx(t)))
+
+	boom := errors.New("boom")
+	var events []string
+	recordPrepare(m, "a", &events, nil)
+	recordPrepare(m, "b", &events, nil)
+	recordPrepare(m, "c", &events, boom)
+	recordPrepare(m, "d", &events, nil)
+	m.OnReload(func(*koanf.Koanf) { events = append(events, "reload") })
+
+	testza.AssertNoError(t, os.WriteFile(filepath.Join(dir, "lakta.yaml"), []byte("port: 2\n"), 0o600))
+	err := m.reload(t.Context())
+
+	var reloadErr *ReloadError
+	testza.AssertTrue(t, errors.As(err, &reloadErr))
+	testza.AssertErrorIs(t, err, boom)
+	testza.AssertEqual(t, "c", reloadErr.Module)
+	testza.AssertContains(t, err.Error(), "c failed to prepare the reload: boom")
+
+	testza.AssertEqual(t, []string{"prepare a", "prepare b", "prepare c", "abort b", "abort a"}, events)
+	testza.AssertEqual(t, 1, m.Koanf().Int("port"))
+
+	statuses := make([]ReloadStatus, len(reloadErr.Results))
+	for i, r := range reloadErr.Results {
+		statuses[i] = r.Status
+	}
+	testza.AssertEqual(t, []ReloadStatus{ReloadAborted, ReloadAborted, ReloadFailed, ReloadSkipped}, statuses)
+	testza.AssertEqual(t, "boom", reloadErr.Results[2].Error)
+}
+
+func TestTransactionalReload_PanicFailsPrepare(t *testing.T) {
+	t.Parallel()
+
+	dir := writeSecretConfig(t, "port: 1\n")
+	m := NewModule(WithConfigDirs(dir))
+	testza.AssertNil(t, m.Init(setupModuleCtx(t)))
+
+	m.OnPrepare("panicky", func(*koanf.Koanf) (lakta.PreparedReload, error) { panic("bad state") })
+
+	testza.AssertNoError(t, os.WriteFile(filepath.Join(dir, "lakta.yaml"), []byte("port: 2\n"), 0o600))
+	err := m.reload(t.Context())
+	testza.AssertNotNil(t, err)
+	testza.AssertContains(t, err.Error(), "panicky failed to prepare the reload: panic: bad state")
+	testza.AssertEqual(t, 1, m.Koanf().Int("port"))
+}
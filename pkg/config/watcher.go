@@ -3,6 +3,9 @@ package config
 import (
 	"context"
 	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -33,8 +36,13 @@ func defaultWatcherFactory() (fileWatcher, error) {
 	return &fsnotifyAdapter{w: w}, nil
 }
 
+// startWatcher watches the config files and the directories of referenced
+// secret files until ctx ends.
 func (m *Module) startWatcher(ctx context.Context) {
-	if len(m.configFiles) == 0 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.configFiles) == 0 && len(m.secrets.files) == 0 {
 		return
 	}
 
@@ -50,11 +58,22 @@ func (m *Module) startWatcher(ctx context.Context) {
 		}
 	}
 
+	m.watcher = watcher
+	m.watchSecretFiles()
+
 	go m.watchLoop(ctx, watcher)
 }
 
 func (m *Module) watchLoop(ctx context.Context, watcher fileWatcher) {
-	defer func() { _ = watcher.Close() }()
+	defer func() {
+		m.mu.Lock()
+		if m.watcher == watcher {
+			m.watcher = nil
+		}
+		m.mu.Unlock()
+
+		_ = watcher.Close()
+	}()
 
 	var debounce *time.Timer
 
@@ -71,15 +90,13 @@ func (m *Module) watchLoop(ctx context.Context, watcher fileWatcher) {
 				return
 			}
 
-			if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) {
+			if (event.Has(fsnotify.Write) || event.Has(fsnotify.Create)) && m.affectsConfig(event.Name) {
 				if debounce != nil {
 					debounce.Stop()
 				}
 				debounce = time.AfterFunc(m.config.DebounceDelay, func() {
-					if err := m.reload(); err != nil {
+					if err := m.reload(ctx); err != nil {
 						slog.Error("failed to reload config", slog.Any("error", err))
-					} else {
-						slog.Info("config reloaded successfully")
 					}
 				})
 			}
@@ -92,3 +109,22 @@ func (m *Module) watchLoop(ctx context.Context, watcher fileWatcher) {
 		}
 	}
 }
+
+// affectsConfig reports whether a change to the file at path can change the
+// config. Events from a watched config file always can; in a secret directory
+// only a referenced secret file or a Kubernetes ..data swap can.
+func (m *Module) affectsConfig(path string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	path = filepath.Clean(path)
+	if _, ok := m.secretDirs[filepath.Dir(path)]; !ok {
+		return true
+	}
+
+	if _, ok := m.secrets.files[path]; ok || strings.HasPrefix(filepath.Base(path), "..") {
+		return true
+	}
+
+	return slices.ContainsFunc(m.configFiles, func(cf configFile) bool { return filepath.Clean(cf.path) == path })
+}
@@ -46,6 +46,11 @@ func (m *Module) LoadConfig(k *koanf.Koanf) error {
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
 // Init loads configuration and prepares the connection pool config.
 func (m *Module) Init(ctx context.Context) error {
 	// Parse the log level string
@@ -97,8 +102,12 @@ func (m *Module) StartAsync(ctx context.Context) error {
 		}
 	}
 
-	lakta.ProvideValue(ctx, m.instance)
-	lakta.ProvideValue(ctx, m.stdInstance)
+	if m.isDefault() {
+		lakta.ProvideValue(ctx, m.instance)
+		lakta.ProvideValue(ctx, m.stdInstance)
+	}
+	lakta.ProvideNamedValue(ctx, m.config.Name, m.instance)
+	lakta.ProvideNamedValue(ctx, m.config.Name, m.stdInstance)
 
 	if m.config.HealthCheck {
 		h, err := lakta.Invoke[*health.Health](ctx)
@@ -119,14 +128,32 @@ func (m *Module) StartAsync(ctx context.Context) error {
 	return nil
 }
 
-// Provides returns the types this module registers in DI.
+// Provides returns the unqualified types this module registers in DI. Only the
+// default instance registers them, so named instances never collide.
 func (m *Module) Provides() []reflect.Type {
+	if !m.isDefault() {
+		return nil
+	}
+
 	return []reflect.Type{
 		reflect.TypeFor[*pgxpool.Pool](),
 		reflect.TypeFor[*sql.DB](),
 	}
 }
 
+// NamedProvides returns the types this module registers in DI qualified by its
+// instance name, resolved with lakta.InvokeNamed.
+func (m *Module) NamedProvides() []lakta.ServiceKey {
+	return []lakta.ServiceKey{
+		lakta.NamedKey[*pgxpool.Pool](m.config.Name),
+		lakta.NamedKey[*sql.DB](m.config.Name),
+	}
+}
+
+func (m *Module) isDefault() bool {
+	return m.config.Name == config.DefaultInstanceName
+}
+
 // Dependencies declares the optional types this module needs from DI before Init.
 func (m *Module) Dependencies() ([]reflect.Type, []reflect.Type) {
 	return nil, []reflect.Type{
@@ -9,9 +9,11 @@ import (
 )
 
 var (
-	_ lakta.AsyncModule  = (*Module)(nil)
-	_ lakta.Configurable = (*Module)(nil)
-	_ lakta.NamedModule  = (*Module)(nil)
+	_ lakta.AsyncModule   = (*Module)(nil)
+	_ lakta.Configurable  = (*Module)(nil)
+	_ lakta.NamedModule   = (*Module)(nil)
+	_ lakta.Provider      = (*Module)(nil)
+	_ lakta.NamedProvider = (*Module)(nil)
 )
 
 func TestNewPoolConfig_AppliesPoolDefaults(t *testing.T) {
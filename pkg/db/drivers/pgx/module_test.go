@@ -4,11 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/MarvinJWendt/testza"
 	pkgpgx "github.com/Vilsol/lakta/pkg/db/drivers/pgx"
+	"github.com/Vilsol/lakta/pkg/lakta"
 	"github.com/Vilsol/lakta/pkg/testkit"
 	healthgo "github.com/hellofresh/health-go/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -71,6 +73,32 @@ func TestPGXModule_Name(t *testing.T) {
 	testza.AssertEqual(t, "custom", pkgpgx.NewModule(pkgpgx.WithName("custom")).Name())
 }
 
+func TestPGXModule_NamedInstancesValidate(t *testing.T) {
+	t.Parallel()
+
+	primary := pkgpgx.NewModule(pkgpgx.WithName("primary"))
+	replica := pkgpgx.NewModule(pkgpgx.WithName("replica"))
+
+	testza.AssertNil(t, lakta.NewRuntime(primary, replica).Validate())
+	testza.AssertNil(t, primary.Provides())
+	testza.AssertEqual(t, []lakta.ServiceKey{
+		lakta.NamedKey[*pgxpool.Pool]("replica"),
+		lakta.NamedKey[*sql.DB]("replica"),
+	}, replica.NamedProvides())
+}
+
+func TestPGXModule_DefaultInstanceProvidesUnqualified(t *testing.T) {
+	t.Parallel()
+
+	m := pkgpgx.NewModule()
+
+	testza.AssertEqual(t, []reflect.Type{
+		reflect.TypeFor[*pgxpool.Pool](),
+		reflect.TypeFor[*sql.DB](),
+	}, m.Provides())
+	testza.AssertEqual(t, lakta.NamedKey[*pgxpool.Pool]("default"), m.NamedProvides()[0])
+}
+
 func TestPGXModule_StartAsync_RegistersPool(t *testing.T) {
 	t.Parallel()
 
@@ -87,6 +115,25 @@ func TestPGXModule_StartAsync_RegistersPool(t *testing.T) {
 	testza.AssertNotNil(t, pool)
 }
 
+func TestPGXModule_StartAsync_RegistersNamedPool(t *testing.T) {
+	t.Parallel()
+
+	dsn := startPostgresContainer(t)
+	h := testkit.NewHarness(t)
+	m := pkgpgx.NewModule(pkgpgx.WithName("replica"), pkgpgx.WithDSN(dsn))
+
+	testza.AssertNil(t, m.Init(h.Ctx()))
+	testza.AssertNil(t, m.StartAsync(h.Ctx()))
+	t.Cleanup(func() { _ = m.Shutdown(context.Background()) })
+
+	pool, err := lakta.InvokeNamed[*pgxpool.Pool](h.Ctx(), "replica")
+	testza.AssertNil(t, err)
+	testza.AssertNotNil(t, pool)
+
+	_, err = do.Invoke[*pgxpool.Pool](h.Injector())
+	testza.AssertNotNil(t, err, "named instances must not register the unqualified pool")
+}
+
 func TestPGXModule_StartAsync_RegistersSQLDB(t *testing.T) {
 	t.Parallel()
 
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/Vilsol/lakta/pkg/config"
+	fiberserver "github.com/Vilsol/lakta/pkg/http/fiber"
 	"github.com/gofiber/fiber/v3"
 	"github.com/knadh/koanf/v2"
 	"github.com/samber/oops"
@@ -31,8 +32,11 @@ const (
 	epRoutes    = "/routes"
 	epInfo      = "/info"
 	epHealth    = "/health"
+	epReadiness = epHealth + fiberserver.ReadinessPath
+	epLiveness  = epHealth + fiberserver.LivenessPath
 	epDI        = "/di"
 	epGoroutine = "/goroutine"
+	epCrashes   = "/crashes"
 	epVars      = "/vars"
 	epLoggers   = "/loggers"
 	epPprof     = "/pprof"
@@ -116,12 +120,13 @@ func (c *Config) SecretRedactor() (*Redactor, error) {
 }
 
 // RequiresAuth reports whether the endpoint (BasePath-relative, e.g.
-// "/goroutine") requires auth on any bind. True for goroutine/pprof/vars/loggers.
+// "/goroutine") requires auth on any bind. True for
+// goroutine/crashes/pprof/vars/loggers.
 func (c *Config) RequiresAuth(endpoint string) bool {
 	switch {
 	case endpoint == epGoroutine, endpoint == epVars, endpoint == epLoggers:
 		return true
-	case strings.HasPrefix(endpoint, epPprof):
+	case strings.HasPrefix(endpoint, epPprof), strings.HasPrefix(endpoint, epCrashes):
 		return true
 	default:
 		return false
@@ -2,6 +2,7 @@ package actuator
 
 import (
 	"bytes"
+	"errors"
 	"expvar"
 	"fmt"
 	"log/slog"
@@ -12,6 +13,8 @@ import (
 	"strconv"
 	"time"
 
+	fiberserver "github.com/Vilsol/lakta/pkg/http/fiber"
+	"github.com/Vilsol/lakta/pkg/lakta"
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/middleware/adaptor"
 	"github.com/samber/oops"
@@ -37,8 +40,12 @@ func (m *Module) registerHandlers(r fiber.Router) {
 	get(epRoutes, m.handleRoutes)
 	get(epInfo, m.handleInfo)
 	get(epHealth, m.handleHealth)
+	get(epReadiness, m.handleReadiness)
+	get(epLiveness, m.handleLiveness)
 	get(epDI, m.handleDI)
 	get(epGoroutine, m.handleGoroutine)
+	get(epCrashes, m.handleCrashes)
+	get(epCrashes+"/:id", m.handleCrash)
 
 	if m.config.Endpoints.Expvar {
 		get(epVars, m.handleExpvar)
@@ -65,15 +72,19 @@ type ModulesResponse struct {
 
 // ModuleView is one module's rendered metadata.
 type ModuleView struct {
-	Name         string   `json:"name"`
-	Type         string   `json:"type"`
-	InitOrder    int      `json:"init_order"`
-	Provides     []string `json:"provides"`
-	Requires     []string `json:"requires"`
-	Optional     []string `json:"optional"`
-	Lifecycle    string   `json:"lifecycle"`
-	State        string   `json:"state"`
-	InitDuration string   `json:"init_duration"`
+	Name            string   `json:"name"`
+	Type            string   `json:"type"`
+	InitOrder       int      `json:"init_order"`
+	InitWave        int      `json:"init_wave"`
+	Provides        []string `json:"provides"`
+	Requires        []string `json:"requires"`
+	Optional        []string `json:"optional"`
+	Lifecycle       string   `json:"lifecycle"`
+	State           string   `json:"state"`
+	InitDuration    string   `json:"init_duration"`
+	ShutdownTimeout string   `json:"shutdown_timeout"`
+	Restarts        int      `json:"restarts"`
+	LastError       string   `json:"last_error,omitempty"`
 }
 
 func (m *Module) handleModules(c fiber.Ctx) error {
@@ -85,15 +96,19 @@ func (m *Module) handleModules(c fiber.Ctx) error {
 	views := make([]ModuleView, 0, len(snap))
 	for _, mi := range snap {
 		views = append(views, ModuleView{
-			Name:         mi.Name,
-			Type:         mi.Type,
-			InitOrder:    mi.InitOrder,
-			Provides:     mi.Provides,
-			Requires:     mi.Requires,
-			Optional:     mi.Optional,
-			Lifecycle:    mi.Lifecycle.String(),
-			State:        mi.State.String(),
-			InitDuration: mi.InitDuration.String(),
+			Name:            mi.Name,
+			Type:            mi.Type,
+			InitOrder:       mi.InitOrder,
+			InitWave:        mi.InitWave,
+			Provides:        mi.Provides,
+			Requires:        mi.Requires,
+			Optional:        mi.Optional,
+			Lifecycle:       mi.Lifecycle.String(),
+			State:           mi.State.String(),
+			InitDuration:    mi.InitDuration.String(),
+			ShutdownTimeout: mi.ShutdownTimeout.String(),
+			Restarts:        mi.Restarts,
+			LastError:       mi.LastError,
 		})
 	}
 
@@ -102,17 +117,33 @@ func (m *Module) handleModules(c fiber.Ctx) error {
 
 // --- /startup ---
 
-// StartupResponse is the /startup JSON contract (init waterfall).
+// StartupResponse is the /startup JSON contract (init waterfall plus the
+// full lifecycle timeline).
 type StartupResponse struct {
-	Total   string         `json:"total_init_duration"`
-	Entries []StartupEntry `json:"entries"`
+	Total    string          `json:"total_init_duration"`
+	Entries  []StartupEntry  `json:"entries"`
+	Timeline []TimelineEntry `json:"timeline"`
 }
 
-// StartupEntry is one module's init timing.
+// StartupEntry is one module's per-phase timing; durations of phases the
+// module has not finished are "0s".
 type StartupEntry struct {
-	Name         string `json:"name"`
-	InitOrder    int    `json:"init_order"`
-	InitDuration string `json:"init_duration"`
+	Name             string `json:"name"`
+	InitOrder        int    `json:"init_order"`
+	InitWave         int    `json:"init_wave"`
+	ConfigDuration   string `json:"config_duration"`
+	InitDuration     string `json:"init_duration"`
+	StartDuration    string `json:"start_duration"`
+	ShutdownDuration string `json:"shutdown_duration"`
+}
+
+// TimelineEntry is one finished lifecycle step, in the order steps finished.
+type TimelineEntry struct {
+	Module   string    `json:"module,omitempty"`
+	Step     string    `json:"step"`
+	Start    time.Time `json:"start"`
+	Duration string    `json:"duration"`
+	Error    string    `json:"error,omitempty"`
 }
 
 func (m *Module) handleStartup(c fiber.Ctx) error {
@@ -130,13 +161,29 @@ func (m *Module) handleStartup(c fiber.Ctx) error {
 			name = mi.Type
 		}
 		entries = append(entries, StartupEntry{
-			Name:         name,
-			InitOrder:    mi.InitOrder,
-			InitDuration: mi.InitDuration.String(),
+			Name:             name,
+			InitOrder:        mi.InitOrder,
+			InitWave:         mi.InitWave,
+			ConfigDuration:   mi.ConfigDuration.String(),
+			InitDuration:     mi.InitDuration.String(),
+			StartDuration:    mi.StartDuration.String(),
+			ShutdownDuration: mi.ShutdownDuration.String(),
+		})
+	}
+
+	timeline := m.runtimeInfo.Timeline()
+	steps := make([]TimelineEntry, 0, len(timeline))
+	for _, e := range timeline {
+		steps = append(steps, TimelineEntry{
+			Module:   e.Module,
+			Step:     string(e.Step),
+			Start:    e.Start,
+			Duration: e.Duration.String(),
+			Error:    e.Err,
 		})
 	}
 
-	return writeJSON(c, StartupResponse{Total: total.String(), Entries: entries})
+	return writeJSON(c, StartupResponse{Total: total.String(), Entries: entries, Timeline: steps})
 }
 
 // --- /config ---
@@ -152,12 +199,20 @@ func (m *Module) handleConfig(c fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusNotImplemented, "config unavailable")
 	}
 
-	resp := ConfigResponse{Values: m.redactor.Redact(m.koanf.Raw(), false)}
+	raw := m.koanf.Raw()
+	if m.configModule != nil {
+		raw = m.configModule.SafeRaw()
+	}
+
+	resp := ConfigResponse{Values: m.redactor.Redact(raw, false)}
 
 	if c.Query("provenance") == "1" && m.configModule != nil {
 		prov := make(map[string]string)
 		for _, e := range m.configModule.ProvenanceSnapshot() {
 			prov[e.Key] = e.Origin
+			if e.Source != "" {
+				prov[e.Key] += ":" + e.Source
+			}
 		}
 		resp.Provenance = prov
 	}
@@ -256,6 +311,22 @@ func (m *Module) handleHealth(c fiber.Ctx) error {
 	return adaptor.HTTPHandlerFunc(m.health.HandlerFunc)(c)
 }
 
+// --- /health/readiness, /health/liveness ---
+
+func (m *Module) handleReadiness(c fiber.Ctx) error {
+	if m.runtimeInfo == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, "runtime info unavailable")
+	}
+	return fiberserver.ProbeHandler(m.runtimeInfo.Readiness)(c)
+}
+
+func (m *Module) handleLiveness(c fiber.Ctx) error {
+	if m.runtimeInfo == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, "runtime info unavailable")
+	}
+	return fiberserver.ProbeHandler(m.runtimeInfo.Liveness)(c)
+}
+
 // --- /goroutine (auth-gated) ---
 
 func (m *Module) handleGoroutine(c fiber.Ctx) error {
@@ -267,6 +338,65 @@ func (m *Module) handleGoroutine(c fiber.Ctx) error {
 	return oops.Wrapf(c.SendString(buf.String()), "failed to write response")
 }
 
+// --- /crashes (auth-gated) ---
+
+// CrashesResponse is the /crashes JSON contract, newest report first. The full
+// report, including stacks and the goroutine dump, is at /crashes/<id>.
+type CrashesResponse struct {
+	Reports []CrashSummary `json:"reports"`
+}
+
+// CrashSummary is one crash report without its dumps.
+type CrashSummary struct {
+	ID     string    `json:"id"`
+	Time   time.Time `json:"time"`
+	Module string    `json:"module"`
+	Step   string    `json:"step"`
+	Policy string    `json:"policy"`
+	Panic  string    `json:"panic"`
+}
+
+func (m *Module) handleCrashes(c fiber.Ctx) error {
+	if m.crashes == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, "crash reports unavailable")
+	}
+
+	reports, err := m.crashes.List()
+	if err != nil {
+		return oops.Wrapf(err, "failed to list crash reports")
+	}
+
+	summaries := make([]CrashSummary, 0, len(reports))
+	for _, r := range reports {
+		summaries = append(summaries, CrashSummary{
+			ID:     r.ID,
+			Time:   r.Time,
+			Module: r.Module,
+			Step:   string(r.Step),
+			Policy: r.Policy.String(),
+			Panic:  r.Panic,
+		})
+	}
+
+	return writeJSON(c, CrashesResponse{Reports: summaries})
+}
+
+func (m *Module) handleCrash(c fiber.Ctx) error {
+	if m.crashes == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, "crash reports unavailable")
+	}
+
+	report, err := m.crashes.Get(c.Params("id"))
+	if errors.Is(err, lakta.ErrCrashReportNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "crash report not found")
+	}
+	if err != nil {
+		return oops.Wrapf(err, "failed to read crash report")
+	}
+
+	return writeJSON(c, report)
+}
+
 // --- /vars (auth-gated expvar) ---
 
 func (m *Module) handleExpvar(c fiber.Ctx) error {
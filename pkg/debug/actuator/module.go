@@ -42,6 +42,7 @@ type Module struct {
 
 	// Optional dependencies resolved during Init; nil when the source is absent.
 	runtimeInfo     *lakta.RuntimeInfo
+	crashes         *lakta.CrashReports
 	health          *health.Health
 	koanf           *koanf.Koanf
 	routes          *fiberserver.RoutesRegistry
@@ -68,6 +69,11 @@ func (m *Module) LoadConfig(k *koanf.Koanf) error {
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
 // Init resolves optional sources from DI, builds the private *fiber.App (or the
 // WithMount target), registers all endpoint handlers under BasePath, and applies
 // the fail-closed security model. No-op when Enabled is false.
@@ -82,8 +88,9 @@ func (m *Module) Init(ctx context.Context) error {
 	}
 	m.redactor = redactor
 
-	m.injector = lakta.GetInjector(ctx)
+	m.injector = lakta.SharedInjector(ctx)
 	m.runtimeInfo, _ = lakta.Invoke[*lakta.RuntimeInfo](ctx)
+	m.crashes, _ = lakta.Invoke[*lakta.CrashReports](ctx)
 	m.health, _ = lakta.Invoke[*health.Health](ctx)
 	m.koanf, _ = lakta.Invoke[*koanf.Koanf](ctx)
 	m.levelController, _ = lakta.Invoke[slogmod.LevelController](ctx)
@@ -142,6 +149,7 @@ func (m *Module) Start(ctx context.Context) error {
 	m.mu.Unlock()
 
 	slox.Info(ctx, "actuator server started", slog.String("address", m.addrPort.String()))
+	m.MarkReady()
 
 	serveErr := make(chan error, 1)
 	go func() {
@@ -156,6 +164,10 @@ func (m *Module) Start(ctx context.Context) error {
 	}
 }
 
+// NotifiesReady reports that an enabled actuator marks itself ready once its
+// listener is bound; a disabled one counts as started right away.
+func (m *Module) NotifiesReady() bool { return m.config.Enabled && m.app != nil }
+
 // Shutdown drains the private listener.
 func (m *Module) Shutdown(ctx context.Context) error {
 	if m.app == nil {
@@ -1,15 +1,22 @@
 package actuator
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/MarvinJWendt/testza"
+	"github.com/Vilsol/lakta/pkg/config"
 	fiberserver "github.com/Vilsol/lakta/pkg/http/fiber"
 	"github.com/Vilsol/lakta/pkg/lakta"
 	slogmod "github.com/Vilsol/lakta/pkg/logging/slog"
@@ -58,6 +65,34 @@ func TestConfigEndpointRedactsSecret(t *testing.T) {
 	testza.AssertEqual(t, "localhost", db["host"])
 }
 
+func TestConfigEndpointHidesResolvedSecrets(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "webhook-token")
+	testza.AssertNoError(t, os.WriteFile(secret, []byte("tok-123\n"), 0o600))
+	testza.AssertNoError(t, os.WriteFile(filepath.Join(dir, "lakta.yaml"),
+		[]byte("webhook:\n  target: https://hooks.example/${file:"+secret+"}\n"), 0o600))
+
+	h := testkit.NewHarness(t)
+	cfg := config.NewModule(config.WithConfigDirs(dir), config.WithEnvPrefix("LAKTATESTACTUATOR_"))
+	testza.AssertNoError(t, cfg.Init(h.Ctx()))
+
+	act := NewModule(WithEnabled(true))
+	act.config.ShowValues = ShowAlways
+	testza.AssertNoError(t, act.Init(h.Ctx()))
+
+	resp, err := act.app.Test(httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/debug/config?provenance=1", nil))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, http.StatusOK, resp.StatusCode)
+
+	out := decodeJSON[ConfigResponse](t, resp)
+	webhook, ok := out.Values["webhook"].(map[string]any)
+	testza.AssertTrue(t, ok)
+	testza.AssertEqual(t, "https://hooks.example/${file:"+secret+"}", webhook["target"])
+	testza.AssertEqual(t, config.OriginFile, out.Provenance["webhook.target"])
+}
+
 func TestRoutesEndpointAggregatesInstances(t *testing.T) {
 	t.Parallel()
 
@@ -121,6 +156,51 @@ func TestHealthEndpointProxyAndAbsent(t *testing.T) {
 	testza.AssertEqual(t, http.StatusOK, resp2.StatusCode)
 }
 
+func TestProbeEndpoints(t *testing.T) {
+	t.Parallel()
+
+	get := func(act *Module, path string) *http.Response {
+		resp, err := act.app.Test(httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil))
+		testza.AssertNoError(t, err)
+		return resp
+	}
+
+	// Absent: 501.
+	act, err := initModule(t, WithEnabled(true))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, http.StatusNotImplemented, get(act, "/debug/health/readiness").StatusCode)
+	testza.AssertEqual(t, http.StatusNotImplemented, get(act, "/debug/health/liveness").StatusCode)
+
+	// Healthy: UP.
+	h := testkit.NewHarness(t)
+	lakta.ProvideValue(h.Ctx(), &lakta.RuntimeInfo{})
+	act2 := NewModule(WithEnabled(true))
+	testza.AssertNoError(t, act2.Init(h.Ctx()))
+
+	resp := get(act2, "/debug/health/readiness")
+	testza.AssertEqual(t, http.StatusOK, resp.StatusCode)
+	testza.AssertEqual(t, fiberserver.ProbeUp, decodeJSON[fiberserver.ProbeResponse](t, resp).Status)
+
+	// A runtime whose module failed to start and has since shut down: DOWN.
+	failed := testkit.NewHarness(t)
+	failer := testkit.NewMockSyncModule()
+	failer.StartErr = errors.New("start boom")
+	testza.AssertNotNil(t, lakta.NewRuntime(failer).RunContext(failed.Ctx()))
+
+	act3 := NewModule(WithEnabled(true))
+	testza.AssertNoError(t, act3.Init(failed.Ctx()))
+
+	resp = get(act3, "/debug/health/liveness")
+	testza.AssertEqual(t, http.StatusServiceUnavailable, resp.StatusCode)
+	out := decodeJSON[fiberserver.ProbeResponse](t, resp)
+	testza.AssertEqual(t, fiberserver.ProbeDown, out.Status)
+	testza.AssertContains(t, out.Reason, "module failed")
+
+	resp = get(act3, "/debug/health/readiness")
+	testza.AssertEqual(t, http.StatusServiceUnavailable, resp.StatusCode)
+	testza.AssertContains(t, decodeJSON[fiberserver.ProbeResponse](t, resp).Reason, "runtime draining")
+}
+
 func TestLoggersEndpointFlipsLevel(t *testing.T) {
 	t.Parallel()
 
@@ -191,6 +271,14 @@ func TestModulesAndStartupEndpoints(t *testing.T) {
 	startup := decodeJSON[StartupResponse](t, resp2)
 	testza.AssertTrue(t, len(startup.Entries) >= 2)
 
+	inits := 0
+	for _, e := range startup.Timeline {
+		if e.Step == string(lakta.StepInit) {
+			inits++
+		}
+	}
+	testza.AssertTrue(t, inits >= 2)
+
 	_ = rh
 }
 
@@ -202,3 +290,61 @@ func TestDisabledModuleInert(t *testing.T) {
 	testza.AssertNil(t, act.app)
 	testza.AssertNil(t, act.Addr())
 }
+
+func TestCrashesEndpointsAfterSupervisedRestart(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	act := NewModule(WithEnabled(true), WithHost("127.0.0.1"), WithPort(0), WithAuth(passAuth))
+
+	var calls atomic.Int32
+	restarted := make(chan struct{})
+	worker := testkit.NewMockAsyncModule()
+	worker.OnStartAsync = func(ctx context.Context) error {
+		if calls.Add(1) == 1 {
+			panic("worker exploded")
+		}
+		close(restarted)
+		<-ctx.Done()
+		return nil
+	}
+
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{worker, act},
+		lakta.WithSupervision(worker, lakta.SupervisionPolicy{Restart: lakta.RestartOnFailure, Backoff: time.Millisecond}),
+		lakta.WithCrashReportDir(dir))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- rt.RunContext(ctx) }()
+	defer func() {
+		cancel()
+		testza.AssertNil(t, <-done)
+	}()
+
+	<-restarted
+	testza.AssertNil(t, rt.WaitReady(t.Context()))
+
+	get := func(path string) *http.Response {
+		resp, err := act.app.Test(httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil))
+		testza.AssertNoError(t, err)
+		return resp
+	}
+
+	resp := get("/debug/crashes")
+	testza.AssertEqual(t, http.StatusOK, resp.StatusCode)
+	list := decodeJSON[CrashesResponse](t, resp)
+	testza.AssertLen(t, list.Reports, 1)
+	testza.AssertEqual(t, "start", list.Reports[0].Step)
+	testza.AssertEqual(t, "restart", list.Reports[0].Policy)
+	testza.AssertContains(t, list.Reports[0].Panic, "worker exploded")
+
+	resp = get("/debug/crashes/" + list.Reports[0].ID)
+	testza.AssertEqual(t, http.StatusOK, resp.StatusCode)
+	report := decodeJSON[lakta.CrashReport](t, resp)
+	testza.AssertContains(t, report.Stack, "panic")
+	testza.AssertContains(t, report.Goroutines, "goroutine")
+	testza.AssertTrue(t, len(report.Modules) >= 2)
+
+	testza.AssertEqual(t, http.StatusNotFound, get("/debug/crashes/crash-20260101T000000.000000000Z-9").StatusCode)
+	testza.AssertEqual(t, http.StatusNotFound, get("/debug/crashes/..%2Fsecrets").StatusCode)
+}
@@ -4,22 +4,16 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/Vilsol/lakta/pkg/config"
 	"github.com/samber/oops"
 )
 
 // defaultKeyInner is the alternation of key substrings whose leaf values are
 // treated as secrets. Wrapped in a case-insensitive group by NewRedactor.
-const defaultKeyInner = `password|secret|token|key|credential|apikey|dsn|conn.*string`
+const defaultKeyInner = config.SecretKeyPattern
 
 // redactMask is the replacement for redacted values.
-const redactMask = "******"
-
-// Value-level scrubbers catch secrets embedded in string values under innocuous
-// keys (url, addr): URI userinfo and DSN/query password-style parameters.
-var (
-	uriUserinfoPattern = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://[^:/?#@\s]+:)([^@\s]+)(@)`)
-	dsnParamPattern    = regexp.MustCompile(`(?i)((?:password|secret|token)=)([^&\s;]+)`)
-)
+const redactMask = config.RedactMask
 
 // Redactor walks koanf-produced structures redacting secrets. Constructed via
 // [Config.SecretRedactor]/[NewRedactor]. Stateless after construction; safe for
@@ -123,9 +117,7 @@ func (r *Redactor) walk(v any, keyMatched, wholesale bool) any {
 // redactValue applies the value-level scrubber (URI userinfo, DSN/query params)
 // to a single string regardless of key.
 func (r *Redactor) redactValue(s string) string {
-	s = uriUserinfoPattern.ReplaceAllString(s, `${1}`+redactMask+`${3}`)
-	s = dsnParamPattern.ReplaceAllString(s, `${1}`+redactMask)
-	return s
+	return config.ScrubSecrets(s)
 }
 
 // isPassthroughKey reports whether a key names a config.Passthrough (,remain)
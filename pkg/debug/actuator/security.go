@@ -23,7 +23,7 @@ func (m *Module) applySecurity(ctx context.Context) error {
 	}
 
 	if m.config.isLoopback() {
-		slox.Warn(ctx, "actuator: no auth configured; sensitive endpoints (goroutine/pprof/vars/loggers) will reject all requests")
+		slox.Warn(ctx, "actuator: no auth configured; sensitive endpoints (goroutine/crashes/pprof/vars/loggers) will reject all requests")
 		return nil
 	}
 
@@ -50,6 +50,7 @@ func TestSecuritySensitiveEndpointsRejectWithoutAuth(t *testing.T) {
 		url    string
 	}{
 		{http.MethodGet, "/debug/goroutine"},
+		{http.MethodGet, "/debug/crashes"},
 		{http.MethodGet, "/debug/vars"},
 		{http.MethodGet, "/debug/pprof/"},
 		{http.MethodPost, "/debug/loggers"},
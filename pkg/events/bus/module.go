@@ -2,10 +2,13 @@ package bus
 
 import (
 	"context"
+	"errors"
+	"log/slog"
 	"reflect"
 
 	"github.com/Vilsol/lakta/pkg/config"
 	"github.com/Vilsol/lakta/pkg/lakta"
+	"github.com/Vilsol/slox"
 	"github.com/knadh/koanf/v2"
 )
 
@@ -36,13 +39,33 @@ func (m *Module) LoadConfig(k *koanf.Koanf) error {
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
-// Init creates the bus and provides it to the injector.
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
+// Init creates the bus and provides it to the injector. With a config module
+// in DI, the change log of every config reload is published as a
+// [config.ChangeLog] event.
 func (m *Module) Init(ctx context.Context) error {
 	m.bus = NewBus(m.config.BufferSize)
 	lakta.ProvideValue(ctx, m.bus)
+
+	if cfg, err := lakta.Invoke[*config.Module](ctx); err == nil {
+		cfg.OnChangeLog(m.publishChangeLog)
+	}
+
 	return nil
 }
 
+// publishChangeLog publishes a config change log. The config module cannot
+// drop the callback, so it is a no-op once the bus is closed.
+func (m *Module) publishChangeLog(ctx context.Context, entry config.ChangeLog) {
+	if err := Publish(ctx, m.bus, entry); err != nil && !errors.Is(err, ErrBusClosed) {
+		slox.Warn(ctx, "failed to publish config change log", slog.Any("error", err))
+	}
+}
+
 // Provides returns the types this module registers in DI.
 func (m *Module) Provides() []reflect.Type {
 	return []reflect.Type{
@@ -54,6 +77,7 @@ func (m *Module) Provides() []reflect.Type {
 func (m *Module) Dependencies() ([]reflect.Type, []reflect.Type) {
 	return nil, []reflect.Type{
 		reflect.TypeFor[*koanf.Koanf](),
+		reflect.TypeFor[*config.Module](),
 	}
 }
 
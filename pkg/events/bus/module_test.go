@@ -1,10 +1,13 @@
 package bus_test
 
 import (
+	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/MarvinJWendt/testza"
+	"github.com/Vilsol/lakta/pkg/config"
 	"github.com/Vilsol/lakta/pkg/events/bus"
 	"github.com/Vilsol/lakta/pkg/lakta"
 	"github.com/Vilsol/lakta/pkg/testkit"
@@ -50,3 +53,56 @@ func TestModule_ShutdownClosesBus(t *testing.T) {
 
 	testza.AssertErrorIs(t, bus.Publish(h.Ctx(), b, userCreated{ID: 1}), bus.ErrBusClosed)
 }
+
+// pushSource is a config source whose updates the test pushes.
+type pushSource struct {
+	updates chan map[string]any
+}
+
+func (s *pushSource) Name() string { return "push" }
+
+func (s *pushSource) Load(context.Context) (map[string]any, error) {
+	return map[string]any{"app.port": 80}, nil
+}
+
+func (s *pushSource) Watch(ctx context.Context, update func(data map[string]any) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case data := <-s.updates:
+			_ = update(data)
+		}
+	}
+}
+
+func TestModule_PublishesConfigChangeLog(t *testing.T) {
+	t.Parallel()
+	h := testkit.NewHarness(t)
+
+	source := &pushSource{updates: make(chan map[string]any)}
+	cfg := config.NewModule(config.WithConfigDirs(t.TempDir()), config.WithSource(source, config.AboveFlags))
+	testza.AssertNoError(t, cfg.Init(h.Ctx()))
+
+	m := bus.NewModule()
+	testza.AssertNoError(t, m.Init(h.Ctx()))
+
+	b, err := do.Invoke[*bus.Bus](lakta.GetInjector(h.Ctx()))
+	testza.AssertNoError(t, err)
+
+	got := make(chan config.ChangeLog, 1)
+	bus.Subscribe(b, func(_ context.Context, entry config.ChangeLog) error {
+		got <- entry
+		return nil
+	})
+
+	source.updates <- map[string]any{"app.port": 81}
+
+	select {
+	case entry := <-got:
+		testza.AssertEqual(t, "push", entry.Source)
+		testza.AssertEqual(t, config.Diff{{Key: "app.port", Kind: config.ChangeModified, Old: 80, New: 81}}, entry.Changes)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the change log")
+	}
+}
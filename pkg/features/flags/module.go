@@ -38,6 +38,11 @@ func (m *Module) LoadConfig(k *koanf.Koanf) error {
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
 // Init parses the flag definitions and provides [Flags] to the injector.
 func (m *Module) Init(ctx context.Context) error {
 	snap, err := parseSnapshot(m.config.Flags)
@@ -51,28 +56,44 @@ func (m *Module) Init(ctx context.Context) error {
 	return nil
 }
 
-// OnReload re-parses flag definitions from the reloaded config, keeping the
-// previous snapshot if the new one fails to parse.
+// OnReload prepares and commits a reload on its own, keeping the previous
+// snapshot if the new one fails to parse. The runtime uses PrepareReload
+// instead.
 // The OnReload contract provides no context, so use the default logger.
 func (m *Module) OnReload(k *koanf.Koanf) {
-	if m.flags == nil {
+	prepared, err := m.PrepareReload(k)
+	if err != nil {
+		slog.Error("failed to reload feature flags, keeping previous flags", slog.Any("error", err))
 		return
 	}
+	if prepared.Commit != nil {
+		prepared.Commit()
+	}
+}
+
+// PrepareReload re-parses flag definitions from the reloaded config; the
+// commit swaps the new snapshot in.
+func (m *Module) PrepareReload(k *koanf.Koanf) (lakta.PreparedReload, error) {
+	if m.flags == nil {
+		return lakta.PreparedReload{}, nil
+	}
 
 	// Fresh Config so stale flags from the previous load can't linger.
 	cfg := Config{Name: m.config.Name}
 	if err := cfg.LoadFromKoanf(k, m.ConfigPath()); err != nil {
-		slog.Error("failed to reload feature flags config, keeping previous flags", slog.Any("error", err))
-		return
+		return lakta.PreparedReload{}, oops.Wrapf(err, "failed to reload feature flags config")
 	}
 	snap, err := parseSnapshot(cfg.Flags)
 	if err != nil {
-		slog.Error("failed to parse reloaded feature flags, keeping previous flags", slog.Any("error", err))
-		return
+		return lakta.PreparedReload{}, oops.Wrapf(err, "failed to parse reloaded feature flags")
 	}
 
-	m.config = cfg
-	m.flags.swap(snap)
+	return lakta.PreparedReload{
+		Commit: func() {
+			m.config = cfg
+			m.flags.swap(snap)
+		},
+	}, nil
 }
 
 // Provides returns the types this module registers in DI.
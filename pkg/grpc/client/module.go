@@ -38,6 +38,11 @@ func (m *Module) LoadConfig(k *koanf.Koanf) error {
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
 // Init loads configuration, creates the gRPC connection, and registers typed clients.
 func (m *Module) Init(ctx context.Context) error {
 	// Create connection using config-provided options
@@ -2,6 +2,7 @@ package grpcserver
 
 import (
 	"context"
+	"sync/atomic"
 
 	"github.com/Vilsol/lakta/pkg/lakta"
 	"github.com/hellofresh/health-go/v5"
@@ -15,13 +16,20 @@ type healthServer struct {
 	healthpb.UnimplementedHealthServer
 
 	runtimeContext context.Context //nolint:containedctx
+	draining       *atomic.Bool
 }
 
-func newHealthServer(ctx context.Context) *healthServer {
-	return &healthServer{runtimeContext: ctx}
+func newHealthServer(ctx context.Context, draining *atomic.Bool) *healthServer {
+	return &healthServer{runtimeContext: ctx, draining: draining}
 }
 
 func (s *healthServer) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if s.draining.Load() {
+		return &healthpb.HealthCheckResponse{
+			Status: healthpb.HealthCheckResponse_NOT_SERVING,
+		}, nil
+	}
+
 	h, err := do.Invoke[*health.Health](lakta.GetInjector(s.runtimeContext))
 	if err != nil {
 		return &healthpb.HealthCheckResponse{
@@ -3,12 +3,14 @@ package grpcserver_test
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/MarvinJWendt/testza"
 	apperrors "github.com/Vilsol/lakta/pkg/errors"
 	errgrpc "github.com/Vilsol/lakta/pkg/errors/grpc"
 	grpcserver "github.com/Vilsol/lakta/pkg/grpc/server"
+	"github.com/Vilsol/lakta/pkg/lakta"
 	"github.com/Vilsol/lakta/pkg/testkit"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
@@ -37,6 +39,31 @@ func (okHealthServer) Check(context.Context, *healthpb.HealthCheckRequest) (*hea
 	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
 }
 
+// requestTx is a request-scoped service counting its disposals.
+type requestTx struct {
+	disposed *atomic.Int32
+}
+
+func (tx *requestTx) Shutdown() { tx.disposed.Add(1) }
+
+// scopedHealthServer reports SERVING when the request scope hands it the same
+// *requestTx twice.
+type scopedHealthServer struct {
+	healthpb.UnimplementedHealthServer
+}
+
+func (scopedHealthServer) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	first, err := lakta.Invoke[*requestTx](ctx)
+	if err != nil {
+		return nil, err
+	}
+	second, err := lakta.Invoke[*requestTx](ctx)
+	if err != nil || first != second {
+		return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING}, nil
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
 func dialServer(t *testing.T, m *grpcserver.Module) healthpb.HealthClient { //nolint:ireturn // grpc client is inherently an interface
 	t.Helper()
 	addr := testkit.WaitForAddr(t, m)
@@ -74,6 +101,36 @@ func TestGRPCServer_ErrgrpcRendersAppError(t *testing.T) {
 	testza.AssertEqual(t, "user", info.GetMetadata()["resource"])
 }
 
+func TestGRPCServer_RequestScope(t *testing.T) {
+	t.Parallel()
+
+	var disposed atomic.Int32
+
+	provider := testkit.NewMockModule()
+	provider.OnInit = func(ctx context.Context) error {
+		lakta.ProvideRequest(ctx, func(context.Context) (*requestTx, error) {
+			return &requestTx{disposed: &disposed}, nil
+		})
+		return nil
+	}
+
+	m := grpcserver.NewModule(
+		grpcserver.WithHost("127.0.0.1"),
+		grpcserver.WithPort(0),
+		grpcserver.WithService(&healthpb.Health_ServiceDesc, scopedHealthServer{}),
+	)
+	testkit.NewRuntimeHarness(t, provider, m)
+
+	client := dialServer(t, m)
+	for range 2 {
+		resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		testza.AssertNil(t, err)
+		testza.AssertEqual(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	}
+
+	testza.AssertEqual(t, int32(2), disposed.Load())
+}
+
 func TestGRPCServer_UnaryInterceptorOrder(t *testing.T) {
 	t.Parallel()
 
@@ -7,6 +7,7 @@ import (
 	"net/netip"
 	"reflect"
 	"sync"
+	"sync/atomic"
 
 	"github.com/Vilsol/lakta/pkg/config"
 	apperrors "github.com/Vilsol/lakta/pkg/errors"
@@ -38,6 +39,7 @@ type Module struct {
 	listener    net.Listener
 	serveCtx    context.Context //nolint:containedctx // cancelled on shutdown deadline to drain in-flight handlers
 	cancelServe context.CancelFunc
+	draining    atomic.Bool
 }
 
 // serveContext lazily derives a cancellable child of the runtime context the
@@ -74,18 +76,25 @@ func (m *Module) LoadConfig(k *koanf.Koanf) error {
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
 // Init loads configuration and creates the gRPC server with interceptors.
 func (m *Module) Init(ctx context.Context) error {
 	contextInjector := func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		span := trace.SpanFromContext(ctx)
-		runtimeCtx := trace.ContextWithSpan(m.serveContext(), span)
-		return handler(runtimeCtx, req)
+		reqCtx, end := lakta.BeginRequest(trace.ContextWithSpan(m.serveContext(), span))
+		defer end()
+		return handler(reqCtx, req)
 	}
 
 	streamContextInjector := func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		span := trace.SpanFromContext(ss.Context())
-		runtimeCtx := trace.ContextWithSpan(m.serveContext(), span)
-		return handler(srv, &contextServerStream{ServerStream: ss, ctx: runtimeCtx})
+		reqCtx, end := lakta.BeginRequest(trace.ContextWithSpan(m.serveContext(), span))
+		defer end()
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: reqCtx})
 	}
 
 	interceptorLogger := func() logging.Logger {
@@ -154,7 +163,7 @@ func (m *Module) Init(ctx context.Context) error {
 // Start begins listening and serving gRPC requests.
 func (m *Module) Start(ctx context.Context) error {
 	if m.config.HealthCheck {
-		healthpb.RegisterHealthServer(m.server, newHealthServer(ctx))
+		healthpb.RegisterHealthServer(m.server, newHealthServer(ctx, &m.draining))
 	}
 
 	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", m.addrPort.String())
@@ -167,6 +176,7 @@ func (m *Module) Start(ctx context.Context) error {
 	m.mu.Unlock()
 
 	slox.Info(ctx, "gRPC server started", slog.String("address", m.addrPort.String()))
+	m.MarkReady()
 
 	var wg errgroup.Group
 	wg.Go(func() error {
@@ -197,6 +207,17 @@ func (m *Module) Dependencies() ([]reflect.Type, []reflect.Type) {
 	}
 }
 
+// NotifiesReady reports that the server marks itself ready once its listener
+// is bound.
+func (m *Module) NotifiesReady() bool { return true }
+
+// Drain keeps serving in-flight and new RPCs but reports NOT_SERVING from the
+// health service, so health-checking load balancers stop routing here.
+func (m *Module) Drain(_ context.Context) error {
+	m.draining.Store(true)
+	return nil
+}
+
 // Shutdown gracefully stops the gRPC server. If the context deadline is
 // exceeded before in-flight RPCs drain, it cancels their handler contexts so
 // they return and GracefulStop completes. It deliberately never calls Stop()
@@ -87,6 +87,32 @@ func TestGRPCServerModule_HealthCheck_WithHealth(t *testing.T) {
 	testza.AssertEqual(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
 }
 
+func TestGRPCServerModule_DrainReportsNotServing(t *testing.T) {
+	t.Parallel()
+
+	healthM := health.NewModule()
+	serverM := grpcserver.NewModule(
+		grpcserver.WithHost("127.0.0.1"),
+		grpcserver.WithPort(0),
+		grpcserver.WithHealthCheck(true),
+	)
+
+	testkit.NewRuntimeHarness(t, healthM, serverM)
+
+	addr := testkit.WaitForAddr(t, serverM)
+
+	conn, err := grpc.NewClient(addr.String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testza.AssertNil(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	testza.AssertNil(t, serverM.Drain(context.Background()))
+
+	// Still serving RPCs, but the health service tells balancers to move on.
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	testza.AssertNil(t, err)
+	testza.AssertEqual(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+}
+
 func TestGRPCServerModule_ConfigPath(t *testing.T) {
 	t.Parallel()
 
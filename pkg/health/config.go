@@ -18,6 +18,12 @@ type Config struct {
 	// ComponentVersion represents the version of the component.
 	ComponentVersion string `koanf:"component_version"`
 
+	// RuntimeChecks registers liveness and readiness checks derived from lakta.RuntimeInfo module states.
+	// Off by default: the readiness check fails while modules boot and once
+	// shutdown begins, which makes /health partial and the gRPC health service
+	// NOT_SERVING during those windows.
+	RuntimeChecks bool `koanf:"runtime_checks"`
+
 	// Checks defines a list of health check configurations for the module.
 	Checks []health.Config `code_only:"WithCheck" koanf:"-"`
 }
@@ -28,6 +34,7 @@ func NewDefaultConfig() Config {
 		Name:             config.DefaultInstanceName,
 		ComponentName:    "",
 		ComponentVersion: "",
+		RuntimeChecks:    false,
 		Checks:           nil,
 	}
 }
@@ -68,6 +75,13 @@ func WithComponentVersion(version string) Option {
 	return func(m *Config) { m.ComponentVersion = version }
 }
 
+// WithRuntimeChecks enables or disables the runtime liveness and readiness
+// checks (off by default). Enabled, /health and the gRPC health service also
+// report not ready while modules boot and from the moment shutdown begins.
+func WithRuntimeChecks(enabled bool) Option {
+	return func(m *Config) { m.RuntimeChecks = enabled }
+}
+
 // WithCheck adds a health check to be registered on initialization (code-only).
 func WithCheck(check health.Config) Option {
 	return func(m *Config) {
@@ -38,6 +38,11 @@ func (m *Module) LoadConfig(k *koanf.Koanf) error {
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
 // Init creates the health instance and provides it to the injector
 func (m *Module) Init(ctx context.Context) error {
 	opts := make([]health.Option, 0, 1+len(m.config.Checks))
@@ -54,6 +59,14 @@ func (m *Module) Init(ctx context.Context) error {
 
 	m.health = h
 
+	if m.config.RuntimeChecks {
+		if info, err := lakta.Invoke[*lakta.RuntimeInfo](ctx); err == nil {
+			if err := registerRuntimeChecks(h, info); err != nil {
+				return err
+			}
+		}
+	}
+
 	lakta.ProvideValue(ctx, m.health)
 
 	return nil
@@ -70,6 +83,7 @@ func (m *Module) Provides() []reflect.Type {
 func (m *Module) Dependencies() ([]reflect.Type, []reflect.Type) {
 	return nil, []reflect.Type{
 		reflect.TypeFor[*koanf.Koanf](),
+		reflect.TypeFor[*lakta.RuntimeInfo](),
 	}
 }
 
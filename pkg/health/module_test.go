@@ -7,6 +7,7 @@ import (
 
 	"github.com/MarvinJWendt/testza"
 	"github.com/Vilsol/lakta/pkg/health"
+	"github.com/Vilsol/lakta/pkg/lakta"
 	"github.com/Vilsol/lakta/pkg/testkit"
 	healthgo "github.com/hellofresh/health-go/v5"
 	"github.com/samber/do/v2"
@@ -122,3 +123,56 @@ func TestHealthModule_NoKoanfSucceeds(t *testing.T) {
 	h := testkit.NewHarness(t)
 	testza.AssertNil(t, health.NewModule().Init(h.Ctx()))
 }
+
+func TestHealthModule_RuntimeChecksFollowReadiness(t *testing.T) {
+	t.Parallel()
+
+	var atInit, atStart healthgo.Status
+
+	measure := func(ctx context.Context) (healthgo.Status, error) {
+		instance, err := lakta.Invoke[*healthgo.Health](ctx)
+		if err != nil {
+			return "", err
+		}
+		return instance.Measure(ctx).Status, nil
+	}
+
+	server := testkit.NewMockSyncModule()
+	server.OnInit = func(ctx context.Context) error {
+		var err error
+		atInit, err = measure(ctx)
+		return err
+	}
+	server.OnStart = func(ctx context.Context) error {
+		var err error
+		atStart, err = measure(ctx)
+		return err
+	}
+
+	rh := testkit.NewRuntimeHarness(t, health.NewModule(health.WithRuntimeChecks(true)), server)
+	testza.AssertNil(t, rh.Shutdown())
+
+	testza.AssertEqual(t, healthgo.StatusPartiallyAvailable, atInit)
+	testza.AssertEqual(t, healthgo.StatusOK, atStart)
+}
+
+func TestHealthModule_RuntimeChecksOffByDefault(t *testing.T) {
+	t.Parallel()
+
+	var atInit healthgo.Status
+
+	server := testkit.NewMockSyncModule()
+	server.OnInit = func(ctx context.Context) error {
+		instance, err := lakta.Invoke[*healthgo.Health](ctx)
+		if err != nil {
+			return err
+		}
+		atInit = instance.Measure(ctx).Status
+		return nil
+	}
+
+	rh := testkit.NewRuntimeHarness(t, health.NewModule(), server)
+	testza.AssertNil(t, rh.Shutdown())
+
+	testza.AssertEqual(t, healthgo.StatusOK, atInit)
+}
@@ -0,0 +1,37 @@
+package health
+
+import (
+	"context"
+
+	"github.com/Vilsol/lakta/pkg/lakta"
+	"github.com/hellofresh/health-go/v5"
+	"github.com/samber/oops"
+)
+
+// Check names registered when Config.RuntimeChecks is enabled.
+const (
+	LivenessCheckName  = "runtime.liveness"
+	ReadinessCheckName = "runtime.readiness"
+)
+
+// registerRuntimeChecks wires info's Liveness and Readiness into h. Liveness
+// is a hard failure; readiness is SkipOnErr so a booting or draining runtime
+// reports "Partially Available" rather than unavailable.
+func registerRuntimeChecks(h *health.Health, info *lakta.RuntimeInfo) error {
+	if err := h.Register(health.Config{
+		Name:  LivenessCheckName,
+		Check: func(context.Context) error { return info.Liveness() },
+	}); err != nil {
+		return oops.Wrapf(err, "failed to register liveness check")
+	}
+
+	if err := h.Register(health.Config{
+		Name:      ReadinessCheckName,
+		SkipOnErr: true,
+		Check:     func(context.Context) error { return info.Readiness() },
+	}); err != nil {
+		return oops.Wrapf(err, "failed to register readiness check")
+	}
+
+	return nil
+}
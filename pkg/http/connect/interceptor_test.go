@@ -3,7 +3,9 @@ package connect_test
 import (
 	"context"
 	stderrors "errors"
+	"fmt"
 	"net/http"
+	"sync/atomic"
 	"testing"
 
 	"connectrpc.com/connect"
@@ -140,6 +142,113 @@ func TestConnectModule_WithServiceDIResolution(t *testing.T) {
 	testza.AssertEqual(t, "Hi there", resp.Msg.GetMessage())
 }
 
+// requestGreeter is a request-scoped greeter counting its disposals.
+type requestGreeter struct {
+	prefix   string
+	disposed *atomic.Int32
+}
+
+func (g *requestGreeter) Shutdown() { g.disposed.Add(1) }
+
+// scopedEchoServer greets with the request's *requestGreeter, failing unless
+// both lookups in one call return the same instance.
+type scopedEchoServer struct{}
+
+func (scopedEchoServer) Echo(ctx context.Context, req *connect.Request[testv1.EchoRequest]) (*connect.Response[testv1.EchoResponse], error) {
+	first, err := lakta.Invoke[*requestGreeter](ctx)
+	if err != nil {
+		return nil, err
+	}
+	second, err := lakta.Invoke[*requestGreeter](ctx)
+	if err != nil || first != second {
+		return nil, connect.NewError(connect.CodeInternal, stderrors.New("request scope not shared"))
+	}
+	return connect.NewResponse(&testv1.EchoResponse{Message: first.prefix + req.Msg.GetMessage()}), nil
+}
+
+// TestConnectModule_RequestScope asserts handlers resolve request-scoped
+// services from their context, disposed of once the call ends.
+func TestConnectModule_RequestScope(t *testing.T) {
+	t.Parallel()
+
+	var created, disposed atomic.Int32
+
+	h := testkit.NewHarness(t)
+	lakta.ProvideRequest(h.Ctx(), func(context.Context) (*requestGreeter, error) {
+		return &requestGreeter{prefix: fmt.Sprintf("#%d ", created.Add(1)), disposed: &disposed}, nil
+	})
+
+	m := connectmod.NewModule(
+		connectmod.WithHost("127.0.0.1"),
+		connectmod.WithPort(0),
+		connectmod.WithService(func(_ context.Context, opts []connect.HandlerOption) (string, http.Handler) {
+			return testv1connect.NewEchoServiceHandler(scopedEchoServer{}, opts...)
+		}),
+	)
+
+	testza.AssertNil(t, m.Init(h.Ctx()))
+	go func() { _ = m.Start(context.Background()) }()
+	t.Cleanup(func() { _ = m.Shutdown(context.Background()) })
+	addr := testkit.WaitForAddr(t, m).String()
+
+	client := testv1connect.NewEchoServiceClient(h2cClient(), "http://"+addr)
+	for _, want := range []string{"#1 there", "#2 there"} {
+		resp, err := client.Echo(context.Background(), connect.NewRequest(&testv1.EchoRequest{Message: "there"}))
+		testza.AssertNil(t, err)
+		testza.AssertEqual(t, want, resp.Msg.GetMessage())
+	}
+
+	testza.AssertEqual(t, int32(2), disposed.Load())
+}
+
+// providedGreeting is what providingEchoServer publishes from a handler.
+type providedGreeting struct {
+	message string
+}
+
+// providingEchoServer registers the request message with lakta.ProvideValue.
+type providingEchoServer struct{}
+
+func (providingEchoServer) Echo(ctx context.Context, req *connect.Request[testv1.EchoRequest]) (*connect.Response[testv1.EchoResponse], error) {
+	lakta.ProvideValue(ctx, &providedGreeting{message: req.Msg.GetMessage()})
+	return connect.NewResponse(&testv1.EchoResponse{Message: req.Msg.GetMessage()}), nil
+}
+
+// TestConnectModule_ProvideInRequestIsShared asserts lakta.Provide inside a
+// handler registers runtime-wide, as it does in fiber and gRPC handlers,
+// rather than into the module's own scope.
+func TestConnectModule_ProvideInRequestIsShared(t *testing.T) {
+	t.Parallel()
+
+	m := connectmod.NewModule(
+		connectmod.WithHost("127.0.0.1"),
+		connectmod.WithPort(0),
+		connectmod.WithService(func(_ context.Context, opts []connect.HandlerOption) (string, http.Handler) {
+			return testv1connect.NewEchoServiceHandler(providingEchoServer{}, opts...)
+		}),
+	)
+
+	injector := do.New()
+	ctx, cancel := context.WithCancel(lakta.WithInjector(t.Context(), injector))
+	defer cancel()
+
+	r := lakta.NewRuntime(m)
+	done := make(chan error, 1)
+	go func() { done <- r.RunContext(ctx) }()
+	testza.AssertNil(t, r.WaitReady(t.Context()))
+
+	client := testv1connect.NewEchoServiceClient(h2cClient(), "http://"+m.Addr().String())
+	_, err := client.Echo(t.Context(), connect.NewRequest(&testv1.EchoRequest{Message: "there"}))
+	testza.AssertNil(t, err)
+
+	greeting, err := do.Invoke[*providedGreeting](injector)
+	testza.AssertNil(t, err)
+	testza.AssertEqual(t, "there", greeting.message)
+
+	cancel()
+	testza.AssertNil(t, <-done)
+}
+
 // TestConnectModule_WithHandlerMountsAsIs asserts a pre-built WithHandler entry
 // is mounted verbatim with no interceptor chain injected.
 func TestConnectModule_WithHandlerMountsAsIs(t *testing.T) {
@@ -11,7 +11,9 @@ import (
 	"connectrpc.com/connect"
 	"connectrpc.com/otelconnect"
 	apperrors "github.com/Vilsol/lakta/pkg/errors"
+	"github.com/Vilsol/lakta/pkg/lakta"
 	"github.com/Vilsol/slox"
+	"github.com/samber/do/v2"
 	"github.com/samber/oops"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/protobuf/proto"
@@ -30,6 +32,40 @@ func otelInterceptor() connect.Interceptor { //nolint:ireturn // connect.Interce
 	return oi
 }
 
+// requestScopeInterceptor hands each call the module's injectors and a
+// lakta.BeginRequest scope ended once the handler returns, mirroring the
+// context grpc/server and http/fiber give their handlers: Invoke resolves the
+// module's private services and Provide still registers runtime-wide.
+type requestScopeInterceptor struct {
+	injector do.Injector
+	shared   do.Injector
+}
+
+// context returns ctx carrying the module's injectors.
+func (i requestScopeInterceptor) context(ctx context.Context) context.Context {
+	return lakta.WithModuleInjectors(ctx, i.injector, i.shared)
+}
+
+func (i requestScopeInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		reqCtx, end := lakta.BeginRequest(i.context(ctx))
+		defer end()
+		return next(reqCtx, req)
+	}
+}
+
+func (requestScopeInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i requestScopeInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		reqCtx, end := lakta.BeginRequest(i.context(ctx))
+		defer end()
+		return next(reqCtx, conn)
+	}
+}
+
 // loggingInterceptor logs each finished unary call, mirroring grpc/server's
 // logging.FinishCall event.
 func loggingInterceptor() connect.Interceptor { //nolint:ireturn // connect.Interceptor is the library's composition unit
@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"sync"
 
+	"connectrpc.com/connect"
 	"github.com/Vilsol/lakta/pkg/config"
 	"github.com/Vilsol/lakta/pkg/lakta"
 	"github.com/Vilsol/slox"
@@ -49,11 +50,19 @@ func (m *Module) ConfigPath() string {
 // LoadConfig loads configuration from koanf.
 func (m *Module) LoadConfig(k *koanf.Koanf) error { return m.config.LoadFromKoanf(k, m.ConfigPath()) }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any { return &m.config }
+
 // Init builds the ServeMux, mounts WithHandler entries as-is and passes the
 // shared []connect.HandlerOption chain into each WithService registrar.
 func (m *Module) Init(ctx context.Context) error {
 	mux := http.NewServeMux()
 	opts := m.config.Interceptors()
+	if lakta.HasInjector(ctx) {
+		// Outermost, so every later interceptor already sees the request scope.
+		scope := requestScopeInterceptor{injector: lakta.GetInjector(ctx), shared: lakta.SharedInjector(ctx)}
+		opts = append([]connect.HandlerOption{connect.WithInterceptors(scope)}, opts...)
+	}
 
 	for path, h := range m.config.Handlers {
 		mux.Handle(path, h)
@@ -106,6 +115,7 @@ func (m *Module) Start(ctx context.Context) error {
 	m.mu.Unlock()
 
 	slox.Info(ctx, "connect server started", slog.String("address", m.addrPort.String()))
+	m.MarkReady()
 
 	var wg errgroup.Group
 	wg.Go(func() error {
@@ -134,6 +144,24 @@ func (m *Module) Start(ctx context.Context) error {
 	}
 }
 
+// NotifiesReady reports that the server marks itself ready once its listener
+// is bound.
+func (m *Module) NotifiesReady() bool { return true }
+
+// Drain keeps the listener open but disables keep-alives, so HTTP/1.1 clients
+// reconnect (to another replica) after their current request.
+func (m *Module) Drain(_ context.Context) error {
+	m.mu.Lock()
+	srv := m.server
+	m.mu.Unlock()
+
+	if srv != nil {
+		srv.SetKeepAlivesEnabled(false)
+	}
+
+	return nil
+}
+
 // Shutdown drains in-flight requests via http.Server.Shutdown raced against the
 // runtime's 30s deadline (net/http analogue of grpc GracefulStop).
 func (m *Module) Shutdown(ctx context.Context) error {
@@ -156,6 +156,32 @@ func TestConnectModule_GracefulDrain(t *testing.T) {
 	testza.AssertNil(t, <-shutDone)
 }
 
+func TestConnectModule_DrainClosesConnections(t *testing.T) {
+	t.Parallel()
+
+	m := echoModule(t, "")
+	addr := testkit.WaitForAddr(t, m).String()
+
+	echo := func() *http.Response {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost,
+			"http://"+addr+"/test.v1.EchoService/Echo", strings.NewReader(`{"message":"hi"}`))
+		testza.AssertNil(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		testza.AssertNil(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	testza.AssertFalse(t, echo().Close)
+
+	testza.AssertNil(t, m.Drain(context.Background()))
+
+	resp := echo()
+	testza.AssertEqual(t, http.StatusOK, resp.StatusCode)
+	testza.AssertTrue(t, resp.Close)
+}
+
 type slowEcho struct {
 	entered chan struct{}
 	release chan struct{}
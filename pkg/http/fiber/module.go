@@ -8,6 +8,7 @@ import (
 	"net/netip"
 	"reflect"
 	"sync"
+	"sync/atomic"
 
 	"github.com/Vilsol/lakta/pkg/config"
 	"github.com/Vilsol/lakta/pkg/lakta"
@@ -36,6 +37,7 @@ type Module struct {
 
 	mu       sync.Mutex
 	listener net.Listener
+	draining atomic.Bool
 }
 
 // NewModule creates a new Fiber HTTP server module with the given options.
@@ -57,6 +59,11 @@ func (m *Module) LoadConfig(k *koanf.Koanf) error {
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
 // Init loads configuration, creates the Fiber app, and registers middleware and routes.
 func (m *Module) Init(ctx context.Context) error {
 	app := fiber.New(m.config.ToFiberConfig())
@@ -66,17 +73,27 @@ func (m *Module) Init(ctx context.Context) error {
 		return nil
 	})
 
+	// While draining, ask clients to close keep-alive connections so their
+	// next request lands on another replica before the listener closes.
+	app.Use(func(c fiber.Ctx) error {
+		if m.draining.Load() {
+			c.Set(fiber.HeaderConnection, "close")
+		}
+		return c.Next()
+	})
+
 	app.Use(recover.New(recover.Config{
 		EnableStackTrace: true,
 	}))
 
 	app.Use(otelfiber.Middleware())
 
-	// Inject context
+	// Inject context, with a request scope disposed of once the handler returns
 	app.Use(func(c fiber.Ctx) error {
 		span := trace.SpanFromContext(c.Context())
-		runtimeCtx := trace.ContextWithSpan(m.RuntimeCtx(), span)
-		c.SetContext(runtimeCtx)
+		reqCtx, end := lakta.BeginRequest(trace.ContextWithSpan(m.RuntimeCtx(), span))
+		defer end()
+		c.SetContext(reqCtx)
 		return c.Next()
 	})
 
@@ -120,6 +137,12 @@ func (m *Module) Start(ctx context.Context) error {
 			return oops.Wrapf(err, "failed to get health instance")
 		}
 		m.server.Get(m.config.HealthPath, adaptor.HTTPHandlerFunc(h.HandlerFunc))
+
+		// Probe endpoints backed by module states, for Kubernetes liveness and readiness.
+		if info, err := lakta.Invoke[*lakta.RuntimeInfo](ctx); err == nil {
+			m.server.Get(m.config.HealthPath+LivenessPath, ProbeHandler(info.Liveness))
+			m.server.Get(m.config.HealthPath+ReadinessPath, ProbeHandler(info.Readiness))
+		}
 	}
 
 	// Routes are fully registered by now; publish this instance's snapshot to
@@ -151,6 +174,7 @@ func (m *Module) Start(ctx context.Context) error {
 
 	slox.Info(ctx, "fiber http server started",
 		slog.String("address", m.addrPort.String()), slog.String("scheme", scheme))
+	m.MarkReady()
 
 	var wg errgroup.Group
 
@@ -182,6 +206,16 @@ func (m *Module) Dependencies() ([]reflect.Type, []reflect.Type) {
 	}
 }
 
+// NotifiesReady reports that the server marks itself ready once its listener
+// is bound.
+func (m *Module) NotifiesReady() bool { return true }
+
+// Drain keeps serving but closes each connection after its current response.
+func (m *Module) Drain(_ context.Context) error {
+	m.draining.Store(true)
+	return nil
+}
+
 // Shutdown gracefully drains in-flight requests, honoring the context deadline.
 func (m *Module) Shutdown(ctx context.Context) error {
 	if m.server == nil {
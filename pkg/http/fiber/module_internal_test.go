@@ -14,10 +14,12 @@ import (
 )
 
 var (
-	_ lakta.SyncModule   = (*Module)(nil)
-	_ lakta.Configurable = (*Module)(nil)
-	_ lakta.NamedModule  = (*Module)(nil)
-	_ lakta.Dependent    = (*Module)(nil)
+	_ lakta.SyncModule    = (*Module)(nil)
+	_ lakta.Drainable     = (*Module)(nil)
+	_ lakta.ReadyNotifier = (*Module)(nil)
+	_ lakta.Configurable  = (*Module)(nil)
+	_ lakta.NamedModule   = (*Module)(nil)
+	_ lakta.Dependent     = (*Module)(nil)
 )
 
 func injectRuntimeCtx(m *Module, ctx context.Context) {
@@ -2,9 +2,12 @@ package fiberserver_test
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net"
 	"net/http"
+	"strconv"
+	"sync/atomic"
 	"testing"
 
 	"github.com/MarvinJWendt/testza"
@@ -51,9 +54,9 @@ func TestFiberModule_Listens(t *testing.T) {
 		}),
 	)
 
-	testkit.NewRuntimeHarness(t, m)
+	testkit.NewRuntimeHarness(t, m).WaitReady()
 
-	addr := testkit.WaitForAddr(t, m)
+	addr := m.Addr()
 	testza.AssertNotNil(t, addr)
 	testza.AssertNotEqual(t, "", addr.String())
 }
@@ -86,6 +89,39 @@ func TestFiberModule_ServesRoute(t *testing.T) {
 	testza.AssertEqual(t, "pong", string(body))
 }
 
+func TestFiberModule_DrainClosesConnections(t *testing.T) {
+	t.Parallel()
+
+	m := fiberserver.NewModule(
+		fiberserver.WithHost("127.0.0.1"),
+		fiberserver.WithPort(0),
+		fiberserver.WithRouter(func(app *fiber.App) {
+			app.Get("/ping", func(c fiber.Ctx) error {
+				return c.SendString("pong")
+			})
+		}),
+	)
+
+	testkit.NewRuntimeHarness(t, m)
+
+	addr := testkit.WaitForAddr(t, m)
+
+	get := func() *http.Response {
+		resp, err := http.Get("http://" + addr.String() + "/ping") //nolint:noctx
+		testza.AssertNil(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	testza.AssertFalse(t, get().Close)
+
+	testza.AssertNil(t, m.Drain(context.Background()))
+
+	resp := get()
+	testza.AssertEqual(t, http.StatusOK, resp.StatusCode)
+	testza.AssertTrue(t, resp.Close)
+}
+
 func TestFiberModule_HealthPath(t *testing.T) {
 	t.Parallel()
 
@@ -106,6 +142,18 @@ func TestFiberModule_HealthPath(t *testing.T) {
 	t.Cleanup(func() { _ = resp.Body.Close() })
 
 	testza.AssertEqual(t, http.StatusOK, resp.StatusCode)
+
+	for _, probe := range []string{"/health" + fiberserver.LivenessPath, "/health" + fiberserver.ReadinessPath} {
+		probeResp, probeErr := http.Get("http://" + addr.String() + probe) //nolint:noctx
+		testza.AssertNil(t, probeErr)
+		t.Cleanup(func() { _ = probeResp.Body.Close() })
+
+		testza.AssertEqual(t, http.StatusOK, probeResp.StatusCode, probe)
+
+		var body fiberserver.ProbeResponse
+		testza.AssertNoError(t, json.NewDecoder(probeResp.Body).Decode(&body))
+		testza.AssertEqual(t, fiberserver.ProbeUp, body.Status, probe)
+	}
 }
 
 type ctxGreeting struct{ msg string }
@@ -161,6 +209,63 @@ func TestFiberModule_RouterCtxResolvesDI(t *testing.T) {
 	}
 }
 
+// requestCounter is a request-scoped service counting its disposals.
+type requestCounter struct {
+	id       int32
+	disposed *atomic.Int32
+}
+
+func (c *requestCounter) Shutdown() { c.disposed.Add(1) }
+
+func TestFiberModule_RequestScope(t *testing.T) {
+	t.Parallel()
+
+	var created, disposed atomic.Int32
+
+	provider := testkit.NewMockModule()
+	provider.OnInit = func(ctx context.Context) error {
+		lakta.ProvideRequest(ctx, func(context.Context) (*requestCounter, error) {
+			return &requestCounter{id: created.Add(1), disposed: &disposed}, nil
+		})
+		return nil
+	}
+
+	m := fiberserver.NewModule(
+		fiberserver.WithHost("127.0.0.1"),
+		fiberserver.WithPort(0),
+		fiberserver.WithRouter(func(app *fiber.App) {
+			app.Get("/scoped", func(c fiber.Ctx) error {
+				first, err := lakta.Invoke[*requestCounter](c.Context())
+				if err != nil {
+					return c.Status(http.StatusInternalServerError).SendString(err.Error())
+				}
+				second, err := lakta.Invoke[*requestCounter](c.Context())
+				if err != nil || first != second {
+					return c.SendStatus(http.StatusConflict)
+				}
+				return c.SendString(strconv.Itoa(int(first.id)))
+			})
+		}),
+	)
+
+	testkit.NewRuntimeHarness(t, provider, m)
+	addr := testkit.WaitForAddr(t, m)
+
+	for _, want := range []string{"1", "2"} {
+		resp, err := http.Get("http://" + addr.String() + "/scoped") //nolint:noctx
+		testza.AssertNil(t, err)
+
+		body, err := io.ReadAll(resp.Body)
+		testza.AssertNil(t, err)
+		_ = resp.Body.Close()
+
+		testza.AssertEqual(t, http.StatusOK, resp.StatusCode)
+		testza.AssertEqual(t, want, string(body))
+	}
+
+	testza.AssertEqual(t, int32(2), disposed.Load())
+}
+
 func TestFiberModule_ConfigPath(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,36 @@
+package fiberserver
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/samber/oops"
+)
+
+// Probe paths, served below Config.HealthPath and below the actuator's /health.
+const (
+	LivenessPath  = "/liveness"
+	ReadinessPath = "/readiness"
+)
+
+// Probe statuses reported by ProbeResponse.
+const (
+	ProbeUp   = "UP"
+	ProbeDown = "DOWN"
+)
+
+// ProbeResponse is the liveness and readiness probe JSON contract.
+type ProbeResponse struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ProbeHandler serves the result of check as a probe: 200 UP when it passes,
+// 503 DOWN with the reason otherwise.
+func ProbeHandler(check func() error) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if err := check(); err != nil {
+			c.Status(fiber.StatusServiceUnavailable)
+			return oops.Wrapf(c.JSON(ProbeResponse{Status: ProbeDown, Reason: err.Error()}), "failed to write probe response")
+		}
+		return oops.Wrapf(c.JSON(ProbeResponse{Status: ProbeUp}), "failed to write probe response")
+	}
+}
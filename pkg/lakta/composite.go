@@ -0,0 +1,198 @@
+package lakta
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os/signal"
+	"sync"
+
+	"github.com/Vilsol/slox"
+	"github.com/samber/do/v2"
+	"github.com/samber/oops"
+)
+
+// ChildRuntime names a Runtime hosted by a Composite. Name is also the name of
+// the child's DI scope and must be unique within the Composite.
+type ChildRuntime struct {
+	Name    string
+	Runtime *Runtime
+}
+
+// Composite hosts several child Runtimes in one process, e.g. an api and a
+// worker for local development. The shared modules (config, logging, otel) run
+// in a parent runtime on the root injector; each child runs in its own
+// do.Scope below it, so children resolve the shared services but not each
+// other's. Children start once every shared module is ready and stop before
+// any shared module does. The first child to stop, cleanly or not, stops the
+// others, and a failing shared module stops them all.
+type Composite struct {
+	parent   *Runtime
+	children []ChildRuntime
+}
+
+// NewComposite creates a Composite running shared in the parent runtime and
+// hosting children. options configure the parent runtime; the signals, error
+// classifier and injector apply to the Composite as a whole, and each child's
+// own injector is set aside for its scope.
+func NewComposite(shared []Module, children []ChildRuntime, options ...RuntimeOption) *Composite {
+	return &Composite{
+		parent:   NewRuntimeWithOptions(shared, options...),
+		children: children,
+	}
+}
+
+// Run starts the Composite, handling the configured signals (SIGTERM/SIGINT by
+// default) for a coordinated graceful shutdown.
+func (c *Composite) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), c.parent.config.Signals...)
+	defer stop()
+
+	return c.RunContext(ctx)
+}
+
+// Validate checks the shared modules' graph, then each child's graph with the
+// shared modules' provides counted as satisfied.
+func (c *Composite) Validate() error {
+	if err := c.checkChildren(); err != nil {
+		return err
+	}
+
+	if err := c.parent.Validate(); err != nil {
+		return oops.Wrapf(err, "invalid shared modules")
+	}
+
+	inherited := c.inherited()
+	for _, child := range c.children {
+		if _, _, err := sortModules(child.Runtime.modules, inherited...); err != nil {
+			return oops.With("child", child.Name).Wrapf(err, "invalid child runtime %s", child.Name)
+		}
+	}
+
+	return nil
+}
+
+// RunContext boots the shared modules, then runs every child concurrently
+// until ctx is cancelled or a child stops, and shuts the children down before
+// the shared modules. Errors from children and the shared runtime are joined;
+// a configured ErrorClassifier maps the result.
+func (c *Composite) RunContext(ctx context.Context) error {
+	err := c.run(ctx)
+	if err != nil && c.parent.config.ErrorClassifier != nil {
+		return c.parent.config.ErrorClassifier(err)
+	}
+
+	return err
+}
+
+// run is RunContext without error classification.
+func (c *Composite) run(ctx context.Context) error {
+	if err := c.checkChildren(); err != nil {
+		return exitError(ExitConfig, nil, err)
+	}
+
+	injector, ok := c.parent.config.Injector, c.parent.config.Injector != nil
+	if !ok {
+		injector, ok = tryInjector(ctx)
+	}
+	if !ok {
+		injector = do.New()
+	}
+
+	inherited := c.inherited()
+	c.parent.hosted = nil
+	scopes := make([]do.Injector, len(c.children))
+	for i, child := range c.children {
+		// Services a previous run left in a child's scope would collide.
+		if _, taken := injector.ChildByName(child.Name); taken {
+			return exitError(ExitConfig, nil, oops.
+				With("child", child.Name).
+				Errorf("injector already has a scope %s, run the composite on a fresh injector", child.Name))
+		}
+
+		scopes[i] = injector.Scope(child.Name)
+		child.Runtime.inherited = inherited
+		c.parent.hosted = append(c.parent.hosted, child.Runtime.modules...)
+	}
+
+	parentCtx, stopParent := context.WithCancel(ctx)
+	defer stopParent()
+
+	var (
+		wg       sync.WaitGroup
+		errs     = make([]error, len(c.children))
+		readyErr error
+	)
+
+	childCtx, stopChildren := context.WithCancel(ctx)
+	defer stopChildren()
+
+	c.parent.started = func(ctx context.Context) {
+		wg.Go(func() {
+			// Sync shared modules are only launched by now; wait until they,
+			// ReadyNotifiers included, are ready.
+			if err := c.parent.info.Load().WaitReady(childCtx); err != nil {
+				if childCtx.Err() == nil {
+					readyErr = oops.Wrapf(err, "shared modules never became ready")
+					slox.Error(ctx, "shared modules never became ready", slog.Any("error", err))
+					stopParent()
+				}
+				return
+			}
+
+			for i, child := range c.children {
+				wg.Go(func() {
+					errs[i] = child.Runtime.runIn(childCtx, scopes[i])
+					if errs[i] != nil {
+						slox.Error(ctx, "child runtime failed",
+							slog.String("child", child.Name), slog.Any("error", errs[i]))
+						errs[i] = oops.With("child", child.Name).Wrapf(errs[i], "child runtime %s failed", child.Name)
+					}
+
+					// The first child to stop takes the others down with it.
+					stopParent()
+				})
+			}
+		})
+	}
+	c.parent.stopping = func(context.Context) {
+		stopChildren()
+		wg.Wait()
+	}
+
+	parentErr := c.parent.runIn(parentCtx, injector)
+
+	stopChildren()
+	wg.Wait()
+
+	if parentErr != nil {
+		parentErr = oops.Wrapf(parentErr, "shared runtime failed")
+	}
+
+	return errors.Join(append(errs, readyErr, parentErr)...)
+}
+
+// checkChildren rejects unnamed, nil and duplicate children.
+func (c *Composite) checkChildren() error {
+	seen := make(map[string]bool, len(c.children))
+	for _, child := range c.children {
+		if child.Name == "" || child.Runtime == nil {
+			return oops.With("child", child.Name).Errorf("child runtime needs a name and a runtime")
+		}
+		if seen[child.Name] {
+			return oops.With("child", child.Name).Errorf("duplicate child runtime %s", child.Name)
+		}
+		seen[child.Name] = true
+	}
+
+	return nil
+}
+
+// inherited lists every service the shared modules declare.
+func (c *Composite) inherited() []ServiceKey {
+	var keys []ServiceKey
+	for _, m := range c.parent.modules {
+		keys = append(keys, declarations(m).provides...)
+	}
+	return keys
+}
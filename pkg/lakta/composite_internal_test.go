@@ -0,0 +1,285 @@
+package lakta
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/samber/do/v2"
+)
+
+// stopRecorder records module shutdowns in order.
+type stopRecorder struct {
+	mu    sync.Mutex
+	order []string
+}
+
+func (s *stopRecorder) record(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.order = append(s.order, name)
+}
+
+func (s *stopRecorder) stopped() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.order...)
+}
+
+// childModule requires *diType and provides *depA into its own scope.
+type childModule struct {
+	NamedBase
+
+	recorder *stopRecorder
+	initErr  error
+	got      atomic.Pointer[diType]
+	inited   atomic.Bool
+}
+
+func (m *childModule) Init(ctx context.Context) error {
+	if m.initErr != nil {
+		return m.initErr
+	}
+
+	shared, err := Invoke[*diType](ctx)
+	if err != nil {
+		return err
+	}
+	m.got.Store(shared)
+
+	ProvideValue(ctx, &depA{})
+	m.inited.Store(true)
+	return nil
+}
+
+func (m *childModule) Shutdown(context.Context) error {
+	m.recorder.record(m.Name())
+	return nil
+}
+
+func (*childModule) Provides() []reflect.Type { return []reflect.Type{reflect.TypeFor[*depA]()} }
+func (*childModule) Dependencies() ([]reflect.Type, []reflect.Type) {
+	return []reflect.Type{reflect.TypeFor[*diType]()}, nil
+}
+
+// sharedModule is an infoProviderModule recording its shutdown.
+type sharedModule struct {
+	infoProviderModule
+
+	recorder *stopRecorder
+}
+
+func (m *sharedModule) Shutdown(context.Context) error {
+	m.recorder.record("shared")
+	return nil
+}
+
+// lateReadyModule is a ReadyNotifier that marks itself ready once ready is
+// closed.
+type lateReadyModule struct {
+	ready chan struct{}
+}
+
+func (*lateReadyModule) Init(context.Context) error     { return nil }
+func (*lateReadyModule) Shutdown(context.Context) error { return nil }
+func (*lateReadyModule) NotifiesReady() bool            { return true }
+
+func (m *lateReadyModule) Start(ctx context.Context) error {
+	select {
+	case <-m.ready:
+		MarkReady(ctx)
+	case <-ctx.Done():
+		return nil
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+func TestComposite_IsolatesChildScopes(t *testing.T) {
+	t.Parallel()
+
+	recorder := &stopRecorder{}
+	api := &childModule{NamedBase: NewNamedBase("api"), recorder: recorder}
+	worker := &childModule{NamedBase: NewNamedBase("worker"), recorder: recorder}
+
+	injector := do.New()
+	c := NewComposite(
+		[]Module{&sharedModule{recorder: recorder}},
+		[]ChildRuntime{
+			{Name: "api", Runtime: NewRuntime(api)},
+			{Name: "worker", Runtime: NewRuntime(worker)},
+		},
+		WithRuntimeInjector(injector),
+	)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- c.RunContext(ctx) }()
+
+	deadline := time.Now().Add(testWaitTimeout)
+	for !api.inited.Load() || !worker.inited.Load() {
+		if time.Now().After(deadline) {
+			t.Fatal("children never initialized")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	testza.AssertEqual(t, "provided", api.got.Load().val)
+	testza.AssertEqual(t, api.got.Load(), worker.got.Load())
+
+	_, err := do.Invoke[*depA](injector)
+	testza.AssertNotNil(t, err, "child services must stay in the child scope")
+
+	scope, ok := injector.ChildByName("api")
+	testza.AssertTrue(t, ok)
+	_, err = do.Invoke[*depA](scope)
+	testza.AssertNil(t, err)
+
+	cancel()
+	testza.AssertNil(t, <-done)
+
+	stopped := recorder.stopped()
+	testza.AssertLen(t, stopped, 3)
+	testza.AssertEqual(t, "shared", stopped[2], "shared modules stop after every child")
+}
+
+func TestComposite_ChildFailureStopsAll(t *testing.T) {
+	t.Parallel()
+
+	recorder := &stopRecorder{}
+	healthy := &childModule{NamedBase: NewNamedBase("api"), recorder: recorder}
+	broken := &childModule{NamedBase: NewNamedBase("worker"), recorder: recorder, initErr: errors.New("boom")}
+
+	c := NewComposite(
+		[]Module{&sharedModule{recorder: recorder}},
+		[]ChildRuntime{
+			{Name: "api", Runtime: NewRuntime(healthy)},
+			{Name: "worker", Runtime: NewRuntime(broken)},
+		},
+	)
+
+	err := c.RunContext(WithInjector(t.Context(), do.New()))
+	testza.AssertNotNil(t, err)
+	testza.AssertContains(t, err.Error(), "child runtime worker failed")
+
+	stopped := recorder.stopped()
+	testza.AssertEqual(t, "shared", stopped[len(stopped)-1])
+}
+
+func TestComposite_Validate(t *testing.T) {
+	t.Parallel()
+
+	child := func() []ChildRuntime {
+		return []ChildRuntime{{Name: "api", Runtime: NewRuntime(&childModule{})}}
+	}
+
+	testza.AssertNil(t, NewComposite([]Module{&infoProviderModule{}}, child()).Validate())
+	testza.AssertErrorIs(t, NewComposite(nil, child()).Validate(), ErrUnmetDependency)
+
+	duplicate := []ChildRuntime{
+		{Name: "api", Runtime: NewRuntime()},
+		{Name: "api", Runtime: NewRuntime()},
+	}
+	testza.AssertNotNil(t, NewComposite(nil, duplicate).Validate())
+	testza.AssertNotNil(t, NewComposite(nil, duplicate).RunContext(t.Context()))
+}
+
+func TestComposite_ChildrenWaitForSharedReadiness(t *testing.T) {
+	t.Parallel()
+
+	recorder := &stopRecorder{}
+	api := &childModule{NamedBase: NewNamedBase("api"), recorder: recorder}
+	late := &lateReadyModule{ready: make(chan struct{})}
+
+	c := NewComposite(
+		[]Module{&sharedModule{recorder: recorder}, late},
+		[]ChildRuntime{{Name: "api", Runtime: NewRuntime(api)}},
+	)
+
+	ctx, cancel := context.WithCancel(WithInjector(t.Context(), do.New()))
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- c.RunContext(ctx) }()
+
+	time.Sleep(50 * time.Millisecond)
+	testza.AssertFalse(t, api.inited.Load(), "children must wait for the shared modules to be ready")
+
+	close(late.ready)
+
+	deadline := time.Now().Add(testWaitTimeout)
+	for !api.inited.Load() {
+		if time.Now().After(deadline) {
+			t.Fatal("child never initialized")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	testza.AssertNil(t, <-done)
+}
+
+func TestComposite_RunDoesNotMutateRuntimes(t *testing.T) {
+	t.Parallel()
+
+	recorder := &stopRecorder{}
+	child := &childModule{NamedBase: NewNamedBase("api"), recorder: recorder}
+	api := NewRuntime(child)
+
+	runOnce := func(c *Composite) error {
+		child.inited.Store(false)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		done := make(chan error, 1)
+		go func() { done <- c.RunContext(ctx) }()
+
+		deadline := time.Now().Add(testWaitTimeout)
+		for !child.inited.Load() {
+			select {
+			case err := <-done:
+				return err
+			default:
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("child never initialized")
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		cancel()
+		return <-done
+	}
+
+	// Each run gets a fresh injector, and with it fresh scopes.
+	c := NewComposite([]Module{&sharedModule{recorder: recorder}}, []ChildRuntime{{Name: "api", Runtime: api}})
+	testza.AssertNil(t, runOnce(c))
+	testza.AssertNil(t, runOnce(c))
+	testza.AssertNil(t, c.parent.config.Injector)
+	testza.AssertNil(t, api.config.Injector)
+
+	// A caller's injector still holds the first run's child scope, so a second
+	// run is rejected rather than panicking on a duplicate scope.
+	injector := do.New()
+	c = NewComposite(
+		[]Module{&sharedModule{recorder: recorder}},
+		[]ChildRuntime{{Name: "api", Runtime: api}},
+		WithRuntimeInjector(injector),
+	)
+	testza.AssertNil(t, runOnce(c))
+	testza.AssertEqual(t, do.Injector(injector), c.parent.config.Injector)
+	testza.AssertNil(t, api.config.Injector)
+
+	err := runOnce(c)
+	testza.AssertNotNil(t, err)
+	testza.AssertContains(t, err.Error(), "already has a scope api")
+}
@@ -0,0 +1,142 @@
+package lakta
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/Vilsol/slox"
+	"github.com/knadh/koanf/v2"
+	"github.com/samber/do/v2"
+	"github.com/samber/oops"
+)
+
+// enabledKey is the config key, relative to a Configurable module's
+// ConfigPath, that skips the module when set to false.
+const enabledKey = "enabled"
+
+// ModuleCondition decides at boot whether a module runs. k is the
+// configuration loaded by the ConfigSource module, or the *koanf.Koanf already
+// in the injector; nil when neither exists.
+type ModuleCondition func(ctx context.Context, k *koanf.Koanf) bool
+
+// EnvCondition returns a ModuleCondition that holds when the environment
+// variable key is set to value.
+func EnvCondition(key, value string) ModuleCondition {
+	return func(_ context.Context, _ *koanf.Koanf) bool {
+		v, ok := os.LookupEnv(key)
+		return ok && v == value
+	}
+}
+
+// ConfigCondition returns a ModuleCondition that holds when the config key is
+// true, such as a profile or feature flag. A missing key or configuration
+// falls back to def.
+func ConfigCondition(key string, def bool) ModuleCondition {
+	return func(_ context.Context, k *koanf.Koanf) bool {
+		if k == nil || !k.Exists(key) {
+			return def
+		}
+		return k.Bool(key)
+	}
+}
+
+// enabledModules splits r.modules into those that run and those skipped by a
+// WithCondition predicate or an `<ConfigPath>.enabled: false` config key,
+// preserving declaration order. k is the configuration conditionConfig read,
+// nil without any.
+func (r *Runtime) enabledModules(ctx context.Context, k *koanf.Koanf) ([]Module, []Module) {
+	var enabled, skipped []Module
+	for _, m := range r.modules {
+		if r.moduleEnabled(ctx, m, k) {
+			enabled = append(enabled, m)
+			continue
+		}
+
+		slox.Info(ctx, "skipping disabled module", slog.String("name", moduleLabel(m)))
+		skipped = append(skipped, m)
+	}
+
+	return enabled, skipped
+}
+
+// conditionConfig returns the configuration conditions read, nil without any,
+// timing the ConfigSource load in r.configLoad.
+func (r *Runtime) conditionConfig(ctx context.Context, injector do.Injector) (*koanf.Koanf, error) {
+	for _, m := range r.modules {
+		src, ok := m.(ConfigSource)
+		if !ok {
+			continue
+		}
+
+		start := time.Now()
+		k, err := src.LoadConfigSource(ctx)
+		r.configLoad = TimelineEntry{Order: -1, Step: StepConfigSource, Start: start, Duration: time.Since(start)}
+		if err != nil {
+			return nil, oops.
+				With("name", fmt.Sprintf("%T", m)).
+				Wrapf(err, "failed loading configuration")
+		}
+		return k, nil
+	}
+
+	if k, err := do.Invoke[*koanf.Koanf](injector); err == nil {
+		return k, nil
+	}
+	return nil, nil
+}
+
+// shareConfigModules hands every Configurable module, hosted ones included, to
+// the SchemaValidator among the modules, if any.
+func (r *Runtime) shareConfigModules() {
+	var (
+		validators   []SchemaValidator
+		configurable []Configurable
+	)
+	for _, m := range r.modules {
+		if v, ok := m.(SchemaValidator); ok {
+			validators = append(validators, v)
+		}
+		if c, ok := m.(Configurable); ok {
+			configurable = append(configurable, c)
+		}
+	}
+	for _, m := range r.hosted {
+		if c, ok := m.(Configurable); ok {
+			configurable = append(configurable, c)
+		}
+	}
+
+	for _, v := range validators {
+		v.SetConfigModules(configurable)
+	}
+}
+
+// moduleEnabled applies m's WithCondition predicate, then its enabled key.
+func (r *Runtime) moduleEnabled(ctx context.Context, m Module, k *koanf.Koanf) bool {
+	if cond := r.condition(m); cond != nil && !cond(ctx, k) {
+		return false
+	}
+
+	c, ok := m.(Configurable)
+	if !ok || k == nil {
+		return true
+	}
+
+	key := c.ConfigPath() + "." + enabledKey
+	return !k.Exists(key) || k.Bool(key)
+}
+
+// condition returns m's WithCondition predicate, nil without one. Modules are
+// matched with sameModule, never hashed: a module value may not be comparable.
+func (r *Runtime) condition(m Module) ModuleCondition {
+	for _, c := range slices.Backward(r.config.Conditions) {
+		if sameModule(c.Module, m) {
+			return c.Condition
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package lakta
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/knadh/koanf/v2"
+	"github.com/samber/do/v2"
+)
+
+// skippableModule is a Configurable module providing *depA.
+type skippableModule struct {
+	declModule
+}
+
+func (*skippableModule) ConfigPath() string            { return "modules.test.skippable.default" }
+func (*skippableModule) LoadConfig(*koanf.Koanf) error { return nil }
+
+// sourceModule is a ConfigSource owning k.
+type sourceModule struct {
+	k     *koanf.Koanf
+	loads int
+}
+
+func (*sourceModule) Init(context.Context) error     { return nil }
+func (*sourceModule) Shutdown(context.Context) error { return nil }
+func (m *sourceModule) LoadConfigSource(context.Context) (*koanf.Koanf, error) {
+	m.loads++
+	return m.k, nil
+}
+
+// validatingSourceModule is a sourceModule that records the modules handed to
+// it as a SchemaValidator.
+type validatingSourceModule struct {
+	sourceModule
+
+	modules []Configurable
+}
+
+func (m *validatingSourceModule) SetConfigModules(modules []Configurable) { m.modules = modules }
+
+// splitModules reads the condition config of r from injector, as runIn does,
+// and splits its modules.
+func splitModules(t *testing.T, r *Runtime, injector do.Injector) ([]Module, []Module) {
+	t.Helper()
+	k, err := r.conditionConfig(t.Context(), injector)
+	testza.AssertNil(t, err)
+	return r.enabledModules(t.Context(), k)
+}
+
+func conditionKoanf(t *testing.T, values map[string]any) *koanf.Koanf {
+	t.Helper()
+	k := koanf.New(".")
+	for key, value := range values {
+		testza.AssertNil(t, k.Set(key, value))
+	}
+	return k
+}
+
+func TestEnvCondition(t *testing.T) {
+	t.Setenv("LAKTA_TEST_PROFILE", "prod")
+
+	testza.AssertTrue(t, EnvCondition("LAKTA_TEST_PROFILE", "prod")(t.Context(), nil))
+	testza.AssertFalse(t, EnvCondition("LAKTA_TEST_PROFILE", "dev")(t.Context(), nil))
+	testza.AssertFalse(t, EnvCondition("LAKTA_TEST_UNSET", "")(t.Context(), nil))
+}
+
+func TestConfigCondition(t *testing.T) {
+	t.Parallel()
+
+	k := conditionKoanf(t, map[string]any{"features.search": false})
+
+	testza.AssertFalse(t, ConfigCondition("features.search", true)(t.Context(), k))
+	testza.AssertTrue(t, ConfigCondition("features.missing", true)(t.Context(), k))
+	testza.AssertFalse(t, ConfigCondition("features.missing", false)(t.Context(), nil))
+}
+
+func TestEnabledModules_ConfigKeyFromSource(t *testing.T) {
+	t.Parallel()
+
+	source := &sourceModule{k: conditionKoanf(t, map[string]any{
+		"modules.test.skippable.default.enabled": false,
+	})}
+	skipped := &skippableModule{}
+	plain := &declModule{}
+
+	r := NewRuntime(plain, skipped, source)
+	enabled, disabled := splitModules(t, r, do.New())
+	testza.AssertEqual(t, []Module{plain, source}, enabled)
+	testza.AssertEqual(t, []Module{skipped}, disabled)
+	testza.AssertEqual(t, 1, source.loads)
+}
+
+func TestEnabledModules_ConfigKeyFromInjector(t *testing.T) {
+	t.Parallel()
+
+	injector := do.New()
+	do.ProvideValue(injector, conditionKoanf(t, map[string]any{
+		"modules.test.skippable.default.enabled": true,
+	}))
+	m := &skippableModule{}
+
+	enabled, disabled := splitModules(t, NewRuntime(m), injector)
+	testza.AssertEqual(t, []Module{m}, enabled)
+	testza.AssertLen(t, disabled, 0)
+}
+
+func TestEnabledModules_WithoutConfig(t *testing.T) {
+	t.Parallel()
+
+	m := &skippableModule{}
+	other := &declModule{}
+
+	r := NewRuntimeWithOptions([]Module{m, other}, WithCondition(other, func(_ context.Context, k *koanf.Koanf) bool {
+		return k != nil
+	}))
+	enabled, disabled := splitModules(t, r, do.New())
+	testza.AssertEqual(t, []Module{m}, enabled)
+	testza.AssertEqual(t, []Module{other}, disabled)
+}
+
+func TestEnabledModules_NonComparableModule(t *testing.T) {
+	t.Parallel()
+
+	value := valuePluginModule{tags: []string{"a"}}
+	other := &declModule{}
+	never := func(context.Context, *koanf.Koanf) bool { return false }
+
+	// A value holding a slice is never hashed, and matches no condition.
+	r := NewRuntimeWithOptions([]Module{value, other},
+		WithCondition(other, never),
+		WithCondition(valuePluginModule{tags: []string{"a"}}, never))
+	enabled, disabled := splitModules(t, r, do.New())
+	testza.AssertEqual(t, []Module{value}, enabled)
+	testza.AssertEqual(t, []Module{other}, disabled)
+}
+
+func TestShareConfigModules(t *testing.T) {
+	t.Parallel()
+
+	source := &validatingSourceModule{}
+	skipped := &skippableModule{}
+	hosted := &skippableModule{}
+
+	r := NewRuntime(&declModule{}, skipped, source)
+	r.hosted = []Module{&declModule{}, hosted}
+	r.shareConfigModules()
+
+	testza.AssertEqual(t, []Configurable{skipped, hosted}, source.modules)
+}
+
+func TestRunContext_SkipsDisabledProvider(t *testing.T) {
+	t.Parallel()
+
+	provider := &infoProviderModule{}
+	syncMod := &infoSyncModule{started: make(chan struct{})}
+	disabled := &declModule{provides: []reflect.Type{reflect.TypeFor[*depA]()}}
+	consumer := &declModule{optional: []reflect.Type{reflect.TypeFor[*depA]()}}
+
+	injector := do.New()
+	ctx, cancel := context.WithCancel(WithInjector(context.Background(), injector))
+	defer cancel()
+
+	r := NewRuntimeWithOptions(
+		[]Module{syncMod, provider, disabled, consumer},
+		WithCondition(disabled, func(context.Context, *koanf.Koanf) bool { return false }),
+	)
+	done := make(chan error, 1)
+	go func() { done <- r.RunContext(ctx) }()
+
+	select {
+	case <-syncMod.started:
+	case <-time.After(testWaitTimeout):
+		t.Fatal("sync module never started")
+	}
+
+	testza.AssertEqual(t, int32(0), disabled.initCalls.Load())
+	testza.AssertEqual(t, int32(1), consumer.initCalls.Load())
+
+	info, err := do.Invoke[*RuntimeInfo](injector)
+	testza.AssertNil(t, err)
+
+	snap := info.Snapshot()
+	testza.AssertLen(t, snap, 4)
+	testza.AssertEqual(t, StateSkipped, snap[3].State)
+	testza.AssertEqual(t, -1, snap[3].InitOrder)
+	testza.AssertEqual(t, -1, snap[3].InitWave)
+	testza.AssertEqual(t, []string{"*lakta.depA"}, snap[3].Provides)
+
+	cancel()
+	testza.AssertNil(t, <-done)
+	testza.AssertEqual(t, StateSkipped, info.Snapshot()[3].State)
+}
@@ -1,6 +1,10 @@
 package lakta
 
-import "github.com/knadh/koanf/v2"
+import (
+	"context"
+
+	"github.com/knadh/koanf/v2"
+)
 
 // Configurable is implemented by modules that can load configuration from koanf.
 type Configurable interface {
@@ -12,6 +16,30 @@ type Configurable interface {
 	LoadConfig(k *koanf.Koanf) error
 }
 
+// ConfigSource is implemented by the module that owns the configuration tree.
+// The runtime calls LoadConfigSource before sorting modules so module
+// conditions can read configuration. Init must then reuse the loaded tree
+// instead of loading it again.
+type ConfigSource interface {
+	LoadConfigSource(ctx context.Context) (*koanf.Koanf, error)
+}
+
+// ConfigSchema is implemented by Configurable modules that expose their config
+// struct, so a SchemaValidator can check the keys under ConfigPath against its
+// koanf tags.
+type ConfigSchema interface {
+	// ConfigStruct returns the module's config struct, or a pointer to it.
+	ConfigStruct() any
+}
+
+// SchemaValidator is implemented by a ConfigSource that validates the
+// configuration tree against the modules that read it. Before any Init, the
+// runtime hands it every Configurable module, enabled or not, including those
+// of a Composite's children.
+type SchemaValidator interface {
+	SetConfigModules(modules []Configurable)
+}
+
 // NamedModule is implemented by modules that support instance naming.
 type NamedModule interface {
 	// Name returns the instance name for this module.
@@ -31,6 +59,34 @@ type HotReloadable interface {
 	OnReload(k *koanf.Koanf)
 }
 
+// TransactionalReloadable is implemented by modules whose reaction to a config
+// hot-reload can fail. A reload prepares every such module before committing
+// any of them; when one fails to prepare, the others are aborted and the
+// previous config stays live. The runtime wires it in place of HotReloadable
+// when a module implements both.
+// PrepareReload runs under the config module's reload lock and must not call
+// back into the config module.
+type TransactionalReloadable interface {
+	PrepareReload(k *koanf.Koanf) (PreparedReload, error)
+}
+
+// PreparedReload holds the state a TransactionalReloadable module built for a
+// reload. Commit swaps it in and cannot fail; anything that can belongs in
+// PrepareReload. Abort discards it, leaving the module as it was. Either may
+// be nil.
+type PreparedReload struct {
+	Commit func()
+	Abort  func()
+}
+
+// TransactionalReloadNotifier is a ReloadNotifier that runs two-phase reloads.
+type TransactionalReloadNotifier interface {
+	ReloadNotifier
+	// OnPrepare registers the prepare phase of the module called name, which
+	// names it in reload results.
+	OnPrepare(name string, fn func(k *koanf.Koanf) (PreparedReload, error))
+}
+
 // ValidatableModule can veto a config hot-reload before it is committed.
 // A non-nil error aborts the reload; the previous config stays live.
 // ValidateReload runs under the config module's reload lock and must not call
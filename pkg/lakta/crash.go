@@ -0,0 +1,360 @@
+package lakta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime/pprof"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vilsol/slox"
+	"github.com/samber/oops"
+)
+
+// PanicPolicy selects what the runtime does when a module's Start or
+// StartAsync panics. A panic in Init or Shutdown always propagates.
+type PanicPolicy int
+
+const (
+	// PanicPropagate turns the panic into the module's error, as if Start had
+	// returned it: the runtime shuts down with ExitPanic (default).
+	PanicPropagate PanicPolicy = iota
+
+	// PanicRestart recovers and calls Start or StartAsync again after a
+	// backoff, like a supervised restart. The module's SupervisionPolicy, if
+	// it has one, sets the backoff and bounds the restarts; once they are used
+	// up the panic propagates.
+	PanicRestart
+
+	// PanicMarkFailed recovers and marks the module failed, so liveness fails,
+	// while the rest of the runtime keeps running.
+	PanicMarkFailed
+)
+
+// String returns the policy's config name.
+func (p PanicPolicy) String() string {
+	switch p {
+	case PanicPropagate:
+		return "propagate"
+	case PanicRestart:
+		return "restart"
+	case PanicMarkFailed:
+		return "mark_failed"
+	default:
+		return fmt.Sprintf("PanicPolicy(%d)", int(p))
+	}
+}
+
+// MarshalText renders the policy by its config name.
+func (p PanicPolicy) MarshalText() ([]byte, error) { return []byte(p.String()), nil }
+
+// UnmarshalText parses a config name: propagate, restart or mark_failed.
+func (p *PanicPolicy) UnmarshalText(text []byte) error {
+	for policy := PanicPropagate; policy <= PanicMarkFailed; policy++ {
+		if policy.String() == string(text) {
+			*p = policy
+			return nil
+		}
+	}
+
+	return oops.Errorf("unknown panic policy %q, want propagate, restart or mark_failed", text)
+}
+
+// ErrCrashReportNotFound is wrapped by CrashReports.Get for an unknown ID.
+var ErrCrashReportNotFound = errors.New("crash report not found")
+
+// CrashReport describes one panic of a module: what panicked and where, the
+// module states at the time, the sections CrashContributor modules added (the
+// config module adds its redacted provenance) and a dump of every goroutine.
+type CrashReport struct {
+	ID         string         `json:"id"`
+	Time       time.Time      `json:"time"`
+	Module     string         `json:"module"` // "Type (Name)" of the module that panicked
+	Step       TimelineStep   `json:"step"`   // init, start or shutdown
+	Policy     PanicPolicy    `json:"policy"` // the policy that handled the panic
+	Panic      string         `json:"panic"`
+	Stack      string         `json:"stack"`
+	Modules    []ModuleInfo   `json:"modules"`
+	Sections   map[string]any `json:"sections,omitempty"`
+	Goroutines string         `json:"goroutines"`
+}
+
+// CrashContributor adds a section to every crash report, such as the config
+// module's provenance. The data must be safe to persist, with secrets already
+// redacted, and encodable as JSON.
+type CrashContributor interface {
+	CrashReportSection() (name string, data any)
+}
+
+// maxCrashReports bounds how many reports CrashReports keeps in memory.
+const maxCrashReports = 32
+
+// crashIDPattern matches the IDs CrashReports hands out, so Get never reads
+// outside the crash report directory.
+var crashIDPattern = regexp.MustCompile(`^crash-[0-9TZ.]+-[0-9]+$`)
+
+// CrashReports holds the crash reports of a runtime; the runtime provides it
+// in DI. With a crash report directory every report is also written there as
+// <id>.json, and List and Get include the reports earlier runs left in it.
+// Safe for concurrent use.
+type CrashReports struct {
+	mu      sync.Mutex
+	dir     string
+	seq     int
+	reports []CrashReport // this process's reports, oldest first
+}
+
+// List returns every known report, newest first.
+func (c *CrashReports) List() ([]CrashReport, error) {
+	c.mu.Lock()
+	dir := c.dir
+	reports := slices.Clone(c.reports)
+	c.mu.Unlock()
+
+	if dir != "" {
+		stored, err := readCrashReports(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, report := range stored {
+			if !slices.ContainsFunc(reports, func(r CrashReport) bool { return r.ID == report.ID }) {
+				reports = append(reports, report)
+			}
+		}
+	}
+
+	slices.SortFunc(reports, func(a, b CrashReport) int { return b.Time.Compare(a.Time) })
+
+	return reports, nil
+}
+
+// Get returns the report with the given ID, wrapping ErrCrashReportNotFound
+// when there is none.
+func (c *CrashReports) Get(id string) (CrashReport, error) {
+	c.mu.Lock()
+	dir := c.dir
+	idx := slices.IndexFunc(c.reports, func(r CrashReport) bool { return r.ID == id })
+	if idx >= 0 {
+		report := c.reports[idx]
+		c.mu.Unlock()
+		return report, nil
+	}
+	c.mu.Unlock()
+
+	if dir == "" || !crashIDPattern.MatchString(id) {
+		return CrashReport{}, oops.With("id", id).Wrapf(ErrCrashReportNotFound, "no crash report %q", id)
+	}
+
+	report, err := readCrashReport(filepath.Join(dir, id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return CrashReport{}, oops.With("id", id).Wrapf(ErrCrashReportNotFound, "no crash report %q", id)
+	}
+
+	return report, err
+}
+
+// setDir changes the directory later reports are written to; "" keeps them in
+// memory only.
+func (c *CrashReports) setDir(dir string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dir = dir
+}
+
+// add assigns report an ID, keeps it and writes it to the crash report
+// directory, if any. Returns the report and the file it was written to.
+func (c *CrashReports) add(report CrashReport) (CrashReport, string, error) {
+	c.mu.Lock()
+	c.seq++
+	report.ID = fmt.Sprintf("crash-%s-%d", report.Time.UTC().Format("20060102T150405.000000000Z"), c.seq)
+	c.reports = append(c.reports, report)
+	if len(c.reports) > maxCrashReports {
+		c.reports = slices.Delete(c.reports, 0, len(c.reports)-maxCrashReports)
+	}
+	dir := c.dir
+	c.mu.Unlock()
+
+	if dir == "" {
+		return report, "", nil
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return report, "", oops.Wrapf(err, "failed to encode crash report")
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return report, "", oops.Wrapf(err, "failed to create crash report directory %s", dir)
+	}
+
+	path := filepath.Join(dir, report.ID+".json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return report, "", oops.Wrapf(err, "failed to write crash report %s", path)
+	}
+
+	return report, path, nil
+}
+
+// readCrashReports reads every report in dir; a missing dir holds none.
+func readCrashReports(dir string) ([]CrashReport, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to read crash report directory %s", dir)
+	}
+
+	var reports []CrashReport
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() || !crashIDPattern.MatchString(id) {
+			continue
+		}
+
+		report, err := readCrashReport(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+func readCrashReport(path string) (CrashReport, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is built from a validated ID
+	if err != nil {
+		return CrashReport{}, oops.Wrapf(err, "failed to read crash report %s", path)
+	}
+
+	var report CrashReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return CrashReport{}, oops.Wrapf(err, "failed to decode crash report %s", path)
+	}
+
+	return report, nil
+}
+
+// CrashReports returns the runtime's crash reports. It is also provided in DI
+// once RunContext starts.
+func (r *Runtime) CrashReports() *CrashReports {
+	return r.crashes
+}
+
+// reportCrash writes the crash report for err, a panic of the module at order
+// during step handled by policy, and logs it. Errors that are not panics are
+// ignored.
+func (r *Runtime) reportCrash(ctx context.Context, info *RuntimeInfo, order int, step TimelineStep, policy PanicPolicy, err error) {
+	if !errors.Is(err, ErrPanic) {
+		return
+	}
+
+	report := CrashReport{
+		Time:    time.Now(),
+		Step:    step,
+		Policy:  policy,
+		Panic:   err.Error(),
+		Modules: info.Snapshot(),
+	}
+
+	if order >= 0 && order < len(report.Modules) {
+		report.Module = report.Modules[order].displayName()
+	}
+
+	if o, ok := oops.AsOops(err); ok {
+		if stack, ok := o.Context()["stack"].(string); ok {
+			report.Stack = stack
+		}
+	}
+
+	for _, c := range r.contributors {
+		name, data := c.CrashReportSection()
+		if report.Sections == nil {
+			report.Sections = make(map[string]any)
+		}
+		report.Sections[name] = data
+	}
+
+	var goroutines bytes.Buffer
+	_ = pprof.Lookup("goroutine").WriteTo(&goroutines, 2) //nolint:mnd // debug=2 prints panic-style stacks
+	report.Goroutines = goroutines.String()
+
+	report, path, writeErr := r.crashes.add(report)
+
+	attrs := []any{
+		slog.String("module", report.Module),
+		slog.String("step", string(step)),
+		slog.String("policy", policy.String()),
+		slog.String("crash_report", report.ID),
+		slog.Any("error", err),
+	}
+	if path != "" {
+		attrs = append(attrs, slog.String("path", path))
+	}
+
+	slox.Error(ctx, "module panicked", attrs...)
+
+	if writeErr != nil {
+		slox.Error(ctx, "failed to write crash report", slog.Any("error", writeErr))
+	}
+}
+
+// guardStart calls start, the Start or StartAsync of the unsupervised module
+// at order, under the panic policy. Every panic is reported. Under
+// PanicRestart a panicking start is called again after a backoff until it
+// returns without panicking, its restarts run out or ctx ends. Under
+// PanicMarkFailed the module is marked failed and contained is true: the
+// caller must not let the panic stop the runtime.
+func (r *Runtime) guardStart(ctx context.Context, info *RuntimeInfo, order int, m Module, start func() error) (bool, error) {
+	policy := r.config.PanicPolicy
+	supervision := r.declaredSupervision(m)
+
+	for restarts := 0; ; {
+		err := safeCall(start)
+		if !errors.Is(err, ErrPanic) {
+			return false, err
+		}
+
+		switch {
+		case policy == PanicMarkFailed:
+			r.reportCrash(ctx, info, order, StepStart, policy, err)
+			info.setLastError(order, err)
+			info.setState(order, StateFailed)
+
+			return true, err
+		case policy != PanicRestart || ctx.Err() != nil,
+			supervision.MaxRestarts > 0 && restarts >= supervision.MaxRestarts:
+			r.reportCrash(ctx, info, order, StepStart, PanicPropagate, err)
+
+			return false, err
+		}
+
+		r.reportCrash(ctx, info, order, StepStart, policy, err)
+
+		restarts++
+		info.setLastError(order, err)
+		info.setRestarts(order, restarts)
+
+		delay := supervision.backoff(restarts)
+		slox.Warn(ctx, "restarting panicked module",
+			slog.String("name", moduleLabel(m)), slog.Int("restart", restarts), slog.Duration("backoff", delay))
+
+		select {
+		case <-ctx.Done():
+			return false, err
+		case <-time.After(delay):
+		}
+	}
+}
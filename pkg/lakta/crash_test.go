@@ -0,0 +1,269 @@
+package lakta_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/Vilsol/lakta/pkg/lakta"
+	"github.com/Vilsol/lakta/pkg/testkit"
+	"github.com/knadh/koanf/v2"
+	"github.com/samber/do/v2"
+)
+
+// crashSection is a CrashContributor module.
+type crashSection struct {
+	testkit.MockModule
+}
+
+func (*crashSection) CrashReportSection() (string, any) {
+	return "build", map[string]any{"commit": "abc123"}
+}
+
+// panicOnce returns a start function that panics on its first call and
+// blocks until ctx ends afterwards.
+func panicOnce() func(ctx context.Context) error {
+	calls := 0
+	return func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			panic("first start exploded")
+		}
+		<-ctx.Done()
+		return nil
+	}
+}
+
+func TestRunContext_PanicPropagateWritesCrashReport(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "crashes")
+
+	m := testkit.NewMockSyncModule()
+	m.OnStart = func(context.Context) error { panic("nil map") }
+
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{&crashSection{}, m}, lakta.WithCrashReportDir(dir))
+	err := rt.RunContext(context.Background())
+	testza.AssertEqual(t, lakta.ExitPanic, lakta.ExitReasonOf(err))
+
+	reports, listErr := rt.CrashReports().List()
+	testza.AssertNil(t, listErr)
+	testza.AssertLen(t, reports, 1)
+
+	report := reports[0]
+	testza.AssertEqual(t, lakta.StepStart, report.Step)
+	testza.AssertEqual(t, lakta.PanicPropagate, report.Policy)
+	testza.AssertContains(t, report.Module, "MockSyncModule")
+	testza.AssertContains(t, report.Panic, "nil map")
+	testza.AssertContains(t, report.Stack, "crash_test.go")
+	testza.AssertContains(t, report.Goroutines, "goroutine ")
+	testza.AssertLen(t, report.Modules, 2)
+	testza.AssertEqual(t, map[string]any{"commit": "abc123"}, report.Sections["build"])
+
+	_, statErr := os.Stat(filepath.Join(dir, report.ID+".json"))
+	testza.AssertNil(t, statErr)
+
+	// A later runtime using the same directory sees the earlier run's report.
+	next := lakta.NewRuntimeWithOptions([]lakta.Module{testkit.NewMockModule()}, lakta.WithCrashReportDir(dir))
+	stored, getErr := next.CrashReports().Get(report.ID)
+	testza.AssertNil(t, getErr)
+	testza.AssertEqual(t, report.Panic, stored.Panic)
+	testza.AssertEqual(t, report.Module, stored.Module)
+
+	_, getErr = next.CrashReports().Get("crash-20260101T000000.000000000Z-1")
+	testza.AssertErrorIs(t, getErr, lakta.ErrCrashReportNotFound)
+	_, getErr = next.CrashReports().Get("../" + report.ID)
+	testza.AssertErrorIs(t, getErr, lakta.ErrCrashReportNotFound)
+}
+
+func TestRunContext_PanicInInitIsReported(t *testing.T) {
+	t.Parallel()
+
+	m := testkit.NewMockModule()
+	m.OnInit = func(context.Context) error { panic("bad wiring") }
+
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{m}, lakta.WithPanicPolicy(lakta.PanicMarkFailed))
+	testza.AssertEqual(t, lakta.ExitPanic, lakta.ExitReasonOf(rt.RunContext(context.Background())))
+
+	reports, err := rt.CrashReports().List()
+	testza.AssertNil(t, err)
+	testza.AssertLen(t, reports, 1)
+	testza.AssertEqual(t, lakta.StepInit, reports[0].Step)
+	testza.AssertEqual(t, lakta.PanicPropagate, reports[0].Policy, "Init panics always propagate")
+}
+
+func TestRunContext_PanicMarkFailedKeepsRunning(t *testing.T) {
+	t.Parallel()
+
+	crasher := testkit.NewMockSyncModule()
+	crasher.OnStart = func(context.Context) error { panic("handler bug") }
+
+	server := testkit.NewMockSyncModule()
+	server.BlockStart = make(chan struct{})
+
+	injector := do.New()
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{crasher, server},
+		lakta.WithRuntimeInjector(injector), lakta.WithPanicPolicy(lakta.PanicMarkFailed))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- rt.RunContext(ctx) }()
+
+	waitFor(t, func() bool {
+		reports, _ := rt.CrashReports().List()
+		return len(reports) == 1
+	})
+
+	info, err := do.Invoke[*lakta.RuntimeInfo](injector)
+	testza.AssertNil(t, err)
+	waitFor(t, func() bool { return info.Liveness() != nil })
+	testza.AssertErrorIs(t, info.Liveness(), lakta.ErrModuleFailed)
+	testza.AssertContains(t, info.Snapshot()[0].LastError, "handler bug")
+
+	select {
+	case err := <-done:
+		t.Fatalf("runtime stopped after a contained panic: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	testza.AssertNil(t, <-done)
+}
+
+func TestRunContext_PanicRestart(t *testing.T) {
+	t.Parallel()
+
+	async := testkit.NewMockAsyncModule()
+	asyncCalls := 0
+	async.OnStartAsync = func(context.Context) error {
+		asyncCalls++
+		if asyncCalls == 1 {
+			panic("cold cache")
+		}
+		return nil
+	}
+
+	sync := testkit.NewMockSyncModule()
+	sync.OnStart = panicOnce()
+
+	fast := lakta.SupervisionPolicy{Backoff: time.Millisecond}
+	injector := do.New()
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{async, sync},
+		lakta.WithRuntimeInjector(injector),
+		lakta.WithPanicPolicy(lakta.PanicRestart),
+		lakta.WithSupervision(async, fast),
+		lakta.WithSupervision(sync, fast))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- rt.RunContext(ctx) }()
+
+	wait, waitCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer waitCancel()
+	testza.AssertNil(t, rt.WaitReady(wait))
+	waitFor(t, func() bool { return sync.StartCalls.Load() == 2 })
+
+	info, err := do.Invoke[*lakta.RuntimeInfo](injector)
+	testza.AssertNil(t, err)
+	for _, m := range info.Snapshot() {
+		testza.AssertEqual(t, 1, m.Restarts)
+		testza.AssertNotEqual(t, "", m.LastError)
+	}
+
+	reports, err := rt.CrashReports().List()
+	testza.AssertNil(t, err)
+	testza.AssertLen(t, reports, 2)
+	for _, report := range reports {
+		testza.AssertEqual(t, lakta.PanicRestart, report.Policy)
+	}
+
+	cancel()
+	testza.AssertNil(t, <-done)
+}
+
+func TestRunContext_PanicRestartExhausted(t *testing.T) {
+	t.Parallel()
+
+	m := testkit.NewMockSyncModule()
+	m.OnStart = func(context.Context) error { panic("always") }
+
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{m},
+		lakta.WithPanicPolicy(lakta.PanicRestart),
+		lakta.WithSupervision(m, lakta.SupervisionPolicy{MaxRestarts: 2, Backoff: time.Millisecond}))
+
+	testza.AssertEqual(t, lakta.ExitPanic, lakta.ExitReasonOf(rt.RunContext(context.Background())))
+	testza.AssertEqual(t, int32(3), m.StartCalls.Load())
+
+	reports, err := rt.CrashReports().List()
+	testza.AssertNil(t, err)
+	testza.AssertLen(t, reports, 3)
+	testza.AssertEqual(t, lakta.PanicPropagate, reports[0].Policy, "the last panic propagates")
+}
+
+func TestRunContext_PanicPolicyFromConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy string
+		err    bool
+	}{
+		{name: "mark failed", policy: "mark_failed"},
+		{name: "unknown", policy: "ignore", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			k := koanf.New(".")
+			_ = k.Set("runtime.panic_policy", tt.policy)
+			_ = k.Set("runtime.crash_report_dir", dir)
+
+			crasher := testkit.NewMockSyncModule()
+			crasher.OnStart = func(context.Context) error { panic("boom") }
+
+			rt := lakta.NewRuntime(koanfProviderModule(k), crasher)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() { done <- rt.RunContext(ctx) }()
+
+			if tt.err {
+				err := <-done
+				cancel()
+				testza.AssertEqual(t, lakta.ExitConfig, lakta.ExitReasonOf(err))
+				testza.AssertContains(t, err.Error(), "runtime.panic_policy")
+				return
+			}
+
+			waitFor(t, func() bool {
+				entries, _ := os.ReadDir(dir)
+				return len(entries) == 1
+			})
+
+			cancel()
+			testza.AssertNil(t, <-done)
+		})
+	}
+}
+
+func TestPanicPolicy_Text(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []lakta.PanicPolicy{lakta.PanicPropagate, lakta.PanicRestart, lakta.PanicMarkFailed} {
+		text, err := policy.MarshalText()
+		testza.AssertNil(t, err)
+
+		var parsed lakta.PanicPolicy
+		testza.AssertNil(t, parsed.UnmarshalText(text))
+		testza.AssertEqual(t, policy, parsed)
+	}
+
+	testza.AssertEqual(t, "mark_failed", lakta.PanicMarkFailed.String())
+	testza.AssertNotNil(t, new(lakta.PanicPolicy).UnmarshalText([]byte("explode")))
+}
@@ -0,0 +1,226 @@
+package lakta
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/samber/oops"
+)
+
+// ErrDuplicateProvider is the typed sentinel for a service key declared by more
+// than one module. Two instances of a module registering the same type must
+// qualify it with NamedProvider instead.
+var ErrDuplicateProvider = errors.New("duplicate module provider")
+
+// ErrDependencyCycle is the typed sentinel for modules whose declared
+// dependencies form a cycle. Cycle errors wrap it with the cycle path and every
+// module left unsorted.
+var ErrDependencyCycle = errors.New("cycle detected in module dependencies")
+
+// moduleLabel names m the way ModuleInfo.displayName does: its type, plus its
+// NamedModule name when set.
+func moduleLabel(m Module) string {
+	label := fmt.Sprintf("%T", m)
+	if nm, ok := m.(NamedModule); ok && nm.Name() != "" {
+		label += " (" + nm.Name() + ")"
+	}
+	return label
+}
+
+// duplicateProvider is a key declared by more than one module. owners holds
+// their indices in declaration order.
+type duplicateProvider struct {
+	key    ServiceKey
+	owners []int
+}
+
+func (d duplicateProvider) describe(modules []Module) string {
+	labels := make([]string, len(d.owners))
+	for i, owner := range d.owners {
+		labels[i] = moduleLabel(modules[owner])
+	}
+	return fmt.Sprintf("%v is provided by %s", d.key, strings.Join(labels, ", "))
+}
+
+// duplicateProviders lists every key provided by more than one module, in
+// order of first declaration. meta is parallel to modules.
+func duplicateProviders(meta []moduleMeta) []duplicateProvider {
+	var (
+		order  []ServiceKey
+		owners = make(map[ServiceKey][]int)
+	)
+
+	for i, m := range meta {
+		for _, k := range m.provides {
+			if _, seen := owners[k]; !seen {
+				order = append(order, k)
+			}
+			if !slices.Contains(owners[k], i) {
+				owners[k] = append(owners[k], i)
+			}
+		}
+	}
+
+	var dups []duplicateProvider
+	for _, k := range order {
+		if len(owners[k]) > 1 {
+			dups = append(dups, duplicateProvider{key: k, owners: owners[k]})
+		}
+	}
+	return dups
+}
+
+// duplicateProviderError builds the ErrDuplicateProvider error listing every
+// duplicated key.
+func duplicateProviderError(modules []Module, dups []duplicateProvider) error {
+	described := make([]string, len(dups))
+	for i, d := range dups {
+		described[i] = d.describe(modules)
+	}
+
+	return oops.
+		With("duplicates", described).
+		Wrapf(ErrDuplicateProvider, "%s; qualify each instance with NamedProvider", strings.Join(described, "; "))
+}
+
+// unmetDependencyError builds the ErrUnmetDependency error for module i
+// requiring k, suggesting providers of a pointer/non-pointer variant, an
+// assignable type, or the same type under another name.
+func unmetDependencyError(modules []Module, meta []moduleMeta, i int, k ServiceKey) error {
+	m := modules[i]
+	msg := fmt.Sprintf("module %T requires type %v but no module provides it", m, k)
+
+	suggestions := suggestProviders(modules, meta, i, k)
+	if len(suggestions) > 0 {
+		msg += "; did you mean " + strings.Join(suggestions, " or ") + "?"
+	}
+
+	builder := oops.
+		With("module", fmt.Sprintf("%T", m)).
+		With("type", k.String())
+	if len(suggestions) > 0 {
+		builder = builder.With("suggestions", suggestions)
+	}
+
+	return builder.Wrapf(ErrUnmetDependency, "%s", msg)
+}
+
+// suggestProviders lists provided keys that are close to the missing k: the
+// same type under another name, or, under k's name, its pointer or element
+// type, a type assignable to it, or an interface it implements. Module i's own
+// provides are skipped.
+func suggestProviders(modules []Module, meta []moduleMeta, i int, k ServiceKey) []string {
+	var out []string
+	seen := make(map[string]bool)
+
+	for owner, m := range meta {
+		if owner == i {
+			continue
+		}
+
+		for _, p := range m.provides {
+			if !nearKey(p, k) {
+				continue
+			}
+
+			s := fmt.Sprintf("%v (provided by %s)", p, moduleLabel(modules[owner]))
+			if !seen[s] {
+				seen[s] = true
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// nearKey reports whether provided key p is a plausible stand-in for the
+// wanted key k.
+func nearKey(p, k ServiceKey) bool {
+	if p.Type == k.Type {
+		return p.Name != k.Name
+	}
+	return p.Name == k.Name && nearType(p.Type, k.Type)
+}
+
+// nearType reports whether provided type p is a plausible stand-in for the
+// wanted type t.
+func nearType(p, t reflect.Type) bool {
+	switch {
+	case p == t:
+		return false
+	case p.Kind() == reflect.Pointer && p.Elem() == t:
+		return true
+	case t.Kind() == reflect.Pointer && t.Elem() == p:
+		return true
+	case p.AssignableTo(t):
+		return true
+	case p.Kind() == reflect.Interface && t.Implements(p):
+		return true
+	default:
+		return false
+	}
+}
+
+// cycleError builds the ErrDependencyCycle error once Kahn's algorithm stalls.
+// unsorted marks the modules it could not place; every one of them has a
+// declared provider edge from another unsorted module, so walking those edges
+// backwards must revisit a module, closing a cycle.
+func cycleError(modules []Module, meta []moduleMeta, unsorted []bool) error {
+	start := slices.Index(unsorted, true)
+
+	var (
+		path  []int        // modules walked, each a consumer of the next
+		via   []ServiceKey // via[k] links path[k+1] into path[k]
+		index = make(map[int]int)
+	)
+
+	for cur := start; ; {
+		if at, ok := index[cur]; ok {
+			path, via = path[at:], via[at:]
+			break
+		}
+
+		index[cur] = len(path)
+		path = append(path, cur)
+
+		for _, link := range meta[cur].links {
+			if unsorted[link.from] {
+				via = append(via, link.key)
+				cur = link.from
+				break
+			}
+		}
+	}
+
+	// path runs against the edges; reverse it so nodes[k] provides via[k] to
+	// nodes[k+1], then rotate it to start at the earliest declared module.
+	slices.Reverse(path)
+	slices.Reverse(via)
+	via = append(via[1:], via[0])
+
+	first := slices.Index(path, slices.Min(path))
+	path = append(path[first:], path[:first]...)
+	via = append(via[first:], via[:first]...)
+
+	var b strings.Builder
+	for k, idx := range path {
+		fmt.Fprintf(&b, "%s -(%v)-> ", moduleLabel(modules[idx]), via[k])
+	}
+	b.WriteString(moduleLabel(modules[path[0]]))
+	cycle := b.String()
+
+	var involved []string
+	for idx, u := range unsorted {
+		if u {
+			involved = append(involved, moduleLabel(modules[idx]))
+		}
+	}
+
+	return oops.
+		With("cycle", cycle).
+		With("modules", involved).
+		Wrapf(ErrDependencyCycle, "%s (unsorted: %s)", cycle, strings.Join(involved, ", "))
+}
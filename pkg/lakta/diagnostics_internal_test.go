@@ -0,0 +1,142 @@
+package lakta
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+// namedDeclModule is a declModule with a NamedModule name.
+type namedDeclModule struct {
+	*declModule
+	NamedBase
+}
+
+func newNamedDeclModule(name string, m *declModule) namedDeclModule {
+	return namedDeclModule{declModule: m, NamedBase: NewNamedBase(name)}
+}
+
+type diagError struct{}
+
+func (*diagError) Error() string { return "diag" }
+
+func TestSortModules_CyclePath(t *testing.T) {
+	t.Parallel()
+
+	a := newNamedDeclModule("a", &declModule{
+		provides: []reflect.Type{reflect.TypeFor[*depA]()},
+		required: []reflect.Type{reflect.TypeFor[*depB]()},
+	})
+	b := newNamedDeclModule("b", &declModule{
+		provides: []reflect.Type{reflect.TypeFor[*depB]()},
+		required: []reflect.Type{reflect.TypeFor[*depA]()},
+	})
+	downstream := newNamedDeclModule("c", &declModule{
+		required: []reflect.Type{reflect.TypeFor[*depA]()},
+	})
+
+	_, _, err := sortModules([]Module{downstream, a, b})
+
+	testza.AssertErrorIs(t, err, ErrDependencyCycle)
+	testza.AssertContains(t, err.Error(),
+		"lakta.namedDeclModule (a) -(*lakta.depA)-> lakta.namedDeclModule (b) -(*lakta.depB)-> lakta.namedDeclModule (a)")
+	testza.AssertContains(t, err.Error(),
+		"unsorted: lakta.namedDeclModule (c), lakta.namedDeclModule (a), lakta.namedDeclModule (b)")
+}
+
+func TestSortModules_UnmetSuggestsPointerVariant(t *testing.T) {
+	t.Parallel()
+
+	provider := &declModule{provides: []reflect.Type{reflect.TypeFor[depA]()}}
+	consumer := &declModule{required: []reflect.Type{reflect.TypeFor[*depA]()}}
+
+	_, _, err := sortModules([]Module{provider, consumer})
+
+	testza.AssertErrorIs(t, err, ErrUnmetDependency)
+	testza.AssertContains(t, err.Error(), "did you mean lakta.depA (provided by *lakta.declModule)?")
+}
+
+func TestSortModules_UnmetSuggestsAssignableType(t *testing.T) {
+	t.Parallel()
+
+	provider := &declModule{provides: []reflect.Type{reflect.TypeFor[*diagError]()}}
+	consumer := &declModule{required: []reflect.Type{reflect.TypeFor[error]()}}
+
+	_, _, err := sortModules([]Module{provider, consumer})
+
+	testza.AssertErrorIs(t, err, ErrUnmetDependency)
+	testza.AssertContains(t, err.Error(), "did you mean *lakta.diagError (provided by *lakta.declModule)?")
+}
+
+func TestSortModules_UnmetWithoutSuggestion(t *testing.T) {
+	t.Parallel()
+
+	provider := &declModule{provides: []reflect.Type{reflect.TypeFor[*depB]()}}
+	consumer := &declModule{required: []reflect.Type{reflect.TypeFor[*depA]()}}
+
+	_, _, err := sortModules([]Module{provider, consumer})
+
+	testza.AssertErrorIs(t, err, ErrUnmetDependency)
+	testza.AssertFalse(t, strings.Contains(err.Error(), "did you mean"))
+}
+
+func TestSortModules_UnmetSuggestsOtherName(t *testing.T) {
+	t.Parallel()
+
+	provider := &namedKeyModule{provides: []ServiceKey{NamedKey[*depA]("primary")}}
+	consumer := &namedKeyModule{required: []ServiceKey{NamedKey[*depA]("replica")}}
+
+	_, _, err := sortModules([]Module{provider, consumer})
+
+	testza.AssertErrorIs(t, err, ErrUnmetDependency)
+	testza.AssertContains(t, err.Error(), "requires type *lakta.depA@replica")
+	testza.AssertContains(t, err.Error(), "did you mean *lakta.depA@primary (provided by *lakta.namedKeyModule)?")
+}
+
+func TestDuplicateProviders(t *testing.T) {
+	t.Parallel()
+
+	first := newNamedDeclModule("first", &declModule{provides: []reflect.Type{reflect.TypeFor[*depA]()}})
+	unique := &declModule{provides: []reflect.Type{reflect.TypeFor[*depB]()}}
+	second := newNamedDeclModule("second", &declModule{provides: []reflect.Type{reflect.TypeFor[*depA]()}})
+	modules := []Module{first, unique, second}
+
+	meta := make([]moduleMeta, len(modules))
+	for i, m := range modules {
+		meta[i] = declarations(m)
+	}
+
+	dups := duplicateProviders(meta)
+	testza.AssertLen(t, dups, 1)
+	testza.AssertEqual(t, KeyOf[*depA](), dups[0].key)
+	testza.AssertEqual(t, []int{0, 2}, dups[0].owners)
+	testza.AssertEqual(t,
+		"*lakta.depA is provided by lakta.namedDeclModule (first), lakta.namedDeclModule (second)",
+		dups[0].describe(modules))
+}
+
+func TestSortModules_DuplicateProvider(t *testing.T) {
+	t.Parallel()
+
+	first := &declModule{provides: []reflect.Type{reflect.TypeFor[*depA]()}}
+	second := &declModule{provides: []reflect.Type{reflect.TypeFor[*depA]()}}
+
+	_, _, err := sortModules([]Module{first, second})
+
+	testza.AssertErrorIs(t, err, ErrDuplicateProvider)
+	testza.AssertContains(t, err.Error(), "*lakta.depA is provided by *lakta.declModule, *lakta.declModule")
+}
+
+func TestSortModules_DuplicateQualifiedProvider(t *testing.T) {
+	t.Parallel()
+
+	first := &namedKeyModule{provides: []ServiceKey{NamedKey[*depA]("primary")}}
+	second := &namedKeyModule{provides: []ServiceKey{NamedKey[*depA]("primary")}}
+
+	_, _, err := sortModules([]Module{first, second})
+
+	testza.AssertErrorIs(t, err, ErrDuplicateProvider)
+	testza.AssertContains(t, err.Error(), "*lakta.depA@primary is provided by")
+}
@@ -0,0 +1,641 @@
+package lakta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Vilsol/slox"
+	"github.com/samber/do/v2"
+	"github.com/samber/oops"
+)
+
+var (
+	// ErrNotRunning is wrapped by Attach and Detach outside the window between
+	// the end of Init and the start of shutdown.
+	ErrNotRunning = errors.New("runtime not running")
+
+	// ErrNotAttached is wrapped by Detach for a module Attach did not add.
+	ErrNotAttached = errors.New("module not attached")
+
+	// ErrHasDependents is wrapped by Detach while live modules still declare a
+	// dependency on the module.
+	ErrHasDependents = errors.New("module has live dependents")
+)
+
+// liveModules is the module set of a running runtime. Boot modules are fixed;
+// Attach appends to it and Detach clears entries, so indices stay stable and
+// match RuntimeInfo.
+type liveModules struct {
+	mu sync.Mutex
+
+	ctx      context.Context //nolint:containedctx
+	injector do.Injector
+	info     *RuntimeInfo
+	sup      *supervisor
+
+	// inherited lists services a Composite parent provides.
+	inherited []ServiceKey
+
+	// modules is indexed by InitOrder; nil entries were never initialized,
+	// skipped, or detached. meta is parallel and empty for nil entries.
+	modules  []Module
+	meta     []moduleMeta
+	attached map[int]*attachment
+	closed   bool
+
+	// attachMu serializes Attach; reserved is the slot the running Attach
+	// holds while its module initializes, or -1.
+	attachMu sync.Mutex
+	reserved int
+}
+
+// attachment tracks a module added by Attach.
+type attachment struct {
+	ctx      context.Context //nolint:containedctx // the Start/StartAsync context
+	cancel   context.CancelFunc
+	done     chan struct{} // closed once Start/StartAsync returned
+	released chan struct{} // closed once release forgot the module
+	services []string      // DI service names registered during Init
+	live     atomic.Bool   // cleared on Detach; gates reload callbacks
+
+	// detaching is set, under liveModules.mu, once a release is under way;
+	// the module is then neither detached again nor stopped by shutdown.
+	detaching bool
+}
+
+// newLiveModules pads initialized and meta with empty entries for the skipped
+// modules RuntimeInfo lists after the sorted ones.
+func newLiveModules(ctx context.Context, injector do.Injector, info *RuntimeInfo, sup *supervisor, initialized []Module, meta []moduleMeta, inherited []ServiceKey) *liveModules {
+	skipped := len(info.Snapshot()) - len(initialized)
+
+	return &liveModules{
+		ctx:       ctx,
+		injector:  injector,
+		info:      info,
+		sup:       sup,
+		inherited: inherited,
+		modules:   append(slices.Clone(initialized), make([]Module, skipped)...),
+		meta:      append(slices.Clone(meta), make([]moduleMeta, skipped)...),
+		attached:  make(map[int]*attachment),
+		reserved:  -1,
+	}
+}
+
+// scoped returns a context carrying the runtime's values (injector, logger)
+// that is cancelled with either the runtime or ctx.
+func (l *liveModules) scoped(ctx context.Context) (context.Context, context.CancelFunc) {
+	scopedCtx, cancel := context.WithCancel(l.ctx)
+	stop := context.AfterFunc(ctx, cancel)
+
+	return scopedCtx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// stopContext returns a context carrying the runtime's values that is cancelled
+// with ctx only: shutdown leaves a module being detached to its Detach, so the
+// stop outlives the runtime's own context.
+func (l *liveModules) stopContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	stopCtx, cancel := context.WithCancel(context.WithoutCancel(l.ctx))
+	stop := context.AfterFunc(ctx, cancel)
+
+	return stopCtx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// close stops Attach and Detach and returns the live modules and meta for
+// shutdown. Modules being detached are left to their Detach.
+func (l *liveModules) close() ([]Module, []moduleMeta) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closed = true
+
+	modules, meta := slices.Clone(l.modules), slices.Clone(l.meta)
+	for order, a := range l.attached {
+		if a.detaching {
+			modules[order] = nil
+			meta[order] = moduleMeta{}
+		}
+	}
+
+	return modules, meta
+}
+
+// wait cancels every attached module's Start/StartAsync and waits for them to
+// return, and for the modules being detached to be released, giving up when
+// ctx expires.
+func (l *liveModules) wait(ctx context.Context) {
+	l.mu.Lock()
+	pending := make([]chan struct{}, 0, len(l.attached))
+	for _, a := range l.attached {
+		a.cancel()
+		if a.detaching {
+			pending = append(pending, a.released)
+		} else {
+			pending = append(pending, a.done)
+		}
+	}
+	l.mu.Unlock()
+
+	for _, ch := range pending {
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			slox.Warn(ctx, "attached modules did not return before the shutdown deadline")
+			return
+		}
+	}
+}
+
+// Attach adds module to the running runtime: its declarations are checked
+// against the live modules, then it loads config, runs Init, is registered for
+// hot-reload and starts, all without touching the rest of the runtime. Its
+// required dependencies must be provided by a live module and its provides
+// must not collide with one. ctx bounds Init; the module's Start/StartAsync
+// context lives until Detach or shutdown. Attach returns once StartAsync has
+// returned, or once Start is running or waiting for the modules providing
+// the module's required dependencies to be ready. A module whose Init or
+// StartAsync fails is shut down again and recorded as detached, with the error
+// as LastError; one whose StartAsync panic PanicMarkFailed contained stays
+// attached and failed.
+// Wraps ErrNotRunning before Init completes and once shutdown begins.
+func (r *Runtime) Attach(ctx context.Context, module Module) error {
+	l := r.live.Load()
+	if l == nil {
+		return oops.Wrapf(ErrNotRunning, "cannot attach %s", moduleLabel(module))
+	}
+
+	// One Attach at a time, so the services each Init registers are its own.
+	// l.mu is only held to reserve and commit, never across Init or Start.
+	l.attachMu.Lock()
+	defer l.attachMu.Unlock()
+
+	order, meta, err := r.reserve(l, module)
+	if err != nil {
+		return err
+	}
+
+	initCtx, cancel := l.scoped(ctx)
+	defer cancel()
+
+	before := l.serviceNames()
+	if err := r.initModule(initCtx, module, order, l.injector, l.info); err != nil {
+		l.info.setLastError(order, err)
+		r.abandon(initCtx, l, order, before)
+
+		return err
+	}
+
+	a, err := r.commitAttach(initCtx, l, module, order, meta, before)
+	if err != nil {
+		return err
+	}
+
+	if err := r.startAttached(l, module, order, a); err != nil {
+		l.info.setLastError(order, err)
+
+		// Unless Detach or shutdown already took the module over.
+		l.mu.Lock()
+		claimed := !l.closed && !a.detaching
+		a.detaching = claimed
+		l.mu.Unlock()
+
+		if claimed {
+			_ = r.release(initCtx, l, module, order, a)
+		}
+
+		return err
+	}
+
+	slox.Info(initCtx, "attached module", slog.String("name", moduleLabel(module)))
+
+	return nil
+}
+
+// reserve checks module against the live modules and reserves its slot and
+// scope. The slot's meta is set, so Detach counts the reserved module as a
+// dependent of its providers while it initializes.
+func (r *Runtime) reserve(l *liveModules, module Module) (int, moduleMeta, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return 0, moduleMeta{}, oops.Wrapf(ErrNotRunning, "cannot attach %s", moduleLabel(module))
+	}
+
+	if l.index(module) >= 0 {
+		return 0, moduleMeta{}, oops.
+			With("name", fmt.Sprintf("%T", module)).
+			Errorf("module %s is already running", moduleLabel(module))
+	}
+
+	meta, err := l.link(module)
+	if err != nil {
+		return 0, moduleMeta{}, err
+	}
+
+	order := l.info.add(attachedInfo(module, meta, l.info))
+	l.modules = append(l.modules, nil)
+	l.meta = append(l.meta, meta)
+	l.reserved = order
+	r.scopes.create(order, module)
+
+	return order, meta, nil
+}
+
+// commitAttach records the initialized module as attached and registers it
+// for hot-reload. If shutdown began while it initialized, the module is shut
+// down again, outside l.mu, and ErrNotRunning returned.
+func (r *Runtime) commitAttach(ctx context.Context, l *liveModules, module Module, order int, meta moduleMeta, before map[string]bool) (*attachment, error) {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+
+		// ctx died with the runtime; the initialized module still gets its
+		// shutdown budget.
+		stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), effectiveShutdownTimeout(module, r.shutdownBudget()))
+		defer cancel()
+
+		_ = r.stopModule(stopCtx, module, order, l.info)
+		r.abandon(stopCtx, l, order, before)
+
+		return nil, oops.Wrapf(ErrNotRunning, "cannot attach %s", moduleLabel(module))
+	}
+	defer l.mu.Unlock()
+
+	startCtx, cancel := context.WithCancel(l.sup.ctx)
+	a := &attachment{
+		ctx:      startCtx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		released: make(chan struct{}),
+		services: l.newServices(before),
+	}
+	a.live.Store(true)
+
+	l.modules[order] = module
+	l.meta[order] = meta
+	l.attached[order] = a
+	l.reserved = -1
+	l.info.setShutdownTimeout(order, effectiveShutdownTimeout(module, r.shutdownBudget()))
+
+	l.registerReload(ctx, module, a)
+
+	return a, nil
+}
+
+// abandon frees the reserved slot at order: the services registered since
+// before are removed, the scope is released and the slot recorded as
+// StateDetached. l.mu is only taken to free the slot; the caller must not
+// hold it.
+func (r *Runtime) abandon(ctx context.Context, l *liveModules, order int, before map[string]bool) {
+	l.info.setState(order, StateDetached)
+	l.removeServices(ctx, before)
+	r.scopes.release(ctx, order)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.meta[order] = moduleMeta{}
+	l.reserved = -1
+}
+
+// Detach stops a module added by Attach and removes it from the runtime: its
+// Start/StartAsync context is cancelled, it is drained and shut down, and the
+// services it registered are removed from the injector and its own scope is
+// closed, shutting down any that implement a do Shutdowner. Its RuntimeInfo entry stays, as
+// StateDetached. ctx bounds the stop on top of the module's shutdown budget.
+// The stop holds up neither Attach nor shutdown; a shutdown beginning
+// meanwhile waits for it within its own budget instead of stopping the module
+// twice.
+// module is matched by pointer, or by value for a comparable type; a module
+// value holding a slice, map or func is never matched, so attach a pointer to
+// be able to detach it.
+// Wraps ErrNotAttached for boot modules and ErrHasDependents while any live
+// module declares a dependency on it.
+func (r *Runtime) Detach(ctx context.Context, module Module) error {
+	l := r.live.Load()
+	if l == nil {
+		return oops.Wrapf(ErrNotRunning, "cannot detach %s", moduleLabel(module))
+	}
+
+	order, a, err := l.claim(module)
+	if err != nil {
+		return err
+	}
+
+	stopCtx, cancel := l.stopContext(ctx)
+	defer cancel()
+
+	return r.release(stopCtx, l, module, order, a)
+}
+
+// claim checks module can be detached and marks it detaching, so the slow
+// release runs without l.mu.
+func (l *liveModules) claim(module Module) (int, *attachment, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return 0, nil, oops.Wrapf(ErrNotRunning, "cannot detach %s", moduleLabel(module))
+	}
+
+	order := l.index(module)
+	a, ok := l.attached[order]
+	if order < 0 || !ok || a.detaching {
+		return 0, nil, oops.Wrapf(ErrNotAttached, "cannot detach %s", moduleLabel(module))
+	}
+
+	if dependents := l.dependents(order); len(dependents) > 0 {
+		return 0, nil, oops.
+			With("dependents", dependents).
+			Wrapf(ErrHasDependents, "cannot detach %s: required by %s", moduleLabel(module), strings.Join(dependents, ", "))
+	}
+
+	a.detaching = true
+
+	return order, a, nil
+}
+
+// link resolves module's declarations against the live modules, returning its
+// meta with dependsOn and links pointing at the live providers.
+func (l *liveModules) link(module Module) (moduleMeta, error) {
+	meta := declarations(module)
+
+	owner := make(map[ServiceKey]int)
+	for order, m := range l.meta {
+		for _, k := range m.provides {
+			owner[k] = order
+		}
+	}
+
+	for _, k := range meta.provides {
+		if o, taken := owner[k]; taken {
+			return moduleMeta{}, oops.
+				With("duplicates", []string{k.String()}).
+				Wrapf(ErrDuplicateProvider, "%v is provided by %s and %s; qualify each instance with NamedProvider",
+					k, moduleLabel(l.modules[o]), moduleLabel(module))
+		}
+	}
+
+	modules := append(slices.Clone(l.modules), module)
+	metas := append(slices.Clone(l.meta), meta)
+
+	for _, k := range meta.required {
+		o, ok := owner[k]
+		if !ok && slices.Contains(l.inherited, k) {
+			continue
+		}
+		if !ok {
+			return moduleMeta{}, unmetDependencyError(modules, metas, len(modules)-1, k)
+		}
+
+		meta.links = append(meta.links, moduleLink{from: o, key: k})
+		meta.dependsOn = append(meta.dependsOn, o)
+	}
+
+	for _, k := range meta.optional {
+		if o, ok := owner[k]; ok {
+			meta.links = append(meta.links, moduleLink{from: o, key: k, optional: true})
+			meta.dependsOn = append(meta.dependsOn, o)
+		}
+	}
+
+	return meta, nil
+}
+
+// index returns the order of module among the live modules, or -1.
+func (l *liveModules) index(module Module) int {
+	return slices.IndexFunc(l.modules, func(m Module) bool { return sameModule(m, module) })
+}
+
+// sameModule reports whether a and b are the same module: the same pointer,
+// or equal values of a comparable type. Unlike a == b it never panics on a
+// module value holding a slice, map or func.
+func sameModule(a, b Module) bool {
+	if a == nil || b == nil {
+		return false
+	}
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Type() != vb.Type() {
+		return false
+	}
+
+	if va.Kind() == reflect.Pointer {
+		return va.Pointer() == vb.Pointer()
+	}
+
+	return va.Comparable() && vb.Comparable() && va.Equal(vb)
+}
+
+// dependents names the live modules, and the one being attached, declaring a
+// dependency on order.
+func (l *liveModules) dependents(order int) []string {
+	var out []string
+	for i, m := range l.meta {
+		if l.modules[i] == nil && i != l.reserved {
+			continue
+		}
+
+		for _, link := range m.links {
+			if link.from == order {
+				out = append(out, l.info.module(i).displayName())
+				break
+			}
+		}
+	}
+	return out
+}
+
+// attachedInfo describes a module joining at runtime. Its wave is one past its
+// latest provider's, so the report still reads as a dependency order.
+func attachedInfo(module Module, meta moduleMeta, info *RuntimeInfo) ModuleInfo {
+	wave := 0
+	for _, dep := range meta.dependsOn {
+		wave = max(wave, info.module(dep).InitWave+1)
+	}
+
+	m := describeModule(module, meta)
+	m.InitWave = wave
+
+	return m
+}
+
+// startAttached starts module under a context cancelled by Detach or the
+// supervisor's stop. StartAsync runs inline so its error reaches Attach;
+// Start and supervised modules run in the background and close a.done on
+// return.
+func (r *Runtime) startAttached(l *liveModules, module Module, order int, a *attachment) error {
+	ctx := r.scopes.context(a.ctx, order)
+
+	name := fmt.Sprintf("%T", module)
+
+	switch m := module.(type) {
+	case AsyncModule:
+		if policy, ok := r.supervisionPolicy(m); ok {
+			go func() {
+				defer close(a.done)
+				l.sup.run(ctx, order, m, policy)
+			}()
+
+			return nil
+		}
+
+		defer close(a.done)
+
+		l.info.setState(order, StateStarting)
+		r.emit(ctx, l.info, order, LifecycleEvent{Phase: PhaseBeforeStart, Module: m})
+
+		startedAt := time.Now()
+		contained, err := r.guardStart(ctx, l.info, order, m, func() error { return m.StartAsync(ctx) })
+		duration := r.recordStep(ctx, l.info, order, StepStart, startedAt, err)
+		if err == nil {
+			l.info.setState(order, StateStarted)
+		}
+		r.emit(ctx, l.info, order, LifecycleEvent{Phase: PhaseAfterStart, Module: m, Duration: duration, Err: err})
+
+		if err != nil && !contained {
+			slox.Error(ctx, "failed starting attached module", slog.String("name", name), slog.Any("error", err))
+			return oops.With("name", name).Wrapf(err, "failed starting module")
+		}
+	case SyncModule:
+		meta := l.meta[order]
+
+		go func() {
+			defer close(a.done)
+
+			if err := r.awaitProviders(ctx, l.info, order, meta); err != nil {
+				slox.Debug(ctx, "not starting attached sync module", slog.String("name", name), slog.Any("error", err))
+				return
+			}
+
+			ctx = r.beginSyncStart(ctx, l.info, order, m)
+
+			r.emit(ctx, l.info, order, LifecycleEvent{Phase: PhaseBeforeStart, Module: m})
+
+			if cs, ok := m.(contextSetter); ok {
+				cs.setCtx(ctx)
+			}
+
+			startedAt := time.Now()
+			_, err := r.guardStart(ctx, l.info, order, m, func() error { return m.Start(ctx) })
+			if err != nil && ctx.Err() == nil {
+				// An attached module fails alone; the runtime keeps running.
+				l.info.setState(order, StateFailed)
+				slox.Error(ctx, "attached sync module failed", slog.String("name", name), slog.Any("error", err))
+			}
+			r.emit(ctx, l.info, order, LifecycleEvent{Phase: PhaseAfterStart, Module: m, Duration: time.Since(startedAt), Err: err})
+		}()
+	default:
+		close(a.done)
+	}
+
+	return nil
+}
+
+// release stops an attached module and forgets it: cancel its start context,
+// drain, shut down, unregister its services, shut down its scope, and record
+// StateDetached. Returns the Shutdown error, if any; the module is detached
+// either way. a must be marked detaching; l.mu is only taken to forget the
+// module, so the stop holds up neither Attach nor shutdown.
+func (r *Runtime) release(ctx context.Context, l *liveModules, module Module, order int, a *attachment) error {
+	defer close(a.released)
+
+	a.live.Store(false)
+	a.cancel()
+
+	ctx, cancel := context.WithTimeout(ctx, r.shutdownBudget())
+	defer cancel()
+
+	select {
+	case <-a.done:
+	case <-ctx.Done():
+		slox.Warn(ctx, "attached module did not return before the shutdown deadline",
+			slog.String("name", moduleLabel(module)))
+	}
+
+	if d, ok := module.(Drainable); ok {
+		if err := safeCall(func() error { return d.Drain(ctx) }); err != nil {
+			slox.Warn(ctx, "failed draining module",
+				slog.String("name", fmt.Sprintf("%T", module)), slog.Any("error", err))
+		}
+	}
+
+	err := r.stopModule(ctx, module, order, l.info)
+	l.unregister(ctx, a.services)
+	r.scopes.release(ctx, order)
+
+	l.mu.Lock()
+	l.modules[order] = nil
+	l.meta[order] = moduleMeta{}
+	delete(l.attached, order)
+	l.mu.Unlock()
+
+	l.info.setState(order, StateDetached)
+
+	if err == nil {
+		slox.Info(ctx, "detached module", slog.String("name", moduleLabel(module)))
+	}
+
+	return err
+}
+
+// registerReload wires module to the ReloadNotifier. The callbacks cannot be
+// removed, so they stop forwarding once the module is detached.
+func (l *liveModules) registerReload(ctx context.Context, module Module, a *attachment) {
+	notifier, err := do.Invoke[ReloadNotifier](l.injector)
+	if err != nil {
+		return
+	}
+
+	wireReload(ctx, notifier, module, a.live.Load)
+}
+
+// serviceNames lists the services currently registered in the injector.
+func (l *liveModules) serviceNames() map[string]bool {
+	names := make(map[string]bool)
+	for _, s := range l.injector.ListProvidedServices() {
+		names[s.Service] = true
+	}
+	return names
+}
+
+// newServices lists the services registered since before was taken.
+func (l *liveModules) newServices(before map[string]bool) []string {
+	var added []string
+	for name := range l.serviceNames() {
+		if !before[name] {
+			added = append(added, name)
+		}
+	}
+	slices.Sort(added)
+	return added
+}
+
+// removeServices unregisters every service added since before was taken.
+func (l *liveModules) removeServices(ctx context.Context, before map[string]bool) {
+	l.unregister(ctx, l.newServices(before))
+}
+
+// unregister removes the named services from the injector, logging failures.
+func (l *liveModules) unregister(ctx context.Context, services []string) {
+	for _, name := range services {
+		if err := do.ShutdownNamedWithContext(ctx, l.injector, name); err != nil {
+			slox.Warn(ctx, "failed removing service of detached module",
+				slog.String("service", name), slog.Any("error", err))
+		}
+	}
+}
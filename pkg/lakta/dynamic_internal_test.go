@@ -0,0 +1,421 @@
+package lakta
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/knadh/koanf/v2"
+	"github.com/samber/do/v2"
+)
+
+// pluginModule provides *depA in DI during Init when provides is set, and
+// counts its Init, Shutdown and OnReload calls.
+type pluginModule struct {
+	provides bool
+	required []reflect.Type
+
+	initCalls     atomic.Int32
+	shutdownCalls atomic.Int32
+	reloads       atomic.Int32
+	started       chan struct{}
+	stopped       chan struct{}
+}
+
+func (m *pluginModule) Init(ctx context.Context) error {
+	m.initCalls.Add(1)
+	if m.provides {
+		ProvideValue(ctx, &depA{})
+	}
+	return nil
+}
+
+func (m *pluginModule) Shutdown(context.Context) error {
+	m.shutdownCalls.Add(1)
+	return nil
+}
+
+func (m *pluginModule) Provides() []reflect.Type {
+	if m.provides {
+		return []reflect.Type{reflect.TypeFor[*depA]()}
+	}
+	return nil
+}
+
+func (m *pluginModule) Dependencies() ([]reflect.Type, []reflect.Type) {
+	return m.required, nil
+}
+
+func (m *pluginModule) OnReload(*koanf.Koanf) { m.reloads.Add(1) }
+
+// syncPluginModule is a pluginModule blocking in Start.
+type syncPluginModule struct {
+	pluginModule
+}
+
+func (m *syncPluginModule) Start(ctx context.Context) error {
+	close(m.started)
+	<-ctx.Done()
+	close(m.stopped)
+	return nil
+}
+
+// failingPluginModule fails its StartAsync.
+type failingPluginModule struct {
+	pluginModule
+}
+
+func (*failingPluginModule) StartAsync(context.Context) error { return errors.New("boom") }
+
+// blockingPluginModule is a pluginModule whose Init blocks until release is
+// closed.
+type blockingPluginModule struct {
+	pluginModule
+
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (m *blockingPluginModule) Init(ctx context.Context) error {
+	close(m.entered)
+	<-m.release
+	return m.pluginModule.Init(ctx)
+}
+
+func liveClosed(l *liveModules) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// slowStoppingPluginModule is a pluginModule whose Shutdown blocks until
+// release is closed.
+type slowStoppingPluginModule struct {
+	pluginModule
+
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (m *slowStoppingPluginModule) Shutdown(ctx context.Context) error {
+	close(m.entered)
+	<-m.release
+	return m.pluginModule.Shutdown(ctx)
+}
+
+// valuePluginModule is a module value whose type is not comparable.
+type valuePluginModule struct {
+	tags []string
+}
+
+func (valuePluginModule) Init(context.Context) error     { return nil }
+func (valuePluginModule) Shutdown(context.Context) error { return nil }
+
+// recordingNotifier is a ReloadNotifier that keeps its callbacks.
+type recordingNotifier struct {
+	reload []func(*koanf.Koanf)
+}
+
+func (n *recordingNotifier) OnReload(fn func(*koanf.Koanf))    { n.reload = append(n.reload, fn) }
+func (*recordingNotifier) OnValidate(func(*koanf.Koanf) error) {}
+func (n *recordingNotifier) fire(k *koanf.Koanf) {
+	for _, fn := range n.reload {
+		fn(k)
+	}
+}
+
+// runningRuntime boots a runtime around a sync module and returns it with its
+// injector once Start has been entered. The runtime stops on test cleanup.
+func runningRuntime(t *testing.T, injector do.Injector) (*Runtime, <-chan error, context.CancelFunc) {
+	t.Helper()
+
+	provider := &infoProviderModule{}
+	syncMod := &infoSyncModule{started: make(chan struct{})}
+
+	ctx, cancel := context.WithCancel(WithInjector(context.Background(), injector))
+	t.Cleanup(cancel)
+
+	r := NewRuntime(syncMod, provider)
+	done := make(chan error, 1)
+	go func() { done <- r.RunContext(ctx) }()
+
+	select {
+	case <-syncMod.started:
+	case <-time.After(testWaitTimeout):
+		t.Fatal("sync module never started")
+	}
+
+	return r, done, cancel
+}
+
+func TestAttach_NotRunning(t *testing.T) {
+	t.Parallel()
+
+	r := NewRuntime(&declModule{})
+
+	testza.AssertErrorIs(t, r.Attach(t.Context(), &pluginModule{}), ErrNotRunning)
+	testza.AssertErrorIs(t, r.Detach(t.Context(), &pluginModule{}), ErrNotRunning)
+}
+
+func TestAttach_InitsAgainstLiveGraph(t *testing.T) {
+	t.Parallel()
+
+	injector := do.New()
+	r, done, cancel := runningRuntime(t, injector)
+
+	consumer := &pluginModule{required: []reflect.Type{reflect.TypeFor[*diType]()}}
+	testza.AssertNil(t, r.Attach(t.Context(), consumer))
+	testza.AssertEqual(t, int32(1), consumer.initCalls.Load())
+
+	info, err := do.Invoke[*RuntimeInfo](injector)
+	testza.AssertNil(t, err)
+
+	snap := info.Snapshot()
+	testza.AssertLen(t, snap, 3)
+	testza.AssertEqual(t, 2, snap[2].InitOrder)
+	testza.AssertEqual(t, 1, snap[2].InitWave)
+	testza.AssertEqual(t, StateInitialized, snap[2].State)
+	testza.AssertNil(t, info.Readiness())
+
+	unmet := &pluginModule{required: []reflect.Type{reflect.TypeFor[*depB]()}}
+	testza.AssertErrorIs(t, r.Attach(t.Context(), unmet), ErrUnmetDependency)
+	testza.AssertEqual(t, int32(0), unmet.initCalls.Load())
+
+	duplicate := &infoProviderModule{}
+	testza.AssertErrorIs(t, r.Attach(t.Context(), duplicate), ErrDuplicateProvider)
+	testza.AssertNotNil(t, r.Attach(t.Context(), consumer), "a running module cannot be attached twice")
+	testza.AssertLen(t, info.Snapshot(), 3)
+
+	cancel()
+	testza.AssertNil(t, <-done)
+	testza.AssertEqual(t, int32(1), consumer.shutdownCalls.Load())
+	testza.AssertErrorIs(t, r.Attach(t.Context(), &pluginModule{}), ErrNotRunning)
+}
+
+func TestDetach_RefusesLiveDependents(t *testing.T) {
+	t.Parallel()
+
+	injector := do.New()
+	r, done, cancel := runningRuntime(t, injector)
+
+	provider := &pluginModule{provides: true}
+	consumer := &pluginModule{required: []reflect.Type{reflect.TypeFor[*depA]()}}
+	testza.AssertNil(t, r.Attach(t.Context(), provider))
+	testza.AssertNil(t, r.Attach(t.Context(), consumer))
+
+	err := r.Detach(t.Context(), provider)
+	testza.AssertErrorIs(t, err, ErrHasDependents)
+	testza.AssertContains(t, err.Error(), "*lakta.pluginModule")
+
+	testza.AssertNil(t, r.Detach(t.Context(), consumer))
+	testza.AssertNil(t, r.Detach(t.Context(), provider))
+	testza.AssertEqual(t, int32(1), provider.shutdownCalls.Load())
+
+	_, err = do.Invoke[*depA](injector)
+	testza.AssertNotNil(t, err, "detaching must remove the module's services")
+
+	info, err := do.Invoke[*RuntimeInfo](injector)
+	testza.AssertNil(t, err)
+	snap := info.Snapshot()
+	testza.AssertEqual(t, StateDetached, snap[2].State)
+	testza.AssertEqual(t, StateDetached, snap[3].State)
+
+	testza.AssertErrorIs(t, r.Detach(t.Context(), provider), ErrNotAttached)
+
+	// Re-attaching re-registers the services without colliding.
+	testza.AssertNil(t, r.Attach(t.Context(), provider))
+	_, err = do.Invoke[*depA](injector)
+	testza.AssertNil(t, err)
+
+	cancel()
+	testza.AssertNil(t, <-done)
+	testza.AssertEqual(t, int32(2), provider.shutdownCalls.Load())
+	testza.AssertEqual(t, int32(1), consumer.shutdownCalls.Load())
+}
+
+func TestDetach_BootModule(t *testing.T) {
+	t.Parallel()
+
+	provider := &infoProviderModule{}
+	syncMod := &infoSyncModule{started: make(chan struct{})}
+
+	ctx, cancel := context.WithCancel(WithInjector(context.Background(), do.New()))
+	defer cancel()
+
+	r := NewRuntime(syncMod, provider)
+	done := make(chan error, 1)
+	go func() { done <- r.RunContext(ctx) }()
+	<-syncMod.started
+
+	testza.AssertErrorIs(t, r.Detach(t.Context(), provider), ErrNotAttached)
+
+	cancel()
+	testza.AssertNil(t, <-done)
+}
+
+func TestAttach_SyncModuleStopsOnDetach(t *testing.T) {
+	t.Parallel()
+
+	r, done, cancel := runningRuntime(t, do.New())
+
+	m := &syncPluginModule{pluginModule{started: make(chan struct{}), stopped: make(chan struct{})}}
+	testza.AssertNil(t, r.Attach(t.Context(), m))
+
+	select {
+	case <-m.started:
+	case <-time.After(testWaitTimeout):
+		t.Fatal("attached module never started")
+	}
+
+	testza.AssertNil(t, r.Detach(t.Context(), m))
+
+	select {
+	case <-m.stopped:
+	default:
+		t.Fatal("Detach must wait for Start to return")
+	}
+
+	cancel()
+	testza.AssertNil(t, <-done)
+	testza.AssertEqual(t, int32(1), m.shutdownCalls.Load())
+}
+
+func TestAttach_StartAsyncFailureRollsBack(t *testing.T) {
+	t.Parallel()
+
+	injector := do.New()
+	r, done, cancel := runningRuntime(t, injector)
+
+	m := &failingPluginModule{pluginModule{provides: true}}
+	testza.AssertNotNil(t, r.Attach(t.Context(), m))
+	testza.AssertEqual(t, int32(1), m.shutdownCalls.Load())
+
+	_, err := do.Invoke[*depA](injector)
+	testza.AssertNotNil(t, err)
+
+	info, err := do.Invoke[*RuntimeInfo](injector)
+	testza.AssertNil(t, err)
+	snap := info.Snapshot()
+	testza.AssertEqual(t, StateDetached, snap[2].State)
+	testza.AssertEqual(t, "failed starting module: boom", snap[2].LastError)
+	testza.AssertNil(t, info.Liveness())
+
+	cancel()
+	testza.AssertNil(t, <-done)
+}
+
+func TestAttach_RegistersForReload(t *testing.T) {
+	t.Parallel()
+
+	injector := do.New()
+	notifier := &recordingNotifier{}
+	do.ProvideValue[ReloadNotifier](injector, notifier)
+
+	r, done, cancel := runningRuntime(t, injector)
+
+	m := &pluginModule{}
+	testza.AssertNil(t, r.Attach(t.Context(), m))
+
+	notifier.fire(koanf.New("."))
+	testza.AssertEqual(t, int32(1), m.reloads.Load())
+
+	testza.AssertNil(t, r.Detach(t.Context(), m))
+	notifier.fire(koanf.New("."))
+	testza.AssertEqual(t, int32(1), m.reloads.Load())
+
+	cancel()
+	testza.AssertNil(t, <-done)
+}
+
+func TestAttach_InitDoesNotHoldTheLock(t *testing.T) {
+	t.Parallel()
+
+	r, done, cancel := runningRuntime(t, do.New())
+
+	other := &pluginModule{}
+	testza.AssertNil(t, r.Attach(t.Context(), other))
+
+	slow := &blockingPluginModule{entered: make(chan struct{}), release: make(chan struct{})}
+	attached := make(chan error, 1)
+	go func() { attached <- r.Attach(t.Context(), slow) }()
+	<-slow.entered
+
+	// Detach and shutdown go ahead while slow is still in Init.
+	testza.AssertNil(t, r.Detach(t.Context(), other))
+
+	cancel()
+	select {
+	case err := <-done:
+		testza.AssertNil(t, err)
+	case <-time.After(testWaitTimeout):
+		t.Fatal("shutdown waited for an attaching module's Init")
+	}
+
+	close(slow.release)
+	testza.AssertErrorIs(t, <-attached, ErrNotRunning)
+	testza.AssertEqual(t, int32(1), slow.shutdownCalls.Load())
+}
+
+func TestDetach_StopDoesNotHoldTheLock(t *testing.T) {
+	t.Parallel()
+
+	r, done, cancel := runningRuntime(t, do.New())
+	l := r.live.Load()
+
+	slow := &slowStoppingPluginModule{entered: make(chan struct{}), release: make(chan struct{})}
+	testza.AssertNil(t, r.Attach(t.Context(), slow))
+
+	detached := make(chan error, 1)
+	go func() { detached <- r.Detach(t.Context(), slow) }()
+	<-slow.entered
+
+	// Attach and shutdown go ahead while slow is still in Shutdown.
+	other := &pluginModule{}
+	testza.AssertNil(t, r.Attach(t.Context(), other))
+	testza.AssertErrorIs(t, r.Detach(t.Context(), slow), ErrNotAttached)
+
+	cancel()
+	deadline := time.Now().Add(testWaitTimeout)
+	for !liveClosed(l) {
+		if time.Now().After(deadline) {
+			t.Fatal("shutdown never closed the live modules")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Shutdown waits for the detach instead of stopping slow again.
+	select {
+	case <-done:
+		t.Fatal("shutdown did not wait for the module being detached")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(slow.release)
+	testza.AssertNil(t, <-detached)
+	testza.AssertNil(t, <-done)
+	testza.AssertEqual(t, int32(1), slow.shutdownCalls.Load())
+	testza.AssertEqual(t, int32(1), other.shutdownCalls.Load())
+}
+
+func TestAttach_NonComparableModule(t *testing.T) {
+	t.Parallel()
+
+	r, done, cancel := runningRuntime(t, do.New())
+
+	// A value of a non-comparable type has no identity: it attaches, but
+	// cannot be found again to detach.
+	m := valuePluginModule{tags: []string{"a"}}
+	testza.AssertNil(t, r.Attach(t.Context(), m))
+	testza.AssertErrorIs(t, r.Detach(t.Context(), m), ErrNotAttached)
+
+	ptr := &valuePluginModule{tags: []string{"b"}}
+	testza.AssertNil(t, r.Attach(t.Context(), ptr))
+	testza.AssertNil(t, r.Detach(t.Context(), ptr))
+
+	cancel()
+	testza.AssertNil(t, <-done)
+}
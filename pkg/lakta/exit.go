@@ -0,0 +1,202 @@
+package lakta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrPanic is wrapped by the error of a module whose Init, Start, StartAsync
+// or Shutdown panicked.
+var ErrPanic = errors.New("panic")
+
+// ExitReason classifies how RunContext returned. Each reason has its own
+// process exit code, so an orchestrator can tell a bad config (restarting will
+// not help) from a crash (it may).
+type ExitReason int
+
+const (
+	// ExitClean is a clean stop: a signal, ctx cancellation or a sync module
+	// returning nil.
+	ExitClean ExitReason = iota
+
+	// ExitFailure is an error no other reason covers, such as a failing
+	// Shutdown.
+	ExitFailure
+
+	// ExitConfig is invalid configuration or an invalid module graph: a config
+	// source or LoadConfig failing, a bad runtime setting, a cycle or an unmet
+	// dependency.
+	ExitConfig
+
+	// ExitInitFailed is a module's Init failing.
+	ExitInitFailed
+
+	// ExitStartFailed is a module's Start or StartAsync failing, or a
+	// supervised module exhausting its restarts.
+	ExitStartFailed
+
+	// ExitShutdownTimeout is a module not stopping before its shutdown
+	// deadline.
+	ExitShutdownTimeout
+
+	// ExitPanic is a module panicking in any lifecycle method.
+	ExitPanic
+)
+
+// String returns the reason's human-readable name.
+func (r ExitReason) String() string {
+	switch r {
+	case ExitClean:
+		return "clean stop"
+	case ExitFailure:
+		return "failure"
+	case ExitConfig:
+		return "config error"
+	case ExitInitFailed:
+		return "init failure"
+	case ExitStartFailed:
+		return "start failure"
+	case ExitShutdownTimeout:
+		return "shutdown deadline exceeded"
+	case ExitPanic:
+		return "panic"
+	default:
+		return fmt.Sprintf("ExitReason(%d)", int(r))
+	}
+}
+
+// Code returns the process exit code for the reason: 0 for a clean stop, 1 for
+// an unclassified failure, 2 for a panic (as an unrecovered Go panic exits),
+// 3 for an init failure, 4 for a start failure, 5 for a shutdown timeout and
+// 78 (EX_CONFIG from sysexits.h) for a config error.
+func (r ExitReason) Code() int {
+	switch r {
+	case ExitClean:
+		return 0
+	case ExitPanic:
+		return 2 //nolint:mnd // documented exit code
+	case ExitInitFailed:
+		return 3 //nolint:mnd // documented exit code
+	case ExitStartFailed:
+		return 4 //nolint:mnd // documented exit code
+	case ExitShutdownTimeout:
+		return 5 //nolint:mnd // documented exit code
+	case ExitConfig:
+		return 78 //nolint:mnd // EX_CONFIG
+	default:
+		return 1
+	}
+}
+
+// ExitError is the error RunContext returns for a classified failure. Its
+// message is the wrapped error's, so it is transparent to errors.Is, errors.As
+// and logging; use errors.As, ExitReasonOf or ExitCode to read the outcome.
+type ExitError struct {
+	Reason ExitReason
+
+	// Module labels the module that caused the exit, e.g.
+	// "*pgx.Module (default)"; empty when no single module did.
+	Module string
+
+	Err error
+}
+
+func (e *ExitError) Error() string { return e.Err.Error() }
+func (e *ExitError) Unwrap() error { return e.Err }
+
+// ExitReasonOf returns the outcome err describes: ExitClean for nil, the
+// reason of the first *ExitError in its chain, otherwise ExitFailure.
+func ExitReasonOf(err error) ExitReason {
+	if err == nil {
+		return ExitClean
+	}
+
+	var exit *ExitError
+	if errors.As(err, &exit) {
+		return exit.Reason
+	}
+
+	return ExitFailure
+}
+
+// ExitCode returns the process exit code for err, see ExitReason.Code.
+func ExitCode(err error) int {
+	return ExitReasonOf(err).Code()
+}
+
+// exitError classifies err as reason, caused by module (nil when no single
+// module did). An err that is already classified is returned as is, and a
+// panic is always ExitPanic.
+func exitError(reason ExitReason, module Module, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var exit *ExitError
+	if errors.As(err, &exit) {
+		return err
+	}
+
+	if errors.Is(err, ErrPanic) {
+		reason = ExitPanic
+	}
+
+	exit = &ExitError{Reason: reason, Err: err}
+	if module != nil {
+		exit.Module = moduleLabel(module)
+	}
+
+	return exit
+}
+
+// stopReason classifies a Shutdown error: a deadline is ExitShutdownTimeout,
+// anything else ExitFailure.
+func stopReason(err error) ExitReason {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ExitShutdownTimeout
+	}
+
+	return ExitFailure
+}
+
+// RunAndExit runs the runtime like Run and exits the process with the
+// outcome's exit code. A failure is first summarized on stderr: the outcome,
+// the module that caused it, the error and the wiring report of the run.
+func (r *Runtime) RunAndExit() {
+	err := r.Run()
+	if err != nil {
+		var modules []ModuleInfo
+		if info := r.info.Load(); info != nil {
+			modules = info.Snapshot()
+		}
+
+		_, _ = fmt.Fprint(os.Stderr, exitSummary(err, modules))
+	}
+
+	os.Exit(ExitCode(err)) //nolint:revive // RunAndExit exists to exit
+}
+
+// exitSummary renders the human summary RunAndExit prints for err.
+func exitSummary(err error, modules []ModuleInfo) string {
+	reason := ExitReasonOf(err)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "runtime stopped: %s (exit code %d)\n", reason, reason.Code())
+
+	var exit *ExitError
+	if errors.As(err, &exit) && exit.Module != "" {
+		fmt.Fprintf(&b, "module: %s\n", exit.Module)
+	}
+
+	fmt.Fprintf(&b, "error: %v\n", err)
+
+	if len(modules) > 0 {
+		b.WriteString("\n")
+		b.WriteString(RenderWiringReport(modules, nil))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,23 @@
+package lakta
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+func TestExitSummary(t *testing.T) {
+	t.Parallel()
+
+	err := exitError(ExitInitFailed, &infoProviderModule{NamedBase: NewNamedBase("primary")}, errors.New("failed initializing module: dial tcp: refused"))
+	modules := []ModuleInfo{{Name: "primary", Type: "*lakta.infoProviderModule", State: StateFailed}}
+
+	summary := exitSummary(err, modules)
+	testza.AssertContains(t, summary, "runtime stopped: init failure (exit code 3)\n")
+	testza.AssertContains(t, summary, "module: *lakta.infoProviderModule (primary)\n")
+	testza.AssertContains(t, summary, "error: failed initializing module: dial tcp: refused\n")
+	testza.AssertContains(t, summary, "ORDER")
+
+	testza.AssertEqual(t, "runtime stopped: failure (exit code 1)\nerror: boom\n", exitSummary(errors.New("boom"), nil))
+}
@@ -0,0 +1,155 @@
+package lakta_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/Vilsol/lakta/pkg/lakta"
+	"github.com/Vilsol/lakta/pkg/testkit"
+	"github.com/knadh/koanf/v2"
+	"github.com/samber/do/v2"
+)
+
+// brokenSource is a ConfigSource whose configuration fails to load.
+type brokenSource struct {
+	testkit.MockModule
+}
+
+func (*brokenSource) LoadConfigSource(context.Context) (*koanf.Koanf, error) {
+	return nil, errors.New("bad yaml")
+}
+
+func TestRunContext_ExitReasons(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		modules func() []lakta.Module
+		options []lakta.RuntimeOption
+		reason  lakta.ExitReason
+		module  bool
+	}{
+		{
+			name: "config source",
+			modules: func() []lakta.Module {
+				return []lakta.Module{&brokenSource{}}
+			},
+			reason: lakta.ExitConfig,
+		},
+		{
+			name: "unmet dependency",
+			modules: func() []lakta.Module {
+				m := testkit.NewMockProviderModule()
+				m.RequiredDeps = []reflect.Type{reflect.TypeFor[*depTypeA]()}
+				return []lakta.Module{m}
+			},
+			reason: lakta.ExitConfig,
+		},
+		{
+			name: "init failure",
+			modules: func() []lakta.Module {
+				m := testkit.NewMockModule()
+				m.InitErr = errors.New("db unreachable")
+				return []lakta.Module{m}
+			},
+			reason: lakta.ExitInitFailed,
+			module: true,
+		},
+		{
+			name: "start failure",
+			modules: func() []lakta.Module {
+				m := testkit.NewMockAsyncModule()
+				m.StartAsyncErr = errors.New("port taken")
+				return []lakta.Module{m}
+			},
+			reason: lakta.ExitStartFailed,
+			module: true,
+		},
+		{
+			name: "sync start failure",
+			modules: func() []lakta.Module {
+				m := testkit.NewMockSyncModule()
+				m.StartErr = errors.New("listener closed")
+				return []lakta.Module{m}
+			},
+			reason: lakta.ExitStartFailed,
+			module: true,
+		},
+		{
+			name: "panic",
+			modules: func() []lakta.Module {
+				m := testkit.NewMockModule()
+				m.OnInit = func(context.Context) error { panic("nil map") }
+				return []lakta.Module{m}
+			},
+			reason: lakta.ExitPanic,
+			module: true,
+		},
+		{
+			name: "shutdown deadline",
+			modules: func() []lakta.Module {
+				blocker := testkit.NewMockSyncModule()
+				stuck := testkit.NewMockModule()
+				stuck.OnShutdown = func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				return []lakta.Module{stuck, blocker}
+			},
+			options: []lakta.RuntimeOption{lakta.WithShutdownTimeout(20 * time.Millisecond)},
+			reason:  lakta.ExitShutdownTimeout,
+			module:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt := lakta.NewRuntimeWithOptions(tt.modules(), tt.options...)
+			err := rt.RunContext(lakta.WithInjector(context.Background(), do.New()))
+			testza.AssertNotNil(t, err)
+			testza.AssertEqual(t, tt.reason, lakta.ExitReasonOf(err))
+			testza.AssertEqual(t, tt.reason.Code(), lakta.ExitCode(err))
+
+			var exit *lakta.ExitError
+			testza.AssertTrue(t, errors.As(err, &exit))
+			testza.AssertEqual(t, tt.module, exit.Module != "", exit.Module)
+		})
+	}
+}
+
+func TestRunContext_CleanStopExitCode(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(lakta.WithInjector(context.Background(), do.New()))
+	cancel()
+
+	err := lakta.NewRuntime(testkit.NewMockModule()).RunContext(ctx)
+	testza.AssertNil(t, err)
+	testza.AssertEqual(t, lakta.ExitClean, lakta.ExitReasonOf(err))
+	testza.AssertEqual(t, 0, lakta.ExitCode(err))
+}
+
+func TestExitReason_DistinctCodes(t *testing.T) {
+	t.Parallel()
+
+	reasons := []lakta.ExitReason{
+		lakta.ExitClean, lakta.ExitFailure, lakta.ExitConfig, lakta.ExitInitFailed,
+		lakta.ExitStartFailed, lakta.ExitShutdownTimeout, lakta.ExitPanic,
+	}
+
+	seen := make(map[int]lakta.ExitReason)
+	for _, r := range reasons {
+		prev, dup := seen[r.Code()]
+		testza.AssertFalse(t, dup, "%s and %s share exit code %d", prev, r, r.Code())
+		seen[r.Code()] = r
+	}
+
+	testza.AssertEqual(t, 78, lakta.ExitConfig.Code())
+	testza.AssertEqual(t, 1, lakta.ExitCode(errors.New("unclassified")))
+}
@@ -0,0 +1,160 @@
+package lakta
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/samber/oops"
+)
+
+// GraphFormat names a ModuleGraph rendering accepted by ModuleGraph.Render.
+type GraphFormat string
+
+const (
+	GraphDOT     GraphFormat = "dot"
+	GraphMermaid GraphFormat = "mermaid"
+	GraphJSON    GraphFormat = "json"
+)
+
+// ModuleGraph is the declared module dependency graph Validate checks: one
+// node per module in init order and one edge per resolved Provides/Dependencies
+// link. It is derived from declarations alone, so undeclared Invoke calls and
+// the implicit ordering of modules without Dependent do not appear.
+type ModuleGraph struct {
+	Modules []GraphNode `json:"modules"`
+	Edges   []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a module in a ModuleGraph. ID is stable for a given module list
+// and is used by edges and by the DOT and Mermaid renderings.
+type GraphNode struct {
+	ID        string `json:"id"`
+	Name      string `json:"name,omitempty"`
+	Type      string `json:"type"`
+	InitOrder int    `json:"init_order"`
+	InitWave  int    `json:"init_wave"`
+	Lifecycle string `json:"lifecycle"`
+}
+
+// GraphEdge links the module providing Type to a module depending on it. Type
+// is the linking ServiceKey, so qualified links carry an "@name" suffix.
+// Optional edges come from the optional half of Dependencies.
+type GraphEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Type     string `json:"type"`
+	Optional bool   `json:"optional"`
+}
+
+// Graph builds the declared dependency graph without creating an injector or
+// calling any module method beyond Provides and Dependencies. Returns the same
+// error Validate would.
+func (r *Runtime) Graph() (*ModuleGraph, error) {
+	sorted, meta, err := sortModules(r.modules, r.inherited...)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := describeModules(sorted, meta)
+
+	graph := &ModuleGraph{
+		Modules: make([]GraphNode, len(infos)),
+		Edges:   []GraphEdge{},
+	}
+
+	for order, info := range infos {
+		graph.Modules[order] = GraphNode{
+			ID:        graphNodeID(order),
+			Name:      info.Name,
+			Type:      info.Type,
+			InitOrder: info.InitOrder,
+			InitWave:  info.InitWave,
+			Lifecycle: info.Lifecycle.String(),
+		}
+
+		for _, link := range meta[order].links {
+			graph.Edges = append(graph.Edges, GraphEdge{
+				From:     graphNodeID(link.from),
+				To:       graphNodeID(order),
+				Type:     link.key.String(),
+				Optional: link.optional,
+			})
+		}
+	}
+
+	return graph, nil
+}
+
+func graphNodeID(order int) string {
+	return fmt.Sprintf("m%d", order)
+}
+
+// Render renders the graph in format.
+func (g *ModuleGraph) Render(format GraphFormat) (string, error) {
+	switch format {
+	case GraphDOT:
+		return g.DOT(), nil
+	case GraphMermaid:
+		return g.Mermaid(), nil
+	case GraphJSON:
+		out, err := g.JSON()
+		return string(out), err
+	default:
+		return "", oops.With("format", string(format)).Errorf("unknown graph format %q", format)
+	}
+}
+
+// DOT renders the graph as a Graphviz digraph. Edges are labelled with the
+// linking type; optional edges are dashed.
+func (g *ModuleGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph modules {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for _, n := range g.Modules {
+		fmt.Fprintf(&b, "  %s [label=%q];\n", n.ID, n.label())
+	}
+	for _, e := range g.Edges {
+		if e.Optional {
+			fmt.Fprintf(&b, "  %s -> %s [label=%q, style=dashed];\n", e.From, e.To, e.Type)
+		} else {
+			fmt.Fprintf(&b, "  %s -> %s [label=%q];\n", e.From, e.To, e.Type)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart. Edges are labelled with
+// the linking type; optional edges are dotted.
+func (g *ModuleGraph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, n := range g.Modules {
+		fmt.Fprintf(&b, "  %s[%q]\n", n.ID, n.label())
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Optional {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "  %s %s|%q| %s\n", e.From, arrow, e.Type, e.To)
+	}
+	return b.String()
+}
+
+// JSON renders the graph as indented JSON.
+func (g *ModuleGraph) JSON() ([]byte, error) {
+	out, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return nil, oops.Wrapf(err, "failed to marshal module graph")
+	}
+	return append(out, '\n'), nil
+}
+
+func (n GraphNode) label() string {
+	if n.Name == "" {
+		return n.Type
+	}
+	return n.Type + " (" + n.Name + ")"
+}
@@ -0,0 +1,113 @@
+package lakta
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+func graphFixture() *Runtime {
+	provider := &declModule{
+		provides: []reflect.Type{reflect.TypeFor[*depA](), reflect.TypeFor[*depB]()},
+	}
+	consumer := &declModule{
+		required: []reflect.Type{reflect.TypeFor[*depA]()},
+		optional: []reflect.Type{reflect.TypeFor[*depB]()},
+	}
+
+	return NewRuntime(consumer, provider)
+}
+
+func TestGraph_Edges(t *testing.T) {
+	t.Parallel()
+
+	graph, err := graphFixture().Graph()
+	testza.AssertNil(t, err)
+
+	testza.AssertLen(t, graph.Modules, 2)
+	testza.AssertEqual(t, "m0", graph.Modules[0].ID)
+	testza.AssertEqual(t, "*lakta.declModule", graph.Modules[0].Type)
+	testza.AssertEqual(t, "init", graph.Modules[0].Lifecycle)
+	testza.AssertEqual(t, 1, graph.Modules[1].InitWave)
+
+	testza.AssertEqual(t, []GraphEdge{
+		{From: "m0", To: "m1", Type: "*lakta.depA"},
+		{From: "m0", To: "m1", Type: "*lakta.depB", Optional: true},
+	}, graph.Edges)
+}
+
+func TestGraph_DoesNotInit(t *testing.T) {
+	t.Parallel()
+
+	m := &declModule{provides: []reflect.Type{reflect.TypeFor[*depA]()}}
+
+	_, err := NewRuntime(m).Graph()
+	testza.AssertNil(t, err)
+	testza.AssertEqual(t, int32(0), m.initCalls.Load())
+}
+
+func TestGraph_ValidateError(t *testing.T) {
+	t.Parallel()
+
+	m := &declModule{required: []reflect.Type{reflect.TypeFor[*depA]()}}
+
+	graph, err := NewRuntime(m).Graph()
+	testza.AssertNil(t, graph)
+	testza.AssertErrorIs(t, err, ErrUnmetDependency)
+}
+
+func TestGraph_DOT(t *testing.T) {
+	t.Parallel()
+
+	graph, err := graphFixture().Graph()
+	testza.AssertNil(t, err)
+
+	testza.AssertEqual(t, `digraph modules {
+  rankdir=LR;
+  m0 [label="*lakta.declModule"];
+  m1 [label="*lakta.declModule"];
+  m0 -> m1 [label="*lakta.depA"];
+  m0 -> m1 [label="*lakta.depB", style=dashed];
+}
+`, graph.DOT())
+}
+
+func TestGraph_Mermaid(t *testing.T) {
+	t.Parallel()
+
+	graph, err := graphFixture().Graph()
+	testza.AssertNil(t, err)
+
+	testza.AssertEqual(t, `graph LR
+  m0["*lakta.declModule"]
+  m1["*lakta.declModule"]
+  m0 -->|"*lakta.depA"| m1
+  m0 -.->|"*lakta.depB"| m1
+`, graph.Mermaid())
+}
+
+func TestGraph_JSONRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	graph, err := graphFixture().Graph()
+	testza.AssertNil(t, err)
+
+	out, err := graph.Render(GraphJSON)
+	testza.AssertNil(t, err)
+
+	var decoded ModuleGraph
+	testza.AssertNil(t, json.Unmarshal([]byte(out), &decoded))
+	testza.AssertEqual(t, *graph, decoded)
+}
+
+func TestGraph_RenderUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	graph, err := graphFixture().Graph()
+	testza.AssertNil(t, err)
+
+	_, err = graph.Render("svg")
+	testza.AssertNotNil(t, err)
+}
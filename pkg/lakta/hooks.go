@@ -0,0 +1,106 @@
+package lakta
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Vilsol/slox"
+)
+
+// LifecyclePhase identifies the module transition a LifecycleEvent reports.
+type LifecyclePhase int
+
+const (
+	PhaseBeforeInit  LifecyclePhase = iota // about to LoadConfig/Init
+	PhaseAfterInit                         // Init returned (State is initialized or failed)
+	PhaseBeforeStart                       // Start/StartAsync goroutine entered (State is starting; started for a SyncModule that does not notify readiness and for a supervised AsyncModule)
+	PhaseAfterStart                        // Start/StartAsync returned
+	PhaseBeforeStop                        // about to call Shutdown
+	PhaseAfterStop                         // Shutdown returned, failed, or was skipped
+	PhaseReady                             // a ReadyNotifier called MarkReady (State has just moved from starting to started)
+)
+
+func (p LifecyclePhase) String() string {
+	switch p {
+	case PhaseBeforeInit:
+		return "before_init"
+	case PhaseAfterInit:
+		return "after_init"
+	case PhaseBeforeStart:
+		return "before_start"
+	case PhaseAfterStart:
+		return "after_start"
+	case PhaseBeforeStop:
+		return "before_stop"
+	case PhaseAfterStop:
+		return "after_stop"
+	case PhaseReady:
+		return "ready"
+	default:
+		return "unknown"
+	}
+}
+
+// LifecycleEvent is one module transition. Duration is how long the phase that
+// just ended took (Init, Start, or Shutdown; for PhaseReady, Start until
+// ready) and is zero for Before* phases; Err is that phase's error, if any.
+type LifecycleEvent struct {
+	Phase    LifecyclePhase
+	Module   Module
+	Info     ModuleInfo // RuntimeInfo entry at emission time
+	Duration time.Duration
+	Err      error
+}
+
+// LifecycleHook observes module transitions. Hooks run synchronously on the
+// lifecycle goroutine that caused the transition — possibly concurrently with
+// each other — so they must be fast and safe for concurrent use.
+type LifecycleHook func(ctx context.Context, event LifecycleEvent)
+
+// LifecycleHooks is the registry of lifecycle observers for one Runtime. The
+// runtime provides it in DI, so modules can subscribe from Init (observing
+// every later transition); hooks added via WithLifecycleHook or Runtime.Hooks
+// before RunContext also see the first BeforeInit.
+type LifecycleHooks struct {
+	mu    sync.RWMutex
+	hooks []LifecycleHook
+}
+
+// On registers hook for every subsequent lifecycle event.
+func (h *LifecycleHooks) On(hook LifecycleHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.hooks = append(h.hooks, hook)
+}
+
+// emit delivers ev to every registered hook. A panicking hook is logged and
+// does not affect the module or the remaining hooks. Nil receiver is a no-op.
+func (h *LifecycleHooks) emit(ctx context.Context, ev LifecycleEvent) {
+	if h == nil {
+		return
+	}
+
+	h.mu.RLock()
+	hooks := slices.Clone(h.hooks)
+	h.mu.RUnlock()
+
+	for _, hook := range hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					slox.Error(ctx, "lifecycle hook panicked",
+						slog.String("phase", ev.Phase.String()),
+						slog.Any("panic", r),
+						slog.String("stack", string(debug.Stack())))
+				}
+			}()
+
+			hook(ctx, ev)
+		}()
+	}
+}
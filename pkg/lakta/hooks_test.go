@@ -0,0 +1,182 @@
+package lakta_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/Vilsol/lakta/pkg/lakta"
+	"github.com/Vilsol/lakta/pkg/testkit"
+)
+
+// eventRecorder collects lifecycle events from concurrent lifecycle goroutines.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []lakta.LifecycleEvent
+}
+
+func (r *eventRecorder) hook(_ context.Context, ev lakta.LifecycleEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, ev)
+}
+
+func (r *eventRecorder) phases(m lakta.Module) []lakta.LifecyclePhase {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var out []lakta.LifecyclePhase
+	for _, ev := range r.events {
+		if ev.Module == m {
+			out = append(out, ev.Phase)
+		}
+	}
+	return out
+}
+
+func (r *eventRecorder) find(m lakta.Module, phase lakta.LifecyclePhase) (lakta.LifecycleEvent, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, ev := range r.events {
+		if ev.Module == m && ev.Phase == phase {
+			return ev, true
+		}
+	}
+	return lakta.LifecycleEvent{}, false
+}
+
+func TestLifecycleHooks_FullLifecycle(t *testing.T) {
+	t.Parallel()
+
+	rec := &eventRecorder{}
+	m := testkit.NewMockSyncModule()
+	m.BlockStart = make(chan struct{})
+
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{m}, lakta.WithLifecycleHook(rec.hook))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- rt.RunContext(ctx) }()
+
+	waitFor(t, func() bool { return m.StartCalls.Load() == 1 })
+	cancel()
+	testza.AssertNil(t, <-done)
+
+	// On a signal, Shutdown does not wait for Start to return, so AfterStart
+	// may interleave with the stop phases.
+	phases := rec.phases(m)
+	testza.AssertEqual(t, 6, len(phases))
+	testza.AssertEqual(t, []lakta.LifecyclePhase{
+		lakta.PhaseBeforeInit,
+		lakta.PhaseAfterInit,
+		lakta.PhaseBeforeStart,
+	}, phases[:3])
+	testza.AssertContains(t, phases, lakta.PhaseAfterStart)
+	testza.AssertTrue(t, slices.Index(phases, lakta.PhaseBeforeStop) < slices.Index(phases, lakta.PhaseAfterStop))
+
+	afterInit, _ := rec.find(m, lakta.PhaseAfterInit)
+	testza.AssertEqual(t, lakta.StateInitialized, afterInit.Info.State)
+	testza.AssertTrue(t, afterInit.Duration > 0)
+
+	afterStop, _ := rec.find(m, lakta.PhaseAfterStop)
+	testza.AssertEqual(t, lakta.StateStopped, afterStop.Info.State)
+	testza.AssertNil(t, afterStop.Err)
+}
+
+func TestLifecycleHooks_InitFailureCarriesError(t *testing.T) {
+	t.Parallel()
+
+	rec := &eventRecorder{}
+	initErr := errors.New("init boom")
+	m := testkit.NewMockModule()
+	m.InitErr = initErr
+
+	rt := lakta.NewRuntime(m)
+	rt.Hooks().On(rec.hook)
+
+	testza.AssertNotNil(t, rt.RunContext(context.Background()))
+
+	ev, ok := rec.find(m, lakta.PhaseAfterInit)
+	testza.AssertTrue(t, ok)
+	testza.AssertTrue(t, errors.Is(ev.Err, initErr))
+	testza.AssertEqual(t, lakta.StateFailed, ev.Info.State)
+
+	_, stopped := rec.find(m, lakta.PhaseBeforeStop)
+	testza.AssertFalse(t, stopped, "a module that failed Init is never shut down")
+}
+
+func TestLifecycleHooks_RegisteredFromDI(t *testing.T) {
+	t.Parallel()
+
+	rec := &eventRecorder{}
+
+	subscriber := testkit.NewMockModule()
+	subscriber.OnInit = func(ctx context.Context) error {
+		hooks, err := lakta.Invoke[*lakta.LifecycleHooks](ctx)
+		if err != nil {
+			return err
+		}
+		hooks.On(rec.hook)
+		return nil
+	}
+
+	later := testkit.NewMockModule()
+
+	rh := testkit.NewRuntimeHarness(t, subscriber, later)
+	testza.AssertNil(t, rh.Shutdown())
+
+	_, ok := rec.find(later, lakta.PhaseAfterInit)
+	testza.AssertTrue(t, ok, "a hook registered during Init sees later modules' events")
+	_, ok = rec.find(subscriber, lakta.PhaseAfterStop)
+	testza.AssertTrue(t, ok)
+}
+
+func TestLifecycleHooks_PanickingHookIsContained(t *testing.T) {
+	t.Parallel()
+
+	rec := &eventRecorder{}
+	m := testkit.NewMockModule()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{m},
+		lakta.WithLifecycleHook(func(context.Context, lakta.LifecycleEvent) { panic("hook boom") }),
+		lakta.WithLifecycleHook(rec.hook),
+	)
+
+	testza.AssertNil(t, rt.RunContext(ctx))
+	testza.AssertEqual(t, int32(1), m.InitCalls.Load())
+	_, ok := rec.find(m, lakta.PhaseAfterStop)
+	testza.AssertTrue(t, ok, "hooks after a panicking hook still run")
+}
+
+func TestLifecyclePhase_String(t *testing.T) {
+	t.Parallel()
+
+	testza.AssertEqual(t, "before_init", lakta.PhaseBeforeInit.String())
+	testza.AssertEqual(t, "after_init", lakta.PhaseAfterInit.String())
+	testza.AssertEqual(t, "before_start", lakta.PhaseBeforeStart.String())
+	testza.AssertEqual(t, "after_start", lakta.PhaseAfterStart.String())
+	testza.AssertEqual(t, "before_stop", lakta.PhaseBeforeStop.String())
+	testza.AssertEqual(t, "after_stop", lakta.PhaseAfterStop.String())
+	testza.AssertEqual(t, "ready", lakta.PhaseReady.String())
+}
+
+// waitFor polls cond until it holds or the test times out.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition never held")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -6,9 +6,14 @@ import (
 	"github.com/samber/do/v2"
 )
 
-type injectorKey struct{}
+type (
+	injectorKey       struct{}
+	sharedInjectorKey struct{}
+)
 
-// GetInjector returns the injector from the context.
+// GetInjector returns the injector from the context. Inside a runtime this is
+// the current module's own scope, which resolves the module's private services
+// before the shared ones.
 func GetInjector(ctx context.Context) do.Injector { //nolint:ireturn
 	injector, ok := ctx.Value(injectorKey{}).(do.Injector)
 	if !ok {
@@ -25,9 +30,37 @@ func tryInjector(ctx context.Context) (do.Injector, bool) { //nolint:ireturn
 	return inj, ok
 }
 
-// WithInjector returns a new context with the injector set.
+// WithInjector returns a new context with the injector set, as both the
+// injector and the shared one.
 func WithInjector(ctx context.Context, injector do.Injector) context.Context {
-	return context.WithValue(ctx, injectorKey{}, injector)
+	ctx = context.WithValue(ctx, injectorKey{}, injector)
+	return context.WithValue(ctx, sharedInjectorKey{}, injector)
+}
+
+// WithModuleInjectors returns ctx carrying a module's injectors: scope, its own
+// scope, as the injector and shared as the one Provide registers into. For
+// transports whose handlers run under their own context rather than the
+// module's, capture both from the module's context with GetInjector and
+// SharedInjector.
+func WithModuleInjectors(ctx context.Context, scope, shared do.Injector) context.Context {
+	ctx = context.WithValue(ctx, sharedInjectorKey{}, shared)
+	return context.WithValue(ctx, injectorKey{}, scope)
+}
+
+// withModuleScope returns ctx with scope as its injector, keeping the current
+// shared injector so Provide still publishes runtime-wide.
+func withModuleScope(ctx context.Context, scope do.Injector) context.Context {
+	return WithModuleInjectors(ctx, scope, SharedInjector(ctx))
+}
+
+// SharedInjector returns the runtime-wide injector Provide registers into.
+// Inside a module it is the parent of the module's own scope; elsewhere it is
+// the injector from the context. Panics like GetInjector without one.
+func SharedInjector(ctx context.Context) do.Injector { //nolint:ireturn
+	if injector, ok := ctx.Value(sharedInjectorKey{}).(do.Injector); ok {
+		return injector
+	}
+	return GetInjector(ctx)
 }
 
 // HasInjector reports whether ctx carries a DI injector. Modules whose Init may
@@ -38,19 +71,68 @@ func HasInjector(ctx context.Context) bool {
 	return ok
 }
 
-// Provide injects a provider into the injector.
+// Provide injects a provider into the shared injector, visible to every module.
 func Provide[T any](ctx context.Context, provider do.Provider[T]) {
-	do.Provide(GetInjector(ctx), provider)
+	do.Provide(SharedInjector(ctx), provider)
 }
 
-// ProvideValue injects a pre-created value into the injector.
+// ProvideValue injects a pre-created value into the shared injector.
 func ProvideValue[T any](ctx context.Context, value T) {
+	do.Provide(SharedInjector(ctx), func(_ do.Injector) (T, error) {
+		return value, nil
+	})
+}
+
+// ProvidePrivate injects a provider into the current module's own scope: the
+// module and its request handlers resolve it, other modules do not. It is
+// disposed of when the module is detached or the run ends.
+func ProvidePrivate[T any](ctx context.Context, provider do.Provider[T]) {
+	do.Provide(GetInjector(ctx), provider)
+}
+
+// ProvidePrivateValue injects a pre-created value into the current module's
+// own scope.
+func ProvidePrivateValue[T any](ctx context.Context, value T) {
 	do.Provide(GetInjector(ctx), func(_ do.Injector) (T, error) {
 		return value, nil
 	})
 }
 
-// Invoke retrieves a value of type T from the injector in the context.
+// Invoke retrieves a value of type T, from the request scope when ctx carries
+// one with a ProvideRequest provider for T, otherwise from the injector in the
+// context.
 func Invoke[T any](ctx context.Context) (T, error) { //nolint:ireturn
+	if injector, ok := requestInjector(ctx, do.NameOf[T]()); ok {
+		return do.Invoke[T](injector)
+	}
 	return do.Invoke[T](GetInjector(ctx))
 }
+
+// ServiceName returns the DI service name ProvideNamed and InvokeNamed use for
+// T qualified by name, for callers working with do.ProvideNamed and
+// do.InvokeNamed directly. An empty name is T's unqualified service.
+func ServiceName[T any](name string) string {
+	if name == "" {
+		return do.NameOf[T]()
+	}
+	return do.NameOf[T]() + "@" + name
+}
+
+// ProvideNamed injects a provider for T qualified by name into the shared
+// injector.
+func ProvideNamed[T any](ctx context.Context, name string, provider do.Provider[T]) {
+	do.ProvideNamed(SharedInjector(ctx), ServiceName[T](name), provider)
+}
+
+// ProvideNamedValue injects a pre-created value for T qualified by name.
+func ProvideNamedValue[T any](ctx context.Context, name string, value T) {
+	do.ProvideNamed(SharedInjector(ctx), ServiceName[T](name), func(_ do.Injector) (T, error) {
+		return value, nil
+	})
+}
+
+// InvokeNamed retrieves the value of type T qualified by name from the
+// injector in the context.
+func InvokeNamed[T any](ctx context.Context, name string) (T, error) { //nolint:ireturn
+	return do.InvokeNamed[T](GetInjector(ctx), ServiceName[T](name))
+}
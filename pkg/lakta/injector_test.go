@@ -48,3 +48,32 @@ func TestProvide_RegistersValue(t *testing.T) {
 	testza.AssertNil(t, err)
 	testza.AssertEqual(t, "hello", val)
 }
+
+func TestProvideNamedValue_InvokeNamed(t *testing.T) {
+	t.Parallel()
+
+	injector := do.New()
+	ctx := lakta.WithInjector(context.Background(), injector)
+
+	lakta.ProvideNamedValue(ctx, "primary", "db-1")
+	lakta.ProvideNamed(ctx, "replica", func(_ do.Injector) (string, error) {
+		return "db-2", nil
+	})
+
+	primary, err := lakta.InvokeNamed[string](ctx, "primary")
+	testza.AssertNil(t, err)
+	testza.AssertEqual(t, "db-1", primary)
+
+	replica, err := do.InvokeNamed[string](injector, lakta.ServiceName[string]("replica"))
+	testza.AssertNil(t, err)
+	testza.AssertEqual(t, "db-2", replica)
+
+	_, err = lakta.Invoke[string](ctx)
+	testza.AssertNotNil(t, err, "qualified services must not satisfy the unqualified one")
+}
+
+func TestServiceName_EmptyNameIsUnqualified(t *testing.T) {
+	t.Parallel()
+
+	testza.AssertEqual(t, do.NameOf[string](), lakta.ServiceName[string](""))
+}
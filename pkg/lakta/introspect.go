@@ -1,10 +1,14 @@
 package lakta
 
 import (
-	"reflect"
+	"context"
+	"errors"
 	"slices"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/samber/oops"
 )
 
 // LifecycleKind classifies how the runtime drives a module: it blocks in Start
@@ -31,6 +35,21 @@ func (k LifecycleKind) String() string {
 	}
 }
 
+// MarshalText renders the kind by name, as in a CrashReport.
+func (k LifecycleKind) MarshalText() ([]byte, error) { return []byte(k.String()), nil }
+
+// UnmarshalText parses a name rendered by MarshalText.
+func (k *LifecycleKind) UnmarshalText(text []byte) error {
+	for kind := LifecycleInit; kind <= LifecycleAsync; kind++ {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+
+	return oops.Errorf("unknown lifecycle kind %q", text)
+}
+
 // ModuleState is the furthest lifecycle stage a module has reached. It mutates
 // through boot and shutdown from multiple goroutines; read only via Snapshot.
 type ModuleState int
@@ -38,15 +57,20 @@ type ModuleState int
 const (
 	StatePending     ModuleState = iota // captured, not yet initialized
 	StateInitialized                    // Init returned nil
-	StateStarted                        // Start/StartAsync goroutine entered
+	StateStarting                       // StartAsync running, or a ReadyNotifier's Start entered but not yet ready
+	StateStarted                        // StartAsync returned nil, a supervised or SyncModule's Start entered, or a ReadyNotifier ready
 	StateStopped                        // Shutdown returned
-	StateFailed                         // Init returned an error
+	StateFailed                         // Init, Start, or StartAsync returned an error
+	StateSkipped                        // disabled by a condition; never initialized
+	StateDetached                       // removed from the running runtime by Detach
 )
 
 func (s ModuleState) String() string {
 	switch s {
 	case StateInitialized:
 		return "initialized"
+	case StateStarting:
+		return "starting"
 	case StateStarted:
 		return "started"
 	case StateStopped:
@@ -55,24 +79,63 @@ func (s ModuleState) String() string {
 		return "failed"
 	case StatePending:
 		return "pending"
+	case StateSkipped:
+		return "skipped"
+	case StateDetached:
+		return "detached"
 	default:
 		return "unknown"
 	}
 }
 
+// MarshalText renders the state by name, as in a CrashReport.
+func (s ModuleState) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+// UnmarshalText parses a name rendered by MarshalText.
+func (s *ModuleState) UnmarshalText(text []byte) error {
+	for state := StatePending; state <= StateDetached; state++ {
+		if state.String() == string(text) {
+			*s = state
+			return nil
+		}
+	}
+
+	return oops.Errorf("unknown module state %q", text)
+}
+
 // ModuleInfo is the captured metadata for one module, indexed by topo (init)
 // order. Provides/Requires/Optional are the reflect.Type slices already
 // computed by sortModules, rendered to strings for transport/rendering.
 type ModuleInfo struct {
-	Name         string // NamedModule.Name() if implemented, else ""
-	Type         string // fmt.Sprintf("%T", module)
-	InitOrder    int    // 0-based position in the topo-sorted slice
-	Provides     []string
-	Requires     []string
-	Optional     []string
-	Lifecycle    LifecycleKind
-	State        ModuleState
-	InitDuration time.Duration
+	Name      string        `json:"name"`       // NamedModule.Name() if implemented, else ""
+	Type      string        `json:"type"`       // fmt.Sprintf("%T", module)
+	InitOrder int           `json:"init_order"` // 0-based position in the topo-sorted slice, or after it once attached; -1 when skipped
+	InitWave  int           `json:"init_wave"`  // 0-based Init wave; modules sharing a wave Init concurrently; -1 when skipped
+	Provides  []string      `json:"provides"`
+	Requires  []string      `json:"requires"`
+	Optional  []string      `json:"optional"`
+	Lifecycle LifecycleKind `json:"lifecycle"`
+	State     ModuleState   `json:"state"`
+
+	// ConfigDuration, InitDuration, StartDuration and ShutdownDuration are
+	// how long LoadConfig, Init, starting and Shutdown took; zero until the
+	// step finished. Starting is StartAsync for an unsupervised AsyncModule
+	// and Start until MarkReady for a ReadyNotifier. Timeline has when.
+	ConfigDuration   time.Duration `json:"config_duration"`
+	InitDuration     time.Duration `json:"init_duration"`
+	StartDuration    time.Duration `json:"start_duration"`
+	ShutdownDuration time.Duration `json:"shutdown_duration"`
+
+	// ShutdownTimeout is the effective Shutdown budget: the module's own
+	// ShutdownTimeout when shorter than the runtime-wide budget, else the
+	// budget. Zero until the runtime resolves it after Init.
+	ShutdownTimeout time.Duration `json:"shutdown_timeout"`
+
+	// Restarts counts supervised and panic restarts so far. LastError is the
+	// latest error that did not stop the runtime: one a restart followed, a
+	// panic PanicMarkFailed contained, or a failed Attach; "" if none.
+	Restarts  int    `json:"restarts"`
+	LastError string `json:"last_error"`
 }
 
 // RuntimeInfo is the live registry of module metadata the runtime populates
@@ -80,8 +143,152 @@ type ModuleInfo struct {
 // lifecycle goroutines — consumers must call Snapshot at read time and never
 // cache the returned slice.
 type RuntimeInfo struct {
-	mu      sync.Mutex
-	modules []ModuleInfo // indexed by InitOrder
+	mu       sync.Mutex
+	modules  []ModuleInfo    // boot modules by InitOrder, then skipped, then attached modules
+	timeline []TimelineEntry // finished steps, in the order they finished
+	draining bool            // set once shutdown begins
+	changed  chan struct{}   // closed on the next state change; created by WaitReady
+}
+
+var (
+	// ErrNotReady is wrapped by Readiness while modules are still booting.
+	ErrNotReady = errors.New("runtime not ready")
+
+	// ErrDraining is wrapped by Readiness once shutdown has begun.
+	ErrDraining = errors.New("runtime draining")
+
+	// ErrModuleFailed is wrapped by Liveness when any module has failed.
+	ErrModuleFailed = errors.New("module failed")
+)
+
+// Readiness reports whether the runtime should receive traffic: nil once every
+// init-only module is initialized and every sync/async module has started.
+// While modules are still booting it wraps ErrNotReady naming them; from the
+// moment shutdown begins it wraps ErrDraining.
+func (ri *RuntimeInfo) Readiness() error {
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+
+	return ri.readiness()
+}
+
+// readiness is Readiness with mu held.
+func (ri *RuntimeInfo) readiness() error {
+	if ri.draining {
+		return oops.Wrapf(ErrDraining, "shutdown in progress")
+	}
+
+	var pending []string
+	for _, m := range ri.modules {
+		if m.State == StateSkipped || m.State == StateDetached {
+			continue
+		}
+
+		if !m.booted() {
+			pending = append(pending, m.displayName())
+		}
+	}
+
+	if len(pending) > 0 {
+		return oops.
+			With("modules", pending).
+			Wrapf(ErrNotReady, "waiting for %s", strings.Join(pending, ", "))
+	}
+
+	return nil
+}
+
+// Liveness reports whether the runtime is healthy: it wraps ErrModuleFailed
+// naming every module in StateFailed, else returns nil.
+func (ri *RuntimeInfo) Liveness() error {
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+
+	return ri.liveness()
+}
+
+// liveness is Liveness with mu held.
+func (ri *RuntimeInfo) liveness() error {
+	var failed []string
+	for _, m := range ri.modules {
+		if m.State == StateFailed {
+			failed = append(failed, m.displayName())
+		}
+	}
+
+	if len(failed) > 0 {
+		return oops.
+			With("modules", failed).
+			Wrapf(ErrModuleFailed, "failed: %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// WaitReady blocks until Readiness reports ready and returns nil. It gives up
+// early with the Readiness error once shutdown has begun and with the
+// Liveness error once a module has failed, since neither run becomes ready.
+// When ctx ends first, the error wraps both ctx's error and the modules still
+// pending.
+func (ri *RuntimeInfo) WaitReady(ctx context.Context) error {
+	for {
+		ri.mu.Lock()
+		ready, live := ri.readiness(), ri.liveness()
+		if ri.changed == nil {
+			ri.changed = make(chan struct{})
+		}
+		changed := ri.changed
+		ri.mu.Unlock()
+
+		switch {
+		case ready == nil:
+			return nil
+		case errors.Is(ready, ErrDraining):
+			return ready
+		case live != nil:
+			return live
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return oops.Errorf("%w: %w", ctx.Err(), ready)
+		}
+	}
+}
+
+// notify wakes every WaitReady caller; call with mu held after a change.
+func (ri *RuntimeInfo) notify() {
+	if ri.changed != nil {
+		close(ri.changed)
+		ri.changed = nil
+	}
+}
+
+// add appends the entry of a module attached at runtime, setting its
+// InitOrder to its index, and returns it.
+func (ri *RuntimeInfo) add(m ModuleInfo) int {
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+
+	m.InitOrder = len(ri.modules)
+	ri.modules = append(ri.modules, m)
+	ri.notify()
+
+	return m.InitOrder
+}
+
+// setDraining marks the runtime as shutting down. Nil receiver is a no-op.
+func (ri *RuntimeInfo) setDraining() {
+	if ri == nil {
+		return
+	}
+
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+
+	ri.draining = true
+	ri.notify()
 }
 
 // Snapshot returns an independent deep copy taken under lock: mutating the
@@ -90,17 +297,34 @@ func (ri *RuntimeInfo) Snapshot() []ModuleInfo {
 	ri.mu.Lock()
 	defer ri.mu.Unlock()
 
-	out := slices.Clone(ri.modules)
-	for i := range out {
-		out[i].Provides = slices.Clone(out[i].Provides)
-		out[i].Requires = slices.Clone(out[i].Requires)
-		out[i].Optional = slices.Clone(out[i].Optional)
+	out := make([]ModuleInfo, len(ri.modules))
+	for i, m := range ri.modules {
+		out[i] = m.clone()
 	}
 	return out
 }
 
-// setState records the furthest stage reached for the module at order. Nil
-// receiver and out-of-range order are no-ops (best-effort start/stop capture).
+// module returns a deep copy of the entry at order (zero value when out of
+// range or on a nil receiver).
+func (ri *RuntimeInfo) module(order int) ModuleInfo {
+	if ri == nil {
+		return ModuleInfo{}
+	}
+
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+
+	if order < 0 || order >= len(ri.modules) {
+		return ModuleInfo{}
+	}
+
+	return ri.modules[order].clone()
+}
+
+// setState records the furthest stage reached for the module at order. A
+// failed module stays StateFailed through shutdown so the failure remains
+// visible. Nil receiver and out-of-range order are no-ops (best-effort
+// start/stop capture).
 func (ri *RuntimeInfo) setState(order int, s ModuleState) {
 	if ri == nil {
 		return
@@ -112,11 +336,67 @@ func (ri *RuntimeInfo) setState(order int, s ModuleState) {
 	if order < 0 || order >= len(ri.modules) {
 		return
 	}
+	if s == StateStopped && ri.modules[order].State == StateFailed {
+		return
+	}
 	ri.modules[order].State = s
+	ri.notify()
+}
+
+// markReady moves the module at order from StateStarting to StateStarted,
+// reporting whether it did. Nil receiver and out-of-range order are no-ops.
+func (ri *RuntimeInfo) markReady(order int) bool {
+	if ri == nil {
+		return false
+	}
+
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+
+	if order < 0 || order >= len(ri.modules) || ri.modules[order].State != StateStarting {
+		return false
+	}
+	ri.modules[order].State = StateStarted
+	ri.notify()
+
+	return true
+}
+
+// setShutdownTimeout records the effective Shutdown budget for the module at order.
+func (ri *RuntimeInfo) setShutdownTimeout(order int, d time.Duration) {
+	if ri == nil {
+		return
+	}
+
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+
+	if order < 0 || order >= len(ri.modules) {
+		return
+	}
+	ri.modules[order].ShutdownTimeout = d
+}
+
+// setRestarts records how many times the module at order was restarted, by
+// its supervisor or the panic policy. Nil receiver and out-of-range order are
+// no-ops.
+func (ri *RuntimeInfo) setRestarts(order int, n int) {
+	if ri == nil {
+		return
+	}
+
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+
+	if order < 0 || order >= len(ri.modules) {
+		return
+	}
+	ri.modules[order].Restarts = n
 }
 
-// setInitDuration records how long the module at order took to Init.
-func (ri *RuntimeInfo) setInitDuration(order int, d time.Duration) {
+// setLastError records err as the latest error of the module at order, see
+// ModuleInfo.LastError. Nil receiver and out-of-range order are no-ops.
+func (ri *RuntimeInfo) setLastError(order int, err error) {
 	if ri == nil {
 		return
 	}
@@ -127,7 +407,32 @@ func (ri *RuntimeInfo) setInitDuration(order int, d time.Duration) {
 	if order < 0 || order >= len(ri.modules) {
 		return
 	}
-	ri.modules[order].InitDuration = d
+	ri.modules[order].LastError = err.Error()
+}
+
+// booted reports whether the module reached its ready state: initialized for
+// a LifecycleInit module, started otherwise.
+func (m ModuleInfo) booted() bool {
+	if m.Lifecycle == LifecycleInit {
+		return m.State == StateInitialized
+	}
+	return m.State == StateStarted
+}
+
+// displayName renders the module as "Type (Name)", or just Type when unnamed.
+func (m ModuleInfo) displayName() string {
+	if m.Name == "" {
+		return m.Type
+	}
+	return m.Type + " (" + m.Name + ")"
+}
+
+// clone returns a copy of m whose slices do not alias m's.
+func (m ModuleInfo) clone() ModuleInfo {
+	m.Provides = slices.Clone(m.Provides)
+	m.Requires = slices.Clone(m.Requires)
+	m.Optional = slices.Clone(m.Optional)
+	return m
 }
 
 // lifecycleOf classifies a module by the start interface it implements.
@@ -142,15 +447,15 @@ func lifecycleOf(m Module) LifecycleKind {
 	}
 }
 
-// renderTypes maps a []reflect.Type to reflect.Type.String() forms.
-func renderTypes(ts []reflect.Type) []string {
-	if len(ts) == 0 {
+// renderKeys maps a []ServiceKey to ServiceKey.String() forms.
+func renderKeys(keys []ServiceKey) []string {
+	if len(keys) == 0 {
 		return nil
 	}
 
-	out := make([]string, len(ts))
-	for i, t := range ts {
-		out[i] = t.String()
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		out[i] = k.String()
 	}
 	return out
 }
@@ -1,7 +1,6 @@
 package lakta
 
 import (
-	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -22,9 +21,11 @@ func TestModuleState_String(t *testing.T) {
 
 	testza.AssertEqual(t, "pending", StatePending.String())
 	testza.AssertEqual(t, "initialized", StateInitialized.String())
+	testza.AssertEqual(t, "starting", StateStarting.String())
 	testza.AssertEqual(t, "started", StateStarted.String())
 	testza.AssertEqual(t, "stopped", StateStopped.String())
 	testza.AssertEqual(t, "failed", StateFailed.String())
+	testza.AssertEqual(t, "skipped", StateSkipped.String())
 }
 
 func TestRuntimeInfo_SnapshotIndependent(t *testing.T) {
@@ -58,8 +59,8 @@ func TestRuntimeInfo_SetStateOutOfRangeIsNoop(t *testing.T) {
 	info := &RuntimeInfo{modules: make([]ModuleInfo, 1)}
 	info.setState(-1, StateStarted)
 	info.setState(1, StateStarted)
-	info.setInitDuration(-1, time.Second)
-	info.setInitDuration(1, time.Second)
+	info.record(TimelineEntry{Order: -1, Step: StepInit, Duration: time.Second})
+	info.record(TimelineEntry{Order: 1, Step: StepInit, Duration: time.Second})
 
 	testza.AssertEqual(t, StatePending, info.Snapshot()[0].State)
 }
@@ -69,7 +70,7 @@ func TestRuntimeInfo_NilReceiverIsNoop(t *testing.T) {
 
 	var info *RuntimeInfo
 	info.setState(0, StateStarted)
-	info.setInitDuration(0, time.Second)
+	info.record(TimelineEntry{Order: 0, Step: StepInit, Duration: time.Second})
 }
 
 func TestRuntimeInfo_ConcurrentMutation(t *testing.T) {
@@ -88,7 +89,7 @@ func TestRuntimeInfo_ConcurrentMutation(t *testing.T) {
 		wg.Go(func() {
 			for i := range iterations {
 				info.setState(i%moduleLen, StateStarted)
-				info.setInitDuration(i%moduleLen, time.Duration(i))
+				info.record(TimelineEntry{Order: i % moduleLen, Step: StepInit, Duration: time.Duration(i)})
 			}
 		})
 	}
@@ -115,12 +116,53 @@ func TestLifecycleOf(t *testing.T) {
 	testza.AssertEqual(t, LifecycleAsync, lifecycleOf(&infoAsyncModule{}))
 }
 
-func TestRenderTypes(t *testing.T) {
+func TestRenderKeys(t *testing.T) {
 	t.Parallel()
 
-	testza.AssertNil(t, renderTypes(nil))
+	testza.AssertNil(t, renderKeys(nil))
 	testza.AssertEqual(t,
-		[]string{"*lakta.depA", "lakta.depB"},
-		renderTypes([]reflect.Type{reflect.TypeFor[*depA](), reflect.TypeFor[depB]()}),
+		[]string{"*lakta.depA", "lakta.depB", "*lakta.depA@replica"},
+		renderKeys([]ServiceKey{KeyOf[*depA](), KeyOf[depB](), NamedKey[*depA]("replica")}),
 	)
 }
+
+func TestRuntimeInfo_Readiness(t *testing.T) {
+	t.Parallel()
+
+	info := &RuntimeInfo{modules: []ModuleInfo{
+		{Type: "*lakta.cfg", Lifecycle: LifecycleInit, State: StateInitialized},
+		{Type: "*lakta.server", Name: "api", Lifecycle: LifecycleSync, State: StateInitialized},
+		{Type: "*lakta.worker", Lifecycle: LifecycleAsync, State: StatePending},
+		{Type: "*lakta.disabled", InitOrder: -1, Lifecycle: LifecycleSync, State: StateSkipped},
+	}}
+
+	err := info.Readiness()
+	testza.AssertErrorIs(t, err, ErrNotReady)
+	testza.AssertContains(t, err.Error(), "*lakta.server (api)")
+	testza.AssertContains(t, err.Error(), "*lakta.worker")
+	testza.AssertNotContains(t, err.Error(), "*lakta.cfg")
+	testza.AssertNotContains(t, err.Error(), "*lakta.disabled")
+
+	info.setState(1, StateStarted)
+	info.setState(2, StateStarted)
+	testza.AssertNil(t, info.Readiness())
+
+	info.setDraining()
+	testza.AssertErrorIs(t, info.Readiness(), ErrDraining)
+}
+
+func TestRuntimeInfo_Liveness(t *testing.T) {
+	t.Parallel()
+
+	info := &RuntimeInfo{modules: []ModuleInfo{
+		{Type: "*lakta.server", Lifecycle: LifecycleSync, State: StateStarted},
+		{Type: "*lakta.worker", Lifecycle: LifecycleAsync, State: StateStarted},
+	}}
+	testza.AssertNil(t, info.Liveness())
+
+	info.setState(1, StateFailed)
+	err := info.Liveness()
+	testza.AssertErrorIs(t, err, ErrModuleFailed)
+	testza.AssertContains(t, err.Error(), "*lakta.worker")
+	testza.AssertNotContains(t, err.Error(), "*lakta.server")
+}
@@ -0,0 +1,43 @@
+package lakta
+
+import "reflect"
+
+// ServiceKey identifies a DI service in the module graph: a type plus, for a
+// qualified service, an instance name. The zero Name is the type's unqualified
+// service, the one Provide and Invoke use.
+type ServiceKey struct {
+	Type reflect.Type
+	Name string
+}
+
+// KeyOf returns the unqualified ServiceKey of T.
+func KeyOf[T any]() ServiceKey {
+	return ServiceKey{Type: reflect.TypeFor[T]()}
+}
+
+// NamedKey returns the ServiceKey of T qualified by name, matching
+// ProvideNamed and InvokeNamed with the same name.
+func NamedKey[T any](name string) ServiceKey {
+	return ServiceKey{Type: reflect.TypeFor[T](), Name: name}
+}
+
+// String renders the key as its type, suffixed with "@name" when qualified.
+func (k ServiceKey) String() string {
+	if k.Name == "" {
+		return k.Type.String()
+	}
+	return k.Type.String() + "@" + k.Name
+}
+
+// unqualifiedKeys wraps Provider/Dependent types as unqualified keys.
+func unqualifiedKeys(ts []reflect.Type) []ServiceKey {
+	if len(ts) == 0 {
+		return nil
+	}
+
+	out := make([]ServiceKey, len(ts))
+	for i, t := range ts {
+		out[i] = ServiceKey{Type: t}
+	}
+	return out
+}
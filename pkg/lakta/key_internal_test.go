@@ -0,0 +1,72 @@
+package lakta
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+// namedKeyModule declares qualified keys through NamedProvider/NamedDependent.
+type namedKeyModule struct {
+	provides []ServiceKey
+	required []ServiceKey
+	optional []ServiceKey
+}
+
+func (*namedKeyModule) Init(context.Context) error     { return nil }
+func (*namedKeyModule) Shutdown(context.Context) error { return nil }
+func (m *namedKeyModule) NamedProvides() []ServiceKey  { return m.provides }
+func (m *namedKeyModule) NamedDependencies() ([]ServiceKey, []ServiceKey) {
+	return m.required, m.optional
+}
+
+func TestServiceKey_String(t *testing.T) {
+	t.Parallel()
+
+	testza.AssertEqual(t, "*lakta.depA", KeyOf[*depA]().String())
+	testza.AssertEqual(t, "*lakta.depA@replica", NamedKey[*depA]("replica").String())
+	testza.AssertEqual(t, KeyOf[*depA](), NamedKey[*depA](""))
+}
+
+func TestSortModules_QualifiedProvidersCoexist(t *testing.T) {
+	t.Parallel()
+
+	primary := &namedKeyModule{provides: []ServiceKey{NamedKey[*depA]("primary")}}
+	replica := &namedKeyModule{provides: []ServiceKey{NamedKey[*depA]("replica")}}
+	unqualified := &declModule{provides: []reflect.Type{reflect.TypeFor[*depA]()}}
+	consumer := &namedKeyModule{required: []ServiceKey{NamedKey[*depA]("replica")}}
+
+	sorted, meta, err := sortModules([]Module{consumer, primary, replica, unqualified})
+	testza.AssertNil(t, err)
+
+	testza.AssertEqual(t, []Module{primary, replica, unqualified, consumer}, sorted)
+	testza.AssertEqual(t, []moduleLink{{from: 1, key: NamedKey[*depA]("replica")}}, meta[3].links)
+
+	// Declaring only NamedDependent is enough to skip the init barrier.
+	testza.AssertEqual(t, [][]int{{0, 1, 2}, {3}}, initWaves(meta))
+}
+
+func TestSortModules_MixedDeclarations(t *testing.T) {
+	t.Parallel()
+
+	provider := &mixedKeyModule{
+		declModule: &declModule{provides: []reflect.Type{reflect.TypeFor[*depA]()}},
+		named:      []ServiceKey{NamedKey[*depB]("extra")},
+	}
+
+	_, meta, err := sortModules([]Module{provider})
+	testza.AssertNil(t, err)
+	testza.AssertEqual(t, []ServiceKey{KeyOf[*depA](), NamedKey[*depB]("extra")}, meta[0].provides)
+	testza.AssertEqual(t, []string{"*lakta.depA", "*lakta.depB@extra"}, describeModules([]Module{provider}, meta)[0].Provides)
+}
+
+// mixedKeyModule implements both Provider and NamedProvider.
+type mixedKeyModule struct {
+	*declModule
+
+	named []ServiceKey
+}
+
+func (m *mixedKeyModule) NamedProvides() []ServiceKey { return m.named }
@@ -3,6 +3,7 @@ package lakta
 import (
 	"context"
 	"reflect"
+	"time"
 )
 
 // Module is the base interface for all lakta modules.
@@ -35,3 +36,33 @@ type Provider interface {
 type Dependent interface {
 	Dependencies() (required, optional []reflect.Type)
 }
+
+// NamedProvider declares qualified services — a type plus an instance name —
+// this module registers with ProvideNamed. Use it instead of Provider for
+// types several module instances register, so each instance keeps its own
+// node in the dependency graph. A module may implement both.
+type NamedProvider interface {
+	NamedProvides() []ServiceKey
+}
+
+// NamedDependent declares qualified services this module needs before Init,
+// resolved with InvokeNamed. Requirement semantics match Dependent. A module
+// may implement both.
+type NamedDependent interface {
+	NamedDependencies() (required, optional []ServiceKey)
+}
+
+// ShutdownTimeouter lets a module bound its own Shutdown more tightly than the
+// runtime-wide budget. The window starts when the module's Shutdown is called
+// and never extends past the runtime budget; non-positive values are ignored.
+type ShutdownTimeouter interface {
+	ShutdownTimeout() time.Duration
+}
+
+// Drainable lets a module stop accepting new work before Shutdown: fail its
+// readiness, deregister, or stop consuming. When shutdown begins the runtime
+// calls Drain on every Drainable module concurrently, then waits the drain
+// delay before the first Shutdown, so load balancers stop routing first.
+type Drainable interface {
+	Drain(ctx context.Context) error
+}
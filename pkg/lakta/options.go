@@ -0,0 +1,191 @@
+package lakta
+
+import (
+	"log/slog"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/samber/do/v2"
+)
+
+// RuntimeConfig holds runtime-level settings. Build it via RuntimeOption values
+// passed to NewRuntimeWithOptions; the zero value of every field means "use the
+// default".
+type RuntimeConfig struct {
+	// Signals are the OS signals Run treats as a shutdown trigger.
+	// Defaults to os.Interrupt and SIGTERM.
+	Signals []os.Signal
+
+	// Injector is the DI container modules register into. When nil, RunContext
+	// adopts an injector carried by its ctx (see WithInjector), else creates one.
+	Injector do.Injector
+
+	// Logger is the fallback used when no module provides a *slog.Logger.
+	// Defaults to slog.Default().
+	Logger *slog.Logger
+
+	// ShutdownTimeout is the runtime-wide shutdown budget. Defaults to
+	// DefaultShutdownTimeout; runtime.shutdown_timeout in config overrides it.
+	ShutdownTimeout time.Duration
+
+	// DrainDelay is how long the runtime waits between draining Drainable
+	// modules and the first Shutdown. Counts against ShutdownTimeout.
+	// Defaults to zero; runtime.drain_delay in config overrides it.
+	DrainDelay time.Duration
+
+	// SlowModuleThreshold is how long a module's config load, Init, start
+	// or Shutdown may take before the runtime logs a warning. Defaults to
+	// DefaultSlowModuleThreshold; negative disables the warnings;
+	// runtime.slow_module_threshold in config overrides it.
+	SlowModuleThreshold time.Duration
+
+	// PanicPolicy selects what a panic in Start or StartAsync does. Defaults
+	// to PanicPropagate; runtime.panic_policy in config overrides it.
+	PanicPolicy PanicPolicy
+
+	// CrashReportDir is the directory crash reports are written to. Empty
+	// keeps them in memory only; runtime.crash_report_dir in config overrides
+	// it.
+	CrashReportDir string
+
+	// ErrorClassifier maps every non-nil error RunContext is about to return.
+	// Returning nil treats the run as a clean stop; returning a different error
+	// replaces it. Nil leaves errors untouched.
+	ErrorClassifier func(err error) error
+
+	// Hooks are lifecycle observers registered before the first BeforeInit.
+	Hooks []LifecycleHook
+
+	// Supervision sets restart policies per AsyncModule, overriding Supervised.
+	// The last entry for a module wins.
+	Supervision []SupervisedModule
+
+	// Conditions decide per module whether it runs at all; a module whose
+	// condition returns false is skipped before the graph is sorted. The last
+	// entry for a module wins.
+	Conditions []ConditionalModule
+}
+
+// SupervisedModule pairs an AsyncModule with its restart policy.
+type SupervisedModule struct {
+	Module Module
+	Policy SupervisionPolicy
+}
+
+// ConditionalModule pairs a module with the condition deciding whether it runs.
+type ConditionalModule struct {
+	Module    Module
+	Condition ModuleCondition
+}
+
+// RuntimeOption manipulates RuntimeConfig.
+type RuntimeOption func(cfg *RuntimeConfig)
+
+// NewDefaultRuntimeConfig returns the default runtime configuration.
+func NewDefaultRuntimeConfig() RuntimeConfig {
+	return RuntimeConfig{
+		Signals: []os.Signal{os.Interrupt, syscall.SIGTERM},
+	}
+}
+
+// WithSignals replaces the signals Run treats as a shutdown trigger
+// (default: SIGINT, SIGTERM).
+func WithSignals(signals ...os.Signal) RuntimeOption {
+	return func(cfg *RuntimeConfig) {
+		cfg.Signals = signals
+	}
+}
+
+// WithRuntimeInjector sets the DI injector explicitly instead of passing it
+// through the context. It takes precedence over a ctx-carried injector.
+func WithRuntimeInjector(injector do.Injector) RuntimeOption {
+	return func(cfg *RuntimeConfig) {
+		cfg.Injector = injector
+	}
+}
+
+// WithFallbackLogger sets the logger used when no module provides a
+// *slog.Logger (default: slog.Default()).
+func WithFallbackLogger(logger *slog.Logger) RuntimeOption {
+	return func(cfg *RuntimeConfig) {
+		cfg.Logger = logger
+	}
+}
+
+// WithShutdownTimeout sets the runtime-wide shutdown budget
+// (default: DefaultShutdownTimeout). runtime.shutdown_timeout in config wins.
+func WithShutdownTimeout(d time.Duration) RuntimeOption {
+	return func(cfg *RuntimeConfig) {
+		cfg.ShutdownTimeout = d
+	}
+}
+
+// WithDrainDelay sets how long the runtime waits after draining before it
+// shuts modules down (default: none). runtime.drain_delay in config wins.
+func WithDrainDelay(d time.Duration) RuntimeOption {
+	return func(cfg *RuntimeConfig) {
+		cfg.DrainDelay = d
+	}
+}
+
+// WithSlowModuleThreshold sets how long a module step may take before the
+// runtime warns about it (default: DefaultSlowModuleThreshold; negative
+// disables). runtime.slow_module_threshold in config wins.
+func WithSlowModuleThreshold(d time.Duration) RuntimeOption {
+	return func(cfg *RuntimeConfig) {
+		cfg.SlowModuleThreshold = d
+	}
+}
+
+// WithPanicPolicy sets what a panic in Start or StartAsync does
+// (default: PanicPropagate). runtime.panic_policy in config wins.
+func WithPanicPolicy(policy PanicPolicy) RuntimeOption {
+	return func(cfg *RuntimeConfig) {
+		cfg.PanicPolicy = policy
+	}
+}
+
+// WithCrashReportDir sets the directory crash reports are written to (default:
+// none, reports stay in memory). runtime.crash_report_dir in config wins.
+func WithCrashReportDir(dir string) RuntimeOption {
+	return func(cfg *RuntimeConfig) {
+		cfg.CrashReportDir = dir
+	}
+}
+
+// WithErrorClassifier sets a function that maps every non-nil error RunContext
+// returns, e.g. to treat a known sentinel as a clean stop.
+func WithErrorClassifier(fn func(err error) error) RuntimeOption {
+	return func(cfg *RuntimeConfig) {
+		cfg.ErrorClassifier = fn
+	}
+}
+
+// WithLifecycleHook registers a lifecycle observer that sees every transition,
+// starting with the first BeforeInit. May be passed multiple times.
+func WithLifecycleHook(hook LifecycleHook) RuntimeOption {
+	return func(cfg *RuntimeConfig) {
+		cfg.Hooks = append(cfg.Hooks, hook)
+	}
+}
+
+// WithSupervision sets the restart policy for an AsyncModule, taking precedence
+// over its own Supervised policy. module must be the same pointer, or an equal
+// comparable value, passed to the runtime; a value holding a slice, map or func
+// matches no module.
+func WithSupervision(module Module, policy SupervisionPolicy) RuntimeOption {
+	return func(cfg *RuntimeConfig) {
+		cfg.Supervision = append(cfg.Supervision, SupervisedModule{Module: module, Policy: policy})
+	}
+}
+
+// WithCondition skips module at boot unless cond returns true. module must be
+// the same pointer, or an equal comparable value, passed to the runtime; a
+// value holding a slice, map or func matches no module. Applies on top of the
+// module's own enabled config key.
+func WithCondition(module Module, cond ModuleCondition) RuntimeOption {
+	return func(cfg *RuntimeConfig) {
+		cfg.Conditions = append(cfg.Conditions, ConditionalModule{Module: module, Condition: cond})
+	}
+}
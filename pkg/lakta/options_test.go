@@ -0,0 +1,123 @@
+package lakta_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/Vilsol/lakta/pkg/lakta"
+	"github.com/Vilsol/lakta/pkg/testkit"
+	"github.com/samber/do/v2"
+)
+
+type seeded struct{ val string }
+
+func TestRuntimeOptions_Injector(t *testing.T) {
+	t.Parallel()
+
+	injector := do.New()
+	do.ProvideValue(injector, &seeded{val: "explicit"})
+
+	var got *seeded
+	m := testkit.NewMockModule()
+	m.OnInit = func(ctx context.Context) error {
+		var err error
+		got, err = lakta.Invoke[*seeded](ctx)
+		return err
+	}
+
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{m}, lakta.WithRuntimeInjector(injector))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testza.AssertNil(t, rt.RunContext(ctx))
+	testza.AssertNotNil(t, got)
+	testza.AssertEqual(t, "explicit", got.val)
+}
+
+func TestRuntimeOptions_FallbackLoggerAndShutdownTimeout(t *testing.T) {
+	t.Parallel()
+
+	fallback := slog.New(slog.NewTextHandler(io.Discard, nil))
+	injector := do.New()
+
+	var got *slog.Logger
+	m := testkit.NewMockModule()
+	m.OnShutdown = func(ctx context.Context) error {
+		var err error
+		got, err = lakta.Invoke[*slog.Logger](ctx)
+		return err
+	}
+
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{m},
+		lakta.WithRuntimeInjector(injector),
+		lakta.WithFallbackLogger(fallback),
+		lakta.WithShutdownTimeout(7*time.Second),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testza.AssertNil(t, rt.RunContext(ctx))
+	testza.AssertEqual(t, fallback, got)
+
+	info, err := do.Invoke[*lakta.RuntimeInfo](injector)
+	testza.AssertNil(t, err)
+	testza.AssertEqual(t, 7*time.Second, info.Snapshot()[0].ShutdownTimeout)
+}
+
+func TestRuntimeOptions_ErrorClassifier(t *testing.T) {
+	t.Parallel()
+
+	errExpected := errors.New("expected stop")
+
+	m := testkit.NewMockSyncModule()
+	m.StartErr = errExpected
+
+	var classified error
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{m}, lakta.WithErrorClassifier(func(err error) error {
+		classified = err
+		if errors.Is(err, errExpected) {
+			return nil
+		}
+		return err
+	}))
+
+	testza.AssertNil(t, rt.RunContext(context.Background()))
+	testza.AssertTrue(t, errors.Is(classified, errExpected))
+}
+
+//nolint:paralleltest // sends a process-global SIGHUP; must run serially so no peer test catches it
+func TestRuntimeOptions_Signals(t *testing.T) {
+	blocker := testkit.NewMockSyncModule()
+	blocker.BlockStart = make(chan struct{})
+
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{blocker}, lakta.WithSignals(syscall.SIGHUP))
+
+	done := make(chan error, 1)
+	go func() { done <- rt.Run() }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for blocker.StartCalls.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("sync module never started")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	testza.AssertNil(t, syscall.Kill(syscall.Getpid(), syscall.SIGHUP))
+
+	select {
+	case err := <-done:
+		testza.AssertNil(t, err)
+		testza.AssertEqual(t, int32(1), blocker.ShutdownCalls.Load())
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after SIGHUP")
+	}
+}
@@ -0,0 +1,173 @@
+package lakta
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vilsol/slox"
+	"github.com/samber/oops"
+)
+
+// ReadyNotifier is a SyncModule that reports when it is ready to serve, such
+// as a server once its listener is bound. When NotifiesReady returns true the
+// module stays StateStarting after Start is entered, and the runtime is not
+// ready, until it calls MarkReady with the context Start received (or
+// SyncCtx.MarkReady). Other SyncModules count as started as soon as Start is
+// entered.
+type ReadyNotifier interface {
+	SyncModule
+	NotifiesReady() bool
+}
+
+type readyKey struct{}
+
+// readySignal fires a module's ready transition at most once.
+type readySignal struct {
+	once  sync.Once
+	ready func()
+}
+
+// MarkReady reports that the ReadyNotifier whose Start received ctx is ready.
+// Only the first call counts; a ctx without a ready signal is a no-op.
+func MarkReady(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+
+	if signal, ok := ctx.Value(readyKey{}).(*readySignal); ok {
+		signal.once.Do(signal.ready)
+	}
+}
+
+// beginSyncStart records the SyncModule at order as starting and returns the
+// context its Start runs under. A ReadyNotifier gets a ready signal in that
+// context and stays StateStarting until MarkReady, which records its start
+// step and emits PhaseReady; any other SyncModule is StateStarted right away.
+func (r *Runtime) beginSyncStart(ctx context.Context, info *RuntimeInfo, order int, m SyncModule) context.Context {
+	if n, ok := m.(ReadyNotifier); !ok || !n.NotifiesReady() {
+		info.setState(order, StateStarted)
+		return ctx
+	}
+
+	info.setState(order, StateStarting)
+	startedAt := time.Now()
+
+	return context.WithValue(ctx, readyKey{}, &readySignal{ready: func() {
+		if !info.markReady(order) {
+			return
+		}
+
+		duration := r.recordStep(ctx, info, order, StepStart, startedAt, nil)
+		r.emit(ctx, info, order, LifecycleEvent{Phase: PhaseReady, Module: m, Duration: duration})
+	}})
+}
+
+// requiredProviders returns the orders of the modules providing the required
+// dependencies in meta, each once.
+func requiredProviders(meta moduleMeta) []int {
+	var out []int
+	for _, link := range meta.links {
+		if !link.optional && !slices.Contains(out, link.from) {
+			out = append(out, link.from)
+		}
+	}
+	return out
+}
+
+// awaitProviders blocks until every module providing a required dependency of
+// the SyncModule at order is ready, so it does not start serving before, say,
+// the server it proxies to is listening. See RuntimeInfo.waitBooted.
+func (r *Runtime) awaitProviders(ctx context.Context, info *RuntimeInfo, order int, meta moduleMeta) error {
+	providers := requiredProviders(meta)
+	if len(providers) == 0 {
+		return nil
+	}
+
+	return info.waitBooted(ctx, order, providers)
+}
+
+// waitBooted blocks until every module in orders is ready (see
+// ModuleInfo.booted) on behalf of the module at order. It fails when one of
+// them fails or stops first, once shutdown begins, or when ctx ends.
+func (ri *RuntimeInfo) waitBooted(ctx context.Context, order int, orders []int) error {
+	logged := false
+
+	for {
+		ri.mu.Lock()
+		module := ri.modules[order].displayName()
+
+		var pending []string
+		var err error
+		for _, o := range orders {
+			m := ri.modules[o]
+			switch {
+			case m.booted():
+			case m.State == StateFailed || m.State == StateStopped || m.State == StateDetached:
+				err = oops.
+					With("provider", m.displayName()).
+					Errorf("%s %s before %s could start", m.displayName(), m.State, module)
+			default:
+				pending = append(pending, m.displayName())
+			}
+		}
+
+		if err == nil && ri.draining {
+			err = oops.Wrapf(ErrDraining, "shutdown began before %s could start", module)
+		}
+
+		if ri.changed == nil {
+			ri.changed = make(chan struct{})
+		}
+		changed := ri.changed
+		ri.mu.Unlock()
+
+		switch {
+		case err != nil:
+			return err
+		case len(pending) == 0:
+			return nil
+		case !logged:
+			slox.Debug(ctx, "waiting for providers to be ready",
+				slog.String("module", module), slog.Any("providers", pending))
+			logged = true
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return oops.Wrapf(ctx.Err(), "%s waiting for %s", module, strings.Join(pending, ", "))
+		}
+	}
+}
+
+// WaitReady blocks until the current run of the runtime is ready, for callers
+// that started RunContext on another goroutine; see RuntimeInfo.WaitReady. It
+// wraps ErrNotRunning when the run ended before its modules were sorted.
+func (r *Runtime) WaitReady(ctx context.Context) error {
+	select {
+	case <-r.booted:
+	case <-ctx.Done():
+		return oops.Wrapf(ctx.Err(), "runtime did not boot")
+	}
+
+	info := r.info.Load()
+	if info == nil {
+		return oops.Wrapf(ErrNotRunning, "runtime stopped before it was ready")
+	}
+
+	return info.WaitReady(ctx)
+}
+
+// markBooted releases WaitReady once the run's RuntimeInfo exists or the run
+// ended without one. Safe to call repeatedly.
+func (r *Runtime) markBooted() {
+	if r.booted == nil {
+		return
+	}
+
+	r.bootOnce.Do(func() { close(r.booted) })
+}
@@ -0,0 +1,242 @@
+package lakta_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/Vilsol/lakta/pkg/lakta"
+	"github.com/Vilsol/lakta/pkg/testkit"
+	"github.com/samber/do/v2"
+)
+
+func TestRuntime_WaitReadyNotifier(t *testing.T) {
+	t.Parallel()
+
+	rec := &eventRecorder{}
+	m := testkit.NewMockSyncModule()
+	m.NotifyReady = true
+	m.BlockStart = make(chan struct{})
+	m.OnStart = func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}
+
+	injector := do.New()
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{m},
+		lakta.WithRuntimeInjector(injector), lakta.WithLifecycleHook(rec.hook))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- rt.RunContext(ctx) }()
+
+	waitFor(t, func() bool { return m.StartCalls.Load() == 1 })
+
+	info, err := do.Invoke[*lakta.RuntimeInfo](injector)
+	testza.AssertNil(t, err)
+	testza.AssertEqual(t, lakta.StateStarting, info.Snapshot()[0].State)
+	testza.AssertNotNil(t, info.Readiness())
+
+	early, earlyCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer earlyCancel()
+	err = rt.WaitReady(early)
+	testza.AssertTrue(t, errors.Is(err, context.DeadlineExceeded))
+	testza.AssertContains(t, err.Error(), "runtime not ready")
+
+	close(m.BlockStart)
+
+	wait, waitCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer waitCancel()
+	testza.AssertNil(t, rt.WaitReady(wait))
+	testza.AssertEqual(t, lakta.StateStarted, info.Snapshot()[0].State)
+
+	ready, ok := rec.find(m, lakta.PhaseReady)
+	testza.AssertTrue(t, ok)
+	testza.AssertEqual(t, info.Snapshot()[0].StartDuration, ready.Duration)
+
+	cancel()
+	testza.AssertNil(t, <-done)
+}
+
+func TestRuntime_WaitReadyPlainSyncModule(t *testing.T) {
+	t.Parallel()
+
+	rec := &eventRecorder{}
+	m := testkit.NewMockSyncModule()
+	m.BlockStart = make(chan struct{})
+
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{m}, lakta.WithLifecycleHook(rec.hook))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- rt.RunContext(ctx) }()
+
+	wait, waitCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer waitCancel()
+	testza.AssertNil(t, rt.WaitReady(wait))
+
+	_, ok := rec.find(m, lakta.PhaseReady)
+	testza.AssertFalse(t, ok, "only a ReadyNotifier emits PhaseReady")
+
+	cancel()
+	testza.AssertNil(t, <-done)
+}
+
+func TestRuntime_WaitReadyFailure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		modules func() []lakta.Module
+		target  error
+	}{
+		{
+			name: "init failure",
+			modules: func() []lakta.Module {
+				m := testkit.NewMockModule()
+				m.InitErr = errors.New("boom")
+				return []lakta.Module{m}
+			},
+		},
+		{
+			name: "config source",
+			modules: func() []lakta.Module {
+				return []lakta.Module{&brokenSource{}}
+			},
+			target: lakta.ErrNotRunning,
+		},
+		{
+			name: "unmet dependency",
+			modules: func() []lakta.Module {
+				m := testkit.NewMockProviderModule()
+				m.RequiredDeps = []reflect.Type{reflect.TypeFor[*depTypeA]()}
+				return []lakta.Module{m}
+			},
+			target: lakta.ErrNotRunning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt := lakta.NewRuntime(tt.modules()...)
+
+			done := make(chan error, 1)
+			go func() { done <- rt.RunContext(context.Background()) }()
+
+			wait, waitCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer waitCancel()
+
+			err := rt.WaitReady(wait)
+			testza.AssertNotNil(t, err)
+			testza.AssertFalse(t, errors.Is(err, context.DeadlineExceeded))
+			if tt.target != nil {
+				testza.AssertErrorIs(t, err, tt.target)
+			}
+
+			testza.AssertNotNil(t, <-done)
+		})
+	}
+}
+
+func TestMarkReady_WithoutSignal(t *testing.T) {
+	t.Parallel()
+
+	lakta.MarkReady(context.Background())
+	lakta.MarkReady(nil) //nolint:staticcheck // nil ctx is tolerated
+}
+
+// syncProvider is a MockSyncModule that declares dependencies.
+type syncProvider struct {
+	*testkit.MockSyncModule
+
+	provides []reflect.Type
+	requires []reflect.Type
+}
+
+func (m *syncProvider) Provides() []reflect.Type { return m.provides }
+
+func (m *syncProvider) Dependencies() ([]reflect.Type, []reflect.Type) { return m.requires, nil }
+
+func blockUntilDone(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func TestRunContext_SyncStartWaitsForProviders(t *testing.T) {
+	t.Parallel()
+
+	backend := &syncProvider{MockSyncModule: testkit.NewMockSyncModule(), provides: []reflect.Type{reflect.TypeFor[*depTypeA]()}}
+	backend.NotifyReady = true
+	backend.BlockStart = make(chan struct{})
+	backend.OnStart = blockUntilDone
+
+	frontend := &syncProvider{MockSyncModule: testkit.NewMockSyncModule(), requires: []reflect.Type{reflect.TypeFor[*depTypeA]()}}
+	frontend.OnStart = blockUntilDone
+
+	independent := testkit.NewMockSyncModule()
+	independent.OnStart = blockUntilDone
+
+	injector := do.New()
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{frontend, backend, independent}, lakta.WithRuntimeInjector(injector))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- rt.RunContext(ctx) }()
+
+	waitFor(t, func() bool { return backend.StartCalls.Load() == 1 && independent.StartCalls.Load() == 1 })
+	time.Sleep(50 * time.Millisecond)
+	testza.AssertEqual(t, int32(0), frontend.StartCalls.Load(), "frontend must wait for backend to be ready")
+
+	info, err := do.Invoke[*lakta.RuntimeInfo](injector)
+	testza.AssertNil(t, err)
+	waiting := 0
+	for _, m := range info.Snapshot() {
+		if len(m.Requires) > 0 {
+			testza.AssertEqual(t, lakta.StateInitialized, m.State)
+			waiting++
+		}
+	}
+	testza.AssertEqual(t, 1, waiting)
+
+	close(backend.BlockStart)
+	waitFor(t, func() bool { return frontend.StartCalls.Load() == 1 })
+
+	wait, waitCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer waitCancel()
+	testza.AssertNil(t, rt.WaitReady(wait))
+
+	cancel()
+	testza.AssertNil(t, <-done)
+}
+
+func TestRunContext_SyncStartSkippedWhenRuntimeStopsFirst(t *testing.T) {
+	t.Parallel()
+
+	backend := &syncProvider{MockSyncModule: testkit.NewMockSyncModule(), provides: []reflect.Type{reflect.TypeFor[*depTypeA]()}}
+	backend.NotifyReady = true
+	backend.BlockStart = make(chan struct{})
+
+	frontend := &syncProvider{MockSyncModule: testkit.NewMockSyncModule(), requires: []reflect.Type{reflect.TypeFor[*depTypeA]()}}
+
+	failer := testkit.NewMockSyncModule()
+	failer.BlockStart = make(chan struct{})
+	failer.StartErr = errors.New("listener closed")
+
+	rt := lakta.NewRuntime(frontend, backend, failer)
+
+	done := make(chan error, 1)
+	go func() { done <- rt.RunContext(context.Background()) }()
+
+	waitFor(t, func() bool { return backend.StartCalls.Load() == 1 && failer.StartCalls.Load() == 1 })
+	close(failer.BlockStart)
+
+	err := <-done
+	testza.AssertEqual(t, lakta.ExitStartFailed, lakta.ExitReasonOf(err))
+	testza.AssertEqual(t, int32(0), frontend.StartCalls.Load())
+	testza.AssertEqual(t, int32(1), frontend.ShutdownCalls.Load())
+}
@@ -0,0 +1,59 @@
+package lakta
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Vilsol/slox"
+	"github.com/knadh/koanf/v2"
+)
+
+// wireReload registers the reload hooks of module with notifier. live, when
+// set, gates every hook, for modules that can be detached.
+//
+// A TransactionalReloadable module joins the notifier's two-phase reload when
+// it runs one; otherwise it prepares and commits on its own in an OnReload
+// callback, so a failed prepare leaves only that module on its old config.
+func wireReload(ctx context.Context, notifier ReloadNotifier, module Module, live func() bool) {
+	active := func() bool { return live == nil || live() }
+
+	if tr, ok := module.(TransactionalReloadable); ok {
+		prepare := func(k *koanf.Koanf) (PreparedReload, error) {
+			if !active() {
+				return PreparedReload{}, nil
+			}
+			return tr.PrepareReload(k)
+		}
+
+		if tn, ok := notifier.(TransactionalReloadNotifier); ok {
+			tn.OnPrepare(moduleLabel(module), prepare)
+		} else {
+			notifier.OnReload(func(k *koanf.Koanf) {
+				prepared, err := prepare(k)
+				if err != nil {
+					slox.Error(ctx, "module failed to prepare config reload; keeping its previous config",
+						slog.String("name", moduleLabel(module)), slog.Any("error", err))
+					return
+				}
+				if prepared.Commit != nil {
+					prepared.Commit()
+				}
+			})
+		}
+	} else if hr, ok := module.(HotReloadable); ok {
+		notifier.OnReload(func(k *koanf.Koanf) {
+			if active() {
+				hr.OnReload(k)
+			}
+		})
+	}
+
+	if v, ok := module.(ValidatableModule); ok {
+		notifier.OnValidate(func(k *koanf.Koanf) error {
+			if !active() {
+				return nil
+			}
+			return v.ValidateReload(k)
+		})
+	}
+}
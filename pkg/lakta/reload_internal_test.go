@@ -0,0 +1,82 @@
+package lakta
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/knadh/koanf/v2"
+)
+
+// preparingModule is a TransactionalReloadable module that is also
+// HotReloadable, recording what reaches it.
+type preparingModule struct {
+	prepareErr error
+	events     []string
+}
+
+func (*preparingModule) Init(context.Context) error     { return nil }
+func (*preparingModule) Shutdown(context.Context) error { return nil }
+func (m *preparingModule) OnReload(*koanf.Koanf)        { m.events = append(m.events, "reload") }
+
+func (m *preparingModule) PrepareReload(*koanf.Koanf) (PreparedReload, error) {
+	m.events = append(m.events, "prepare")
+	if m.prepareErr != nil {
+		return PreparedReload{}, m.prepareErr
+	}
+	return PreparedReload{Commit: func() { m.events = append(m.events, "commit") }}, nil
+}
+
+// preparingNotifier is a recordingNotifier that runs two-phase reloads.
+type preparingNotifier struct {
+	recordingNotifier
+
+	names    []string
+	prepares []func(*koanf.Koanf) (PreparedReload, error)
+}
+
+func (n *preparingNotifier) OnPrepare(name string, fn func(*koanf.Koanf) (PreparedReload, error)) {
+	n.names = append(n.names, name)
+	n.prepares = append(n.prepares, fn)
+}
+
+func TestWireReload_Transactional(t *testing.T) {
+	t.Parallel()
+
+	n := &preparingNotifier{}
+	module := &preparingModule{}
+	live := true
+	wireReload(t.Context(), n, module, func() bool { return live })
+
+	// The prepare phase replaces OnReload.
+	testza.AssertEqual(t, []string{"*lakta.preparingModule"}, n.names)
+	testza.AssertLen(t, n.reload, 0)
+
+	prepared, err := n.prepares[0](koanf.New("."))
+	testza.AssertNoError(t, err)
+	prepared.Commit()
+	testza.AssertEqual(t, []string{"prepare", "commit"}, module.events)
+
+	live = false
+	prepared, err = n.prepares[0](koanf.New("."))
+	testza.AssertNoError(t, err)
+	testza.AssertNil(t, prepared.Commit)
+	testza.AssertEqual(t, []string{"prepare", "commit"}, module.events)
+}
+
+func TestWireReload_PlainNotifierPreparesAndCommits(t *testing.T) {
+	t.Parallel()
+
+	n := &recordingNotifier{}
+	module := &preparingModule{}
+	wireReload(t.Context(), n, module, nil)
+
+	n.fire(koanf.New("."))
+	testza.AssertEqual(t, []string{"prepare", "commit"}, module.events)
+
+	module.prepareErr = errors.New("boom")
+	module.events = nil
+	n.fire(koanf.New("."))
+	testza.AssertEqual(t, []string{"prepare"}, module.events)
+}
@@ -16,26 +16,33 @@ import (
 const envDebugWiring = "LAKTA_DEBUG_WIRING"
 
 // RenderWiringReport renders a RuntimeInfo snapshot as an aligned text table
-// with columns order, module, lifecycle, provides, consumes, and init duration.
+// with columns order, wave, module, lifecycle, provides, consumes, init
+// duration, and effective shutdown budget. Skipped modules show "skipped" in
+// place of their init duration.
 // When prov is non-empty, a config-provenance section (key -> origin) is
 // appended. Used by the boot-time debug log and the LAKTA_DEBUG_WIRING=1 dump.
 func RenderWiringReport(info []ModuleInfo, prov map[string]string) string {
-	headers := []string{"ORDER", "MODULE", "LIFECYCLE", "PROVIDES", "CONSUMES", "INIT"}
+	headers := []string{"ORDER", "WAVE", "MODULE", "LIFECYCLE", "PROVIDES", "CONSUMES", "INIT", "SHUTDOWN"}
 	rows := make([][]string, 0, len(info))
 
 	for _, m := range info {
-		module := m.Type
-		if m.Name != "" {
-			module = m.Type + " (" + m.Name + ")"
+		if m.State == StateSkipped {
+			rows = append(rows, []string{
+				"-", "-", m.displayName(), m.Lifecycle.String(),
+				joinOrDash(m.Provides), joinOrDash(consumes(m)), StateSkipped.String(), "-",
+			})
+			continue
 		}
 
 		rows = append(rows, []string{
 			strconv.Itoa(m.InitOrder),
-			module,
+			strconv.Itoa(m.InitWave),
+			m.displayName(),
 			m.Lifecycle.String(),
 			joinOrDash(m.Provides),
 			joinOrDash(consumes(m)),
 			m.InitDuration.String(),
+			m.ShutdownTimeout.String(),
 		})
 	}
 
@@ -44,3 +44,20 @@ func TestRenderWiringReport(t *testing.T) {
 	testza.AssertTrue(t, strings.Contains(withProv, "config provenance"))
 	testza.AssertTrue(t, strings.Contains(withProv, "a.b = file"))
 }
+
+func TestRenderWiringReport_Skipped(t *testing.T) {
+	t.Parallel()
+
+	out := lakta.RenderWiringReport([]lakta.ModuleInfo{{
+		Type:      "otel.Module",
+		InitOrder: -1,
+		InitWave:  -1,
+		Provides:  []string{"metric.MeterProvider"},
+		Lifecycle: lakta.LifecycleInit,
+		State:     lakta.StateSkipped,
+	}}, nil)
+
+	testza.AssertTrue(t, strings.Contains(out, "otel.Module"))
+	testza.AssertTrue(t, strings.Contains(out, "skipped"))
+	testza.AssertTrue(t, strings.Contains(out, "metric.MeterProvider"))
+}
@@ -0,0 +1,162 @@
+package lakta
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/Vilsol/slox"
+	"github.com/samber/do/v2"
+)
+
+type requestScopeKey struct{}
+
+// requestProviders is the registry of ProvideRequest providers of one
+// injector, keyed by service name. It is provided in that injector, so a
+// runtime's modules see their own registry and, through parent, those of the
+// runtimes above it.
+type requestProviders struct {
+	owner     do.Injector
+	parent    *requestProviders
+	mu        sync.RWMutex
+	providers map[string]func(ctx context.Context, injector do.Injector)
+}
+
+// requestProvidersMu serializes registry creation, so concurrent first
+// ProvideRequest calls agree on one registry.
+var requestProvidersMu sync.Mutex
+
+// requestProvidersOf returns the registry owned by injector, creating and
+// providing it on first use.
+func requestProvidersOf(injector do.Injector) *requestProviders {
+	requestProvidersMu.Lock()
+	defer requestProvidersMu.Unlock()
+
+	parent, err := do.Invoke[*requestProviders](injector)
+	if err == nil && parent.owner == injector {
+		return parent
+	}
+
+	registry := &requestProviders{
+		owner:     injector,
+		parent:    parent,
+		providers: make(map[string]func(context.Context, do.Injector)),
+	}
+	do.ProvideValue(injector, registry)
+
+	return registry
+}
+
+// add registers provide under name, panicking like do.Provide when name is
+// already declared.
+func (p *requestProviders) add(name string, provide func(context.Context, do.Injector)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.providers[name]; ok {
+		panic(fmt.Errorf("DI: request-scoped service `%s` has already been declared", name))
+	}
+	p.providers[name] = provide
+}
+
+// lookup finds the provider of name in p or its parents.
+func (p *requestProviders) lookup(name string) func(context.Context, do.Injector) {
+	for ; p != nil; p = p.parent {
+		p.mu.RLock()
+		provide, ok := p.providers[name]
+		p.mu.RUnlock()
+
+		if ok {
+			return provide
+		}
+	}
+
+	return nil
+}
+
+// requestScope is the per-request injector BeginRequest puts in the context.
+// Its injector is only created once a request-scoped service is invoked, and
+// providers are registered into it on their first Invoke.
+type requestScope struct {
+	providers *requestProviders
+
+	mu       sync.Mutex
+	injector *do.RootScope
+	provided map[string]bool
+}
+
+// ProvideRequest registers a request-scoped provider for T. Within a request
+// started by BeginRequest — every fiber, gRPC and connect request — the first
+// Invoke[T] calls provider with that Invoke's context and later ones reuse the
+// value until the request ends, when it is disposed of like any do service
+// (do.Shutdowner and friends). Outside a request Invoke[T] resolves the
+// injector as usual. Call it from Init.
+func ProvideRequest[T any](ctx context.Context, provider func(ctx context.Context) (T, error)) {
+	requestProvidersOf(SharedInjector(ctx)).add(do.NameOf[T](), func(ctx context.Context, injector do.Injector) {
+		do.Provide(injector, func(_ do.Injector) (T, error) {
+			return provider(ctx)
+		})
+	})
+}
+
+// BeginRequest returns ctx carrying a fresh request scope for the
+// ProvideRequest providers visible from ctx's injector, and the func ending
+// it. end disposes of every request-scoped service created during the request,
+// logging failures; call it once the request is done.
+func BeginRequest(ctx context.Context) (reqCtx context.Context, end func()) {
+	scope := &requestScope{}
+	if injector, ok := tryInjector(ctx); ok {
+		if providers, err := do.Invoke[*requestProviders](injector); err == nil {
+			scope.providers = providers
+		}
+	}
+
+	reqCtx = context.WithValue(ctx, requestScopeKey{}, scope)
+
+	return reqCtx, func() { scope.end(context.WithoutCancel(reqCtx)) }
+}
+
+// requestInjector returns the request injector with the provider of name
+// registered, if ctx carries a request scope that has one.
+func requestInjector(ctx context.Context, name string) (do.Injector, bool) { //nolint:ireturn
+	scope, ok := ctx.Value(requestScopeKey{}).(*requestScope)
+	if !ok || scope.providers == nil {
+		return nil, false
+	}
+
+	provide := scope.providers.lookup(name)
+	if provide == nil {
+		return nil, false
+	}
+
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+
+	if scope.injector == nil {
+		scope.injector = do.New()
+		scope.provided = make(map[string]bool)
+	}
+
+	if !scope.provided[name] {
+		provide(ctx, scope.injector)
+		scope.provided[name] = true
+	}
+
+	return scope.injector, true
+}
+
+// end shuts down the request injector, if one was created.
+func (s *requestScope) end(ctx context.Context) {
+	s.mu.Lock()
+	injector := s.injector
+	s.mu.Unlock()
+
+	if injector == nil {
+		return
+	}
+
+	if report := injector.ShutdownWithContext(ctx); !report.Succeed {
+		slox.Warn(ctx, "failed disposing request-scoped services", slog.Any("error", report))
+	}
+}
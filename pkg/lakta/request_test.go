@@ -0,0 +1,114 @@
+package lakta_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/Vilsol/lakta/pkg/lakta"
+	"github.com/samber/do/v2"
+)
+
+type principalKey struct{}
+
+// requestTx is a request-scoped service recording its disposal.
+type requestTx struct {
+	principal string
+	closed    *atomic.Int32
+}
+
+func (tx *requestTx) Shutdown() { tx.closed.Add(1) }
+
+type sharedValue struct{ val string }
+
+func TestProvideRequest_OnePerRequest(t *testing.T) {
+	t.Parallel()
+
+	var created, closed atomic.Int32
+
+	ctx := lakta.WithInjector(context.Background(), do.New())
+	lakta.ProvideRequest(ctx, func(ctx context.Context) (*requestTx, error) {
+		created.Add(1)
+		principal, _ := ctx.Value(principalKey{}).(string)
+		return &requestTx{principal: principal, closed: &closed}, nil
+	})
+
+	_, err := lakta.Invoke[*requestTx](ctx)
+	testza.AssertNotNil(t, err, "request-scoped services only resolve inside a request")
+
+	reqCtx, end := lakta.BeginRequest(context.WithValue(ctx, principalKey{}, "alice"))
+
+	first, err := lakta.Invoke[*requestTx](reqCtx)
+	testza.AssertNil(t, err)
+	testza.AssertEqual(t, "alice", first.principal)
+
+	second, err := lakta.Invoke[*requestTx](reqCtx)
+	testza.AssertNil(t, err)
+	testza.AssertEqual(t, first, second)
+	testza.AssertEqual(t, int32(1), created.Load())
+
+	otherCtx, endOther := lakta.BeginRequest(context.WithValue(ctx, principalKey{}, "bob"))
+	other, err := lakta.Invoke[*requestTx](otherCtx)
+	testza.AssertNil(t, err)
+	testza.AssertEqual(t, "bob", other.principal)
+
+	end()
+	testza.AssertEqual(t, int32(1), closed.Load())
+
+	endOther()
+	testza.AssertEqual(t, int32(2), closed.Load())
+}
+
+func TestProvideRequest_FallsBackToInjector(t *testing.T) {
+	t.Parallel()
+
+	ctx := lakta.WithInjector(context.Background(), do.New())
+	lakta.ProvideValue(ctx, &sharedValue{val: "shared"})
+	lakta.ProvideRequest(ctx, func(ctx context.Context) (*requestTx, error) {
+		shared, err := lakta.Invoke[*sharedValue](ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &requestTx{principal: shared.val, closed: &atomic.Int32{}}, nil
+	})
+
+	reqCtx, end := lakta.BeginRequest(ctx)
+	defer end()
+
+	tx, err := lakta.Invoke[*requestTx](reqCtx)
+	testza.AssertNil(t, err)
+	testza.AssertEqual(t, "shared", tx.principal)
+
+	shared, err := lakta.Invoke[*sharedValue](reqCtx)
+	testza.AssertNil(t, err)
+	testza.AssertEqual(t, "shared", shared.val)
+}
+
+func TestProvideRequest_DuplicatePanics(t *testing.T) {
+	t.Parallel()
+
+	ctx := lakta.WithInjector(context.Background(), do.New())
+	provider := func(context.Context) (*requestTx, error) { return &requestTx{}, nil }
+	lakta.ProvideRequest(ctx, provider)
+
+	panicked := false
+	func() {
+		defer func() {
+			if recover() != nil {
+				panicked = true
+			}
+		}()
+		lakta.ProvideRequest(ctx, provider)
+	}()
+
+	testza.AssertTrue(t, panicked)
+}
+
+func TestBeginRequest_WithoutInjector(t *testing.T) {
+	t.Parallel()
+
+	reqCtx, end := lakta.BeginRequest(context.Background())
+	testza.AssertFalse(t, lakta.HasInjector(reqCtx))
+	end()
+}
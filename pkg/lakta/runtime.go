@@ -5,13 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"os"
 	"os/signal"
-	"reflect"
 	"runtime/debug"
 	"slices"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 
 	"github.com/Vilsol/slox"
@@ -21,24 +19,113 @@ import (
 	"github.com/sourcegraph/conc/pool"
 )
 
+// DefaultShutdownTimeout is the runtime-wide shutdown budget used when
+// runtime.shutdown_timeout is not configured.
 const DefaultShutdownTimeout = 30 * time.Second
 
+// Koanf paths of the runtime-level settings.
+const (
+	// shutdownTimeoutKey overrides the runtime-wide shutdown budget, e.g. to
+	// match Kubernetes terminationGracePeriodSeconds.
+	shutdownTimeoutKey = "runtime.shutdown_timeout"
+
+	// drainDelayKey overrides the pause between draining and Shutdown.
+	drainDelayKey = "runtime.drain_delay"
+
+	// slowModuleThresholdKey overrides how long a module step may take before
+	// the runtime warns about it.
+	slowModuleThresholdKey = "runtime.slow_module_threshold"
+
+	// panicPolicyKey overrides what a panic in Start or StartAsync does.
+	panicPolicyKey = "runtime.panic_policy"
+
+	// crashReportDirKey overrides where crash reports are written.
+	crashReportDirKey = "runtime.crash_report_dir"
+)
+
 // Runtime orchestrates module initialization, startup, and shutdown.
 type Runtime struct {
 	modules []Module
+	config  RuntimeConfig
+	hooks   *LifecycleHooks
+	crashes *CrashReports
+
+	// contributors add sections to crash reports; set once RunContext has
+	// sorted the modules.
+	contributors []CrashContributor
+
+	// live is the running module set Attach and Detach change; nil outside
+	// RunContext's start phase.
+	live atomic.Pointer[liveModules]
+
+	// inherited lists the services a Composite's shared modules provide to
+	// this child runtime.
+	inherited []ServiceKey
+
+	// hosted lists the modules of a Composite's children, whose config the
+	// shared SchemaValidator checks along with this runtime's.
+	hosted []Module
+
+	// started and stopping let a Composite order its children around the
+	// shared runtime: started runs once every sync module has been launched,
+	// stopping before the drain begins.
+	started  func(ctx context.Context)
+	stopping func(ctx context.Context)
+
+	// scopes holds each module's own DI scope; set once RunContext has sorted
+	// the modules.
+	scopes *moduleScopes
+
+	// info is the RuntimeInfo of the latest run, for RunAndExit's summary.
+	info atomic.Pointer[RuntimeInfo]
+
+	// configLoad times the ConfigSource load of the latest run, recorded once
+	// its RuntimeInfo exists; zero without a ConfigSource.
+	configLoad TimelineEntry
+
+	// settled is set once the runtime settings are loaded, so slow steps are
+	// warned about as they finish.
+	settled atomic.Bool
+
+	// booted is closed once the run's RuntimeInfo exists or the run ended
+	// without one, releasing WaitReady.
+	booted   chan struct{}
+	bootOnce sync.Once
 }
 
 // NewRuntime creates a runtime with the given modules. Order does not matter —
 // the runtime resolves Init order automatically from Provider/Dependent declarations.
 func NewRuntime(modules ...Module) *Runtime {
+	return NewRuntimeWithOptions(modules)
+}
+
+// NewRuntimeWithOptions creates a runtime with the given modules and runtime
+// options applied over NewDefaultRuntimeConfig.
+func NewRuntimeWithOptions(modules []Module, options ...RuntimeOption) *Runtime {
+	cfg := NewDefaultRuntimeConfig()
+	for _, o := range options {
+		o(&cfg)
+	}
+
 	return &Runtime{
 		modules: modules,
+		config:  cfg,
+		hooks:   &LifecycleHooks{hooks: slices.Clone(cfg.Hooks)},
+		crashes: &CrashReports{dir: cfg.CrashReportDir},
+		booted:  make(chan struct{}),
 	}
 }
 
-// Run starts the runtime, handling SIGTERM/SIGINT for graceful shutdown.
+// Hooks returns the runtime's lifecycle hook registry. It is also provided in
+// DI once RunContext starts.
+func (r *Runtime) Hooks() *LifecycleHooks {
+	return r.hooks
+}
+
+// Run starts the runtime, handling the configured signals (SIGTERM/SIGINT by
+// default) for graceful shutdown.
 func (r *Runtime) Run() error {
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	ctx, stop := signal.NotifyContext(context.Background(), r.config.Signals...)
 	defer stop()
 
 	return r.RunContext(ctx)
@@ -46,39 +133,112 @@ func (r *Runtime) Run() error {
 
 // RunContext initializes, starts, and manages graceful shutdown of all modules.
 // ctx cancellation is the shutdown trigger — callers are responsible for signal handling.
+// A failure is returned as an *ExitError classifying it (see ExitReasonOf); a
+// configured ErrorClassifier maps the returned error.
 func (r *Runtime) RunContext(ctx context.Context) error {
-	sorted, meta, err := sortModules(r.modules)
-	if err != nil {
-		return err
+	err := r.run(ctx)
+	if err != nil && r.config.ErrorClassifier != nil {
+		return r.config.ErrorClassifier(err)
 	}
 
-	// Adopt a ctx-supplied injector (harness/test-slice mocks) instead of
-	// overwriting it; without one, behavior is unchanged (fresh do.New).
-	injector, ok := tryInjector(ctx)
+	return err
+}
+
+// run is RunContext without error classification.
+func (r *Runtime) run(ctx context.Context) error {
+	// An explicit WithRuntimeInjector wins; otherwise adopt a ctx-supplied
+	// injector (harness/test-slice mocks) instead of overwriting it, and only
+	// without either create a fresh do.New.
+	injector, ok := r.config.Injector, r.config.Injector != nil
+	if !ok {
+		injector, ok = tryInjector(ctx)
+	}
 	if !ok {
 		injector = do.New()
-		ctx = WithInjector(ctx, injector)
+	}
+
+	return r.runIn(ctx, injector)
+}
+
+// runIn is run on injector, which a Composite picks for its parent and child
+// runtimes without touching their config.
+func (r *Runtime) runIn(ctx context.Context, injector do.Injector) error {
+	ctx = WithInjector(ctx, injector)
+
+	defer r.markBooted()
+
+	k, err := r.conditionConfig(ctx, injector)
+	if err != nil {
+		return exitError(ExitConfig, nil, err)
+	}
+
+	// Applied before Init when the config is already loaded, so a failing
+	// Init is torn down within runtime.shutdown_timeout.
+	if k != nil {
+		if err := r.loadRuntimeSettings(k); err != nil {
+			slox.Error(ctx, "invalid runtime config", slog.Any("error", err))
+			return exitError(ExitConfig, nil, err)
+		}
+	}
+
+	enabled, skipped := r.enabledModules(ctx, k)
+	r.shareConfigModules()
+
+	sorted, meta, err := sortModules(enabled, r.inherited...)
+	if err != nil {
+		return exitError(ExitConfig, nil, err)
 	}
 
 	// Provided before the Init loop so later-initializing modules (the
 	// actuator) can Invoke[*RuntimeInfo].
-	info := &RuntimeInfo{modules: describeModules(sorted, meta)}
+	info := &RuntimeInfo{modules: append(describeModules(sorted, meta), describeSkipped(skipped)...)}
+	r.info.Store(info)
+	if !r.configLoad.Start.IsZero() {
+		info.record(r.configLoad)
+	}
+	r.markBooted()
 	ProvideValue(ctx, info)
+	ProvideValue(ctx, r.hooks)
+	ProvideValue(ctx, r.crashes)
+	requestProvidersOf(injector)
+
+	r.contributors = nil
+	for _, module := range sorted {
+		if c, ok := module.(CrashContributor); ok {
+			r.contributors = append(r.contributors, c)
+		}
+	}
+
+	r.scopes = newModuleScopes(injector)
+	for order, module := range sorted {
+		r.scopes.create(order, module)
+	}
 
-	initialized, err := r.initModules(ctx, sorted, injector, info)
+	initialized, err := r.initModules(ctx, sorted, meta, injector, info)
 	if err != nil {
 		return err
 	}
 
-	// Wire HotReloadable modules to the ReloadNotifier.
+	// Without a ConfigSource the config only reaches DI during Init.
+	if k == nil {
+		if dk, kErr := do.Invoke[*koanf.Koanf](injector); kErr == nil {
+			if err := r.loadRuntimeSettings(dk); err != nil {
+				slox.Error(ctx, "invalid runtime config", slog.Any("error", err))
+				r.teardown(ctx, initialized, meta, info)
+
+				return exitError(ExitConfig, nil, err)
+			}
+		}
+	}
+
+	for order, module := range sorted {
+		info.setShutdownTimeout(order, effectiveShutdownTimeout(module, r.shutdownBudget()))
+	}
+
+	// Wire reloadable modules to the ReloadNotifier.
 	if notifier, err := do.Invoke[ReloadNotifier](injector); err == nil {
 		for _, module := range initialized {
-			if hr, ok := module.(HotReloadable); ok {
-				notifier.OnReload(hr.OnReload)
-			}
-			if v, ok := module.(ValidatableModule); ok {
-				notifier.OnValidate(v.ValidateReload)
-			}
+			wireReload(ctx, notifier, module, nil)
 		}
 	}
 
@@ -87,7 +247,10 @@ func (r *Runtime) RunContext(ctx context.Context) error {
 	if err != nil || logger == nil {
 		slox.Warn(ctx, "failed to retrieve logger, continuing with default logger", slog.Any("error", err))
 
-		logger = slog.Default()
+		logger = r.config.Logger
+		if logger == nil {
+			logger = slog.Default()
+		}
 		do.Provide(injector, func(_ do.Injector) (*slog.Logger, error) {
 			return logger, nil
 		})
@@ -98,6 +261,38 @@ func (r *Runtime) RunContext(ctx context.Context) error {
 	// Init durations and states are now recorded; render the wiring report.
 	emitWiringReport(ctx, info)
 
+	// The slow threshold is final now; check the steps that already finished.
+	r.settled.Store(true)
+	r.warnSlowBoot(ctx, info)
+
+	// A supervised module that exhausts its restarts cancels ctx with the cause.
+	ctx, escalate := context.WithCancelCause(ctx)
+	defer escalate(nil)
+
+	sup := newSupervisor(ctx, r, info, escalate)
+
+	live := newLiveModules(ctx, injector, info, sup, initialized, meta, r.inherited)
+	r.live.Store(live)
+	defer r.live.Store(nil)
+
+	shutdown := func() error {
+		if r.stopping != nil {
+			r.stopping(ctx)
+		}
+
+		shutdownCtx, cancel := r.shutdownContext(ctx)
+		defer cancel()
+
+		// Attached modules join the boot modules in drain and shutdown.
+		modules, meta := live.close()
+
+		r.drain(shutdownCtx, modules, info)
+		sup.stop(shutdownCtx)
+		live.wait(shutdownCtx)
+
+		return r.shutdown(shutdownCtx, modules, meta, info)
+	}
+
 	// Phase 1: Start async modules (non-blocking setup).
 	asyncPool := pool.New().
 		WithErrors().
@@ -109,14 +304,37 @@ func (r *Runtime) RunContext(ctx context.Context) error {
 
 		switch m := module.(type) {
 		case AsyncModule:
+			if policy, ok := r.supervisionPolicy(m); ok {
+				sup.start(order, m, policy)
+				continue
+			}
+
 			asyncPool.Go(func(ctx context.Context) error {
-				info.setState(order, StateStarted)
+				ctx = r.scopes.context(ctx, order)
+				info.setState(order, StateStarting)
+				r.emit(ctx, info, order, LifecycleEvent{Phase: PhaseBeforeStart, Module: m})
+
+				startedAt := time.Now()
+				contained, err := r.guardStart(ctx, info, order, m, func() error { return m.StartAsync(ctx) })
+				duration := r.recordStep(ctx, info, order, StepStart, startedAt, err)
+				if err == nil {
+					info.setState(order, StateStarted)
+				}
+				r.emit(ctx, info, order, LifecycleEvent{Phase: PhaseAfterStart, Module: m, Duration: duration, Err: err})
+
+				if contained {
+					return nil
+				}
+
+				if err != nil {
+					if ctx.Err() == nil {
+						info.setState(order, StateFailed)
+					}
 
-				if err := safeCall(func() error { return m.StartAsync(ctx) }); err != nil {
 					slox.Error(ctx, "failed starting async module",
 						slog.String("name", name), slog.Any("error", err))
 
-					return oops.With("name", name).Wrapf(err, "failed starting module")
+					return exitError(ExitStartFailed, m, oops.With("name", name).Wrapf(err, "failed starting module"))
 				}
 
 				return nil
@@ -132,25 +350,24 @@ func (r *Runtime) RunContext(ctx context.Context) error {
 	if err := asyncPool.Wait(); err != nil {
 		slox.Error(ctx, "async modules failed", slog.Any("error", err))
 
-		shutdownTimeout, cancel := shutdownContext(ctx)
-		defer cancel()
-
-		if shutdownErr := r.shutdown(shutdownTimeout, initialized, info); shutdownErr != nil {
+		if shutdownErr := shutdown(); shutdownErr != nil {
 			return shutdownErr
 		}
 
 		return oops.Wrapf(err, "async modules failed")
 	}
 
-	// Phase 2: Start sync modules. The first sync module to return (clean OR error)
+	// Phase 2: Start sync modules, each once the modules providing its required
+	// dependencies are ready. The first sync module to return (clean OR error)
 	// records the originating cause and cancels syncCtx; siblings observe the
 	// cancellation and return, then the runtime proceeds to graceful shutdown.
 	syncCtx, cancelSync := context.WithCancel(ctx)
 	defer cancelSync()
 
 	var (
-		firstExit sync.Once
-		firstErr  error
+		firstExit   sync.Once
+		firstErr    error
+		firstModule Module
 	)
 
 	syncPool := pool.New().
@@ -166,18 +383,44 @@ func (r *Runtime) RunContext(ctx context.Context) error {
 			name := fmt.Sprintf("%T", module)
 
 			syncPool.Go(func(ctx context.Context) error {
-				info.setState(order, StateStarted)
+				ctx = r.scopes.context(ctx, order)
+
+				if err := r.awaitProviders(ctx, info, order, meta[order]); err != nil {
+					// The runtime is already stopping: a provider returned or
+					// failed, or ctx ended. The module never starts.
+					slox.Debug(ctx, "not starting sync module",
+						slog.String("name", name), slog.Any("error", err))
+
+					return nil
+				}
+
+				ctx = r.beginSyncStart(ctx, info, order, m)
+				r.emit(ctx, info, order, LifecycleEvent{Phase: PhaseBeforeStart, Module: m})
 
 				if cs, ok := m.(contextSetter); ok {
 					cs.setCtx(ctx)
 				}
 
-				err := safeCall(func() error { return m.Start(ctx) })
+				startedAt := time.Now()
+				contained, err := r.guardStart(ctx, info, order, m, func() error { return m.Start(ctx) })
+				if err != nil && ctx.Err() == nil {
+					// Failed on its own rather than in response to cancellation.
+					info.setState(order, StateFailed)
+				}
+				r.emit(ctx, info, order, LifecycleEvent{Phase: PhaseAfterStart, Module: m, Duration: time.Since(startedAt), Err: err})
+
+				if contained {
+					// The panic policy keeps the runtime running; stay in the
+					// pool so the sync phase does not end without this module.
+					<-ctx.Done()
+					return nil
+				}
 
 				// First to return records the cause and cancels siblings; later
 				// returns (often context.Canceled from this cancellation) are ignored.
 				firstExit.Do(func() {
 					firstErr = err
+					firstModule = m
 					cancelSync()
 				})
 
@@ -185,7 +428,7 @@ func (r *Runtime) RunContext(ctx context.Context) error {
 					slox.Error(ctx, "failed starting sync module",
 						slog.String("name", name), slog.Any("error", err))
 
-					return oops.With("name", name).Wrapf(err, "failed starting module")
+					return exitError(ExitStartFailed, m, oops.With("name", name).Wrapf(err, "failed starting module"))
 				}
 
 				return nil
@@ -193,6 +436,10 @@ func (r *Runtime) RunContext(ctx context.Context) error {
 		}
 	}
 
+	if r.started != nil {
+		r.started(ctx)
+	}
+
 	if hasSyncModules {
 		syncDone := make(chan struct{})
 		go func() {
@@ -210,14 +457,11 @@ func (r *Runtime) RunContext(ctx context.Context) error {
 			if firstErr != nil && ctx.Err() == nil {
 				slox.Error(ctx, "sync module failed", slog.Any("error", firstErr))
 
-				shutdownTimeout, cancel := shutdownContext(ctx)
-				defer cancel()
-
-				if shutdownErr := r.shutdown(shutdownTimeout, initialized, info); shutdownErr != nil {
+				if shutdownErr := shutdown(); shutdownErr != nil {
 					return shutdownErr
 				}
 
-				return oops.Wrapf(firstErr, "sync module failed")
+				return exitError(ExitStartFailed, firstModule, oops.Wrapf(firstErr, "sync module failed"))
 			}
 
 			slox.Info(ctx, "a sync module stopped, shutting down")
@@ -227,69 +471,255 @@ func (r *Runtime) RunContext(ctx context.Context) error {
 		slox.Info(ctx, "shutdown signal received")
 	}
 
-	shutdownTimeout, cancel := shutdownContext(ctx)
-	defer cancel()
+	if cause := context.Cause(ctx); errors.Is(cause, ErrRestartsExhausted) {
+		if shutdownErr := shutdown(); shutdownErr != nil {
+			return shutdownErr
+		}
+
+		return oops.Wrapf(cause, "supervised module failed")
+	}
 
-	return r.shutdown(shutdownTimeout, initialized, info)
+	return shutdown()
 }
 
-// initModules initializes modules sequentially in dependency order, loading
-// config for Configurable modules first and recording state/duration in info.
-// On failure it tears down the already-initialized prefix and returns the
-// wrapped error.
-func (r *Runtime) initModules(ctx context.Context, sorted []Module, injector do.Injector, info *RuntimeInfo) ([]Module, error) {
-	initialized := make([]Module, 0, len(sorted))
+// initModules initializes modules in dependency waves: every module in a wave
+// has all of its dependencies in earlier waves, so a wave's Init calls run
+// concurrently. Config for Configurable modules is loaded first and
+// state/duration/wave recorded in info. On failure the current wave is allowed
+// to finish, then everything initialized so far is torn down and the wrapped
+// error of the earliest failing module (in init order) is returned.
+//
+// The returned slice is indexed by InitOrder.
+func (r *Runtime) initModules(ctx context.Context, sorted []Module, meta []moduleMeta, injector do.Injector, info *RuntimeInfo) ([]Module, error) {
+	initialized := make([]Module, len(sorted))
+
+	for _, orders := range initWaves(meta) {
+		errs := make([]error, len(orders))
+
+		var wg sync.WaitGroup
+		for i, order := range orders {
+			wg.Go(func() {
+				errs[i] = r.initModule(ctx, sorted[order], order, injector, info)
+			})
+		}
+		wg.Wait()
 
-	for order, module := range sorted {
-		name := fmt.Sprintf("%T", module)
+		var firstErr error
+		for i, order := range orders {
+			if errs[i] == nil {
+				initialized[order] = sorted[order]
+				continue
+			}
 
-		if c, ok := module.(Configurable); ok {
-			k, kErr := do.Invoke[*koanf.Koanf](injector)
-			if kErr == nil && k.Exists(c.ConfigPath()) {
-				if err := c.LoadConfig(k); err != nil {
-					slox.Error(ctx, "failed loading config for module", slog.String("name", name), slog.Any("error", err))
-					info.setState(order, StateFailed)
-					r.teardown(ctx, initialized, info)
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+		}
 
-					return nil, oops.
-						With("name", name).
-						Wrapf(err, "failed loading config for module")
-				}
+		if firstErr != nil {
+			r.teardown(ctx, initialized, meta, info)
+			return nil, firstErr
+		}
+	}
+
+	return initialized, nil
+}
+
+// initModule loads config (for Configurable modules) and runs Init for the
+// module at order, recording its state and timed steps in info and emitting
+// the BeforeInit/AfterInit lifecycle events. Init runs in the module's scope.
+// A config failure, including the Init of a ConfigSource, is classified as
+// ExitConfig, any other as ExitInitFailed.
+func (r *Runtime) initModule(ctx context.Context, module Module, order int, injector do.Injector, info *RuntimeInfo) error {
+	ctx = r.scopes.context(ctx, order)
+	name := fmt.Sprintf("%T", module)
+
+	r.emit(ctx, info, order, LifecycleEvent{Phase: PhaseBeforeInit, Module: module})
+
+	if c, ok := module.(Configurable); ok {
+		k, kErr := do.Invoke[*koanf.Koanf](injector)
+		if kErr == nil && k.Exists(c.ConfigPath()) {
+			configStart := time.Now()
+			err := c.LoadConfig(k)
+			duration := r.recordStep(ctx, info, order, StepConfig, configStart, err)
+
+			if err != nil {
+				slox.Error(ctx, "failed loading config for module", slog.String("name", name), slog.Any("error", err))
+				info.setState(order, StateFailed)
+				r.emit(ctx, info, order, LifecycleEvent{Phase: PhaseAfterInit, Module: module, Duration: duration, Err: err})
+
+				return exitError(ExitConfig, module, oops.
+					With("name", name).
+					Wrapf(err, "failed loading config for module"))
 			}
 		}
+	}
+
+	initStart := time.Now()
+	if err := safeCall(func() error { return module.Init(ctx) }); err != nil {
+		duration := r.recordStep(ctx, info, order, StepInit, initStart, err)
+		slox.Error(ctx, "failed initializing module", slog.Any("error", err))
+		info.setState(order, StateFailed)
+		r.reportCrash(ctx, info, order, StepInit, PanicPropagate, err)
+		r.emit(ctx, info, order, LifecycleEvent{Phase: PhaseAfterInit, Module: module, Duration: duration, Err: err})
+
+		reason := ExitInitFailed
+		if _, ok := module.(ConfigSource); ok {
+			reason = ExitConfig
+		}
 
-		initStart := time.Now()
-		if err := safeCall(func() error { return module.Init(ctx) }); err != nil {
-			slox.Error(ctx, "failed initializing module", slog.Any("error", err))
-			info.setState(order, StateFailed)
-			r.teardown(ctx, initialized, info)
+		return exitError(reason, module, oops.
+			With("name", name).
+			Wrapf(err, "failed initializing module"))
+	}
 
-			return nil, oops.
-				With("name", name).
-				Wrapf(err, "failed initializing module")
+	duration := r.recordStep(ctx, info, order, StepInit, initStart, nil)
+	info.setState(order, StateInitialized)
+	r.emit(ctx, info, order, LifecycleEvent{Phase: PhaseAfterInit, Module: module, Duration: duration})
+
+	return nil
+}
+
+// emit fills ev.Info from the module's current RuntimeInfo entry and delivers
+// it to the lifecycle hooks.
+func (r *Runtime) emit(ctx context.Context, info *RuntimeInfo, order int, ev LifecycleEvent) {
+	ev.Info = info.module(order)
+	r.hooks.emit(ctx, ev)
+}
+
+// initWaves groups module orders into Init waves: a module's wave is one past
+// the latest wave of anything in its dependsOn, so each wave only depends on
+// earlier ones. Orders within a wave are ascending.
+func initWaves(meta []moduleMeta) [][]int {
+	waveOf := make([]int, len(meta))
+	var waves [][]int
+
+	for order, m := range meta {
+		wave := 0
+		for _, dep := range m.dependsOn {
+			wave = max(wave, waveOf[dep]+1)
 		}
 
-		info.setInitDuration(order, time.Since(initStart))
-		info.setState(order, StateInitialized)
+		waveOf[order] = wave
+		if wave == len(waves) {
+			waves = append(waves, nil)
+		}
+		waves[wave] = append(waves[wave], order)
+	}
 
-		initialized = append(initialized, module)
+	return waves
+}
+
+// shutdownContext derives a fresh context bounded by the runtime-wide shutdown
+// budget. It preserves ctx values (logger, injector) but is detached from ctx
+// cancellation, which is typically already triggered by the time shutdown runs.
+func (r *Runtime) shutdownContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), r.shutdownBudget())
+}
+
+// shutdownBudget returns the runtime-wide shutdown budget.
+func (r *Runtime) shutdownBudget() time.Duration {
+	if r.config.ShutdownTimeout > 0 {
+		return r.config.ShutdownTimeout
 	}
 
-	return initialized, nil
+	return DefaultShutdownTimeout
+}
+
+// loadRuntimeSettings applies runtime.shutdown_timeout,
+// runtime.slow_module_threshold, runtime.panic_policy, runtime.crash_report_dir
+// and runtime.drain_delay from k, where the keys are set. A shutdown timeout
+// must be positive, a drain delay must not be negative and a panic policy must
+// be known.
+func (r *Runtime) loadRuntimeSettings(k *koanf.Koanf) error {
+	if k.Exists(shutdownTimeoutKey) {
+		timeout, err := configDuration(k, shutdownTimeoutKey)
+		if err != nil || timeout <= 0 {
+			return oops.
+				With("key", shutdownTimeoutKey).
+				With("value", k.Get(shutdownTimeoutKey)).
+				Errorf("%s must be a positive duration", shutdownTimeoutKey)
+		}
+
+		r.config.ShutdownTimeout = timeout
+	}
+
+	if k.Exists(slowModuleThresholdKey) {
+		threshold, err := configDuration(k, slowModuleThresholdKey)
+		if err != nil {
+			return oops.
+				With("key", slowModuleThresholdKey).
+				With("value", k.Get(slowModuleThresholdKey)).
+				Errorf("%s must be a duration", slowModuleThresholdKey)
+		}
+
+		r.config.SlowModuleThreshold = threshold
+	}
+
+	if k.Exists(panicPolicyKey) {
+		var policy PanicPolicy
+		if err := policy.UnmarshalText([]byte(k.String(panicPolicyKey))); err != nil {
+			return oops.
+				With("key", panicPolicyKey).
+				With("value", k.Get(panicPolicyKey)).
+				Wrapf(err, "invalid %s", panicPolicyKey)
+		}
+
+		r.config.PanicPolicy = policy
+	}
+
+	if k.Exists(crashReportDirKey) {
+		r.config.CrashReportDir = k.String(crashReportDirKey)
+		r.crashes.setDir(r.config.CrashReportDir)
+	}
+
+	if k.Exists(drainDelayKey) {
+		delay, err := configDuration(k, drainDelayKey)
+		if err != nil || delay < 0 {
+			return oops.
+				With("key", drainDelayKey).
+				With("value", k.Get(drainDelayKey)).
+				Errorf("%s must be a non-negative duration", drainDelayKey)
+		}
+
+		r.config.DrainDelay = delay
+	}
+
+	return nil
 }
 
-// shutdownContext derives a fresh timeout context for shutdown. It preserves
-// ctx values (logger, injector) but is detached from ctx cancellation, which is
-// typically already triggered by the time shutdown runs.
-func shutdownContext(ctx context.Context) (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.WithoutCancel(ctx), DefaultShutdownTimeout)
+// configDuration reads key as a duration. Unlike koanf's Duration, a string
+// that does not parse is an error rather than zero.
+func configDuration(k *koanf.Koanf, key string) (time.Duration, error) {
+	if s, ok := k.Get(key).(string); ok {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, oops.Wrapf(err, "invalid duration for %s", key)
+		}
+
+		return d, nil
+	}
+
+	return k.Duration(key), nil
+}
+
+// effectiveShutdownTimeout is the Shutdown budget a module actually gets: its
+// own ShutdownTimeout when set and shorter than budget, otherwise budget.
+func effectiveShutdownTimeout(module Module, budget time.Duration) time.Duration {
+	if st, ok := module.(ShutdownTimeouter); ok {
+		if own := st.ShutdownTimeout(); own > 0 && own < budget {
+			return own
+		}
+	}
+
+	return budget
 }
 
 // safeCall runs fn, converting any panic into an error with a stack trace.
 func safeCall(fn func() error) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			err = oops.With("stack", string(debug.Stack())).Errorf("panic: %v", r)
+			err = oops.With("stack", string(debug.Stack())).Errorf("%w: %v", ErrPanic, r)
 		}
 	}()
 	return fn()
@@ -303,7 +733,7 @@ func shutdownModule(ctx context.Context, module Module) error {
 	go func() {
 		defer func() {
 			if r := recover(); r != nil {
-				done <- oops.With("stack", string(debug.Stack())).Errorf("panic during shutdown: %v", r)
+				done <- oops.With("stack", string(debug.Stack())).Errorf("%w during shutdown: %v", ErrPanic, r)
 			}
 		}()
 		done <- module.Shutdown(ctx)
@@ -317,59 +747,157 @@ func shutdownModule(ctx context.Context, module Module) error {
 	}
 }
 
-// teardown shuts down modules in reverse order under a fresh deadline, logging
-// but not returning errors. Used when cleaning up after an Init failure.
-func (r *Runtime) teardown(ctx context.Context, initialized []Module, info *RuntimeInfo) {
-	timeoutCtx, cancel := shutdownContext(ctx)
-	defer cancel()
-
-	for order, module := range slices.Backward(initialized) {
-		name := fmt.Sprintf("%T", module)
+// drain marks info as draining, calls Drain on every initialized Drainable
+// module concurrently, then waits the drain delay, so in-flight traffic moves
+// elsewhere before any listener closes. Drain errors are logged, never fatal;
+// both steps give up when ctx expires.
+func (r *Runtime) drain(ctx context.Context, initialized []Module, info *RuntimeInfo) {
+	info.setDraining()
 
-		if timeoutCtx.Err() != nil {
-			slox.Error(ctx, "shutdown deadline exceeded, skipping module teardown", slog.String("name", name))
+	var wg sync.WaitGroup
+	for _, module := range initialized {
+		d, ok := module.(Drainable)
+		if !ok {
 			continue
 		}
 
-		if err := shutdownModule(timeoutCtx, module); err != nil {
-			slox.Error(ctx, "failed shutting down module", slog.String("name", name), slog.Any("error", err))
-			continue
-		}
+		wg.Go(func() {
+			if err := safeCall(func() error { return d.Drain(ctx) }); err != nil {
+				slox.Warn(ctx, "failed draining module",
+					slog.String("name", fmt.Sprintf("%T", module)), slog.Any("error", err))
+			}
+		})
+	}
 
-		info.setState(order, StateStopped)
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		slox.Warn(ctx, "modules did not finish draining before the shutdown deadline")
+		return
+	}
+
+	if r.config.DrainDelay <= 0 {
+		return
+	}
+
+	slox.Info(ctx, "draining before shutdown", slog.Duration("delay", r.config.DrainDelay))
+
+	select {
+	case <-time.After(r.config.DrainDelay):
+	case <-ctx.Done():
 	}
 }
 
-// shutdown shuts down modules in reverse order, returning the first error.
-// Modules remaining after the deadline expires are logged and skipped.
-// initialized is a prefix of the topo-sorted slice, so its index is the
-// module's InitOrder.
-func (r *Runtime) shutdown(ctx context.Context, initialized []Module, info *RuntimeInfo) error {
-	var firstErr error
+// teardown shuts down initialized modules under a fresh deadline, logging but
+// not returning errors. Used when cleaning up after an Init failure.
+func (r *Runtime) teardown(ctx context.Context, initialized []Module, meta []moduleMeta, info *RuntimeInfo) {
+	timeoutCtx, cancel := r.shutdownContext(ctx)
+	defer cancel()
 
-	for order, module := range slices.Backward(initialized) {
-		name := fmt.Sprintf("%T", module)
+	_ = r.shutdown(timeoutCtx, initialized, meta, info)
+}
 
-		if ctx.Err() != nil {
-			slox.Error(ctx, "shutdown deadline exceeded, skipping module", slog.String("name", name))
-			if firstErr == nil {
-				firstErr = oops.With("name", name).Wrapf(ctx.Err(), "shutdown deadline exceeded")
-			}
+// shutdown stops modules along the reverse dependency DAG: a module's Shutdown
+// runs only once every initialized module depending on it has stopped, and
+// modules nothing depends on stop concurrently. Failed or skipped dependents
+// still release their dependencies. Modules remaining after the deadline
+// expires are logged and skipped. initialized is indexed by InitOrder (nil
+// entries were never initialized) and meta is the parallel sortModules
+// output. Marks info as draining first, so readiness flips before any module
+// stops, and releases the module scopes last, disposing of their private
+// services. Returns the error of the latest-initialized failing module.
+func (r *Runtime) shutdown(ctx context.Context, initialized []Module, meta []moduleMeta, info *RuntimeInfo) error {
+	info.setDraining()
+
+	dependents := make([][]int, len(initialized))
+	for order, m := range meta {
+		for _, dep := range m.dependsOn {
+			dependents[dep] = append(dependents[dep], order)
+		}
+	}
+
+	stopped := make([]chan struct{}, len(initialized))
+	for order := range initialized {
+		stopped[order] = make(chan struct{})
+	}
+
+	errs := make([]error, len(initialized))
+
+	var wg sync.WaitGroup
+	for order, module := range initialized {
+		if module == nil {
+			close(stopped[order])
 			continue
 		}
 
-		if err := shutdownModule(ctx, module); err != nil {
-			slox.Error(ctx, "failed shutting down module", slog.String("name", name), slog.Any("error", err))
-			if firstErr == nil {
-				firstErr = oops.With("name", name).Wrapf(err, "failed shutting down module")
+		wg.Go(func() {
+			defer close(stopped[order])
+
+			for _, dependent := range dependents[order] {
+				<-stopped[dependent]
 			}
-			continue
+
+			errs[order] = r.stopModule(ctx, module, order, info)
+		})
+	}
+	wg.Wait()
+
+	r.scopes.releaseAll(ctx)
+
+	for _, err := range slices.Backward(errs) {
+		if err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
+
+// stopModule shuts down a single module under ctx, further bounded by its own
+// ShutdownTimeout, recording StateStopped on success and emitting the
+// BeforeStop/AfterStop lifecycle events. A module reached after the deadline
+// expired is skipped. Shutdown runs in the module's scope.
+func (r *Runtime) stopModule(ctx context.Context, module Module, order int, info *RuntimeInfo) error {
+	ctx = r.scopes.context(ctx, order)
+	name := fmt.Sprintf("%T", module)
+
+	if ctx.Err() != nil {
+		slox.Error(ctx, "shutdown deadline exceeded, skipping module", slog.String("name", name))
+		err := oops.With("name", name).Wrapf(ctx.Err(), "shutdown deadline exceeded")
+		r.emit(ctx, info, order, LifecycleEvent{Phase: PhaseAfterStop, Module: module, Err: err})
+
+		return exitError(ExitShutdownTimeout, module, err)
+	}
+
+	if st, ok := module.(ShutdownTimeouter); ok && st.ShutdownTimeout() > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, st.ShutdownTimeout())
+		defer cancel()
+	}
 
-		info.setState(order, StateStopped)
+	r.emit(ctx, info, order, LifecycleEvent{Phase: PhaseBeforeStop, Module: module})
+
+	stopStart := time.Now()
+	if err := shutdownModule(ctx, module); err != nil {
+		duration := r.recordStep(ctx, info, order, StepShutdown, stopStart, err)
+		slox.Error(ctx, "failed shutting down module", slog.String("name", name), slog.Any("error", err))
+		r.reportCrash(ctx, info, order, StepShutdown, PanicPropagate, err)
+		r.emit(ctx, info, order, LifecycleEvent{Phase: PhaseAfterStop, Module: module, Duration: duration, Err: err})
+
+		return exitError(stopReason(err), module, oops.With("name", name).Wrapf(err, "failed shutting down module"))
 	}
 
-	return firstErr
+	duration := r.recordStep(ctx, info, order, StepShutdown, stopStart, nil)
+	info.setState(order, StateStopped)
+	r.emit(ctx, info, order, LifecycleEvent{Phase: PhaseAfterStop, Module: module, Duration: duration})
+
+	return nil
 }
 
 // ErrUnmetDependency is the typed sentinel for a module declaring a required
@@ -381,9 +909,25 @@ var ErrUnmetDependency = errors.New("unmet module dependency")
 // graph. Threaded out (indexed parallel to the sorted slice) so describeModules
 // reuses it instead of re-calling Provides()/Dependencies().
 type moduleMeta struct {
-	provides []reflect.Type
-	required []reflect.Type
-	optional []reflect.Type
+	provides []ServiceKey
+	required []ServiceKey
+	optional []ServiceKey
+
+	// dependsOn holds the sorted indices that must finish Init before this
+	// module: its declared provider edges plus the implicit barrier edges of
+	// modules that declare no dependencies.
+	dependsOn []int
+
+	// links holds the declared provider edges into this module, one per
+	// resolved dependency key. Used by Graph.
+	links []moduleLink
+}
+
+// moduleLink is a declared dependency resolved to its providing module.
+type moduleLink struct {
+	from     int // sorted index of the provider once sortModules finishes
+	key      ServiceKey
+	optional bool
 }
 
 // Validate runs the dependency topo-sort only — no do.New, no Init, no side
@@ -391,55 +935,91 @@ type moduleMeta struct {
 // Limitation: undeclared do.MustInvoke calls are invisible here and only
 // surface at Init.
 func (r *Runtime) Validate() error {
-	_, _, err := sortModules(r.modules)
+	_, _, err := sortModules(r.modules, r.inherited...)
 	return err
 }
 
 // describeModules builds the initial []ModuleInfo (State = StatePending) from
-// the sorted modules and their meta, assigning InitOrder = index.
+// the sorted modules and their meta, assigning InitOrder = index and InitWave
+// from initWaves.
 func describeModules(sorted []Module, meta []moduleMeta) []ModuleInfo {
 	infos := make([]ModuleInfo, len(sorted))
 
-	for i, m := range sorted {
-		var name string
-		if nm, ok := m.(NamedModule); ok {
-			name = nm.Name()
+	waveOf := make([]int, len(sorted))
+	for wave, orders := range initWaves(meta) {
+		for _, order := range orders {
+			waveOf[order] = wave
 		}
+	}
 
-		infos[i] = ModuleInfo{
-			Name:      name,
-			Type:      fmt.Sprintf("%T", m),
-			InitOrder: i,
-			Provides:  renderTypes(meta[i].provides),
-			Requires:  renderTypes(meta[i].required),
-			Optional:  renderTypes(meta[i].optional),
-			Lifecycle: lifecycleOf(m),
-			State:     StatePending,
-		}
+	for i, m := range sorted {
+		infos[i] = describeModule(m, meta[i])
+		infos[i].InitOrder = i
+		infos[i].InitWave = waveOf[i]
 	}
 
 	return infos
 }
 
-// sortModules topologically sorts modules based on Provider/Dependent declarations
-// using Kahn's algorithm. Modules with no declared deps preserve their original order.
-// meta[i] holds the declarations derived for sorted[i], so callers avoid a second
-// reflect pass. Unmet required deps satisfy errors.Is(err, ErrUnmetDependency).
-func sortModules(modules []Module) ([]Module, []moduleMeta, error) {
-	// Build type → module index map from Provider declarations
-	typeOwner := make(map[reflect.Type]int)
+// describeSkipped builds the ModuleInfo (State = StateSkipped, InitOrder and
+// InitWave -1) of modules a condition removed before sorting.
+func describeSkipped(skipped []Module) []ModuleInfo {
+	infos := make([]ModuleInfo, len(skipped))
+
+	for i, m := range skipped {
+		infos[i] = describeModule(m, declarations(m))
+		infos[i].InitOrder = -1
+		infos[i].InitWave = -1
+		infos[i].State = StateSkipped
+	}
+
+	return infos
+}
+
+// describeModule builds the pending ModuleInfo of m from its declarations,
+// leaving InitOrder and InitWave to the caller.
+func describeModule(m Module, meta moduleMeta) ModuleInfo {
+	var name string
+	if nm, ok := m.(NamedModule); ok {
+		name = nm.Name()
+	}
+
+	return ModuleInfo{
+		Name:      name,
+		Type:      fmt.Sprintf("%T", m),
+		Provides:  renderKeys(meta.provides),
+		Requires:  renderKeys(meta.required),
+		Optional:  renderKeys(meta.optional),
+		Lifecycle: lifecycleOf(m),
+		State:     StatePending,
+	}
+}
+
+// sortModules topologically sorts modules based on Provider/Dependent and
+// NamedProvider/NamedDependent declarations using Kahn's algorithm. Modules with
+// no declared deps preserve their original order. Required keys in inherited
+// are satisfied from outside the graph, without an edge. meta[i] holds the
+// declarations derived for sorted[i], so callers avoid a second reflect pass.
+// A key declared by more than one module satisfies
+// errors.Is(err, ErrDuplicateProvider), unmet required deps
+// errors.Is(err, ErrUnmetDependency), and cycles errors.Is(err, ErrDependencyCycle).
+func sortModules(modules []Module, inherited ...ServiceKey) ([]Module, []moduleMeta, error) {
 	metaByIdx := make([]moduleMeta, len(modules))
 
 	for i, m := range modules {
-		p, ok := m.(Provider)
-		if !ok {
-			continue
-		}
+		metaByIdx[i] = declarations(m)
+	}
+
+	if dups := duplicateProviders(metaByIdx); len(dups) > 0 {
+		return nil, nil, duplicateProviderError(modules, dups)
+	}
 
-		metaByIdx[i].provides = p.Provides()
+	// Build key → module index map from the provides declarations
+	keyOwner := make(map[ServiceKey]int)
 
-		for _, t := range metaByIdx[i].provides {
-			typeOwner[t] = i
+	for i := range modules {
+		for _, k := range metaByIdx[i].provides {
+			keyOwner[k] = i
 		}
 	}
 
@@ -448,23 +1028,14 @@ func sortModules(modules []Module) ([]Module, []moduleMeta, error) {
 	edges := make([][]int, len(modules))
 	inDegree := make([]int, len(modules))
 
-	for i, m := range modules {
-		d, ok := m.(Dependent)
-		if !ok {
-			continue
-		}
-
-		required, optional := d.Dependencies()
-		metaByIdx[i].required = required
-		metaByIdx[i].optional = optional
-
-		for _, t := range required {
-			ownerIdx, found := typeOwner[t]
+	for i := range modules {
+		for _, k := range metaByIdx[i].required {
+			ownerIdx, found := keyOwner[k]
+			if !found && slices.Contains(inherited, k) {
+				continue
+			}
 			if !found {
-				return nil, nil, oops.
-					With("module", fmt.Sprintf("%T", m)).
-					With("type", t.String()).
-					Wrapf(ErrUnmetDependency, "module %T requires type %v but no module provides it", m, t)
+				return nil, nil, unmetDependencyError(modules, metaByIdx, i, k)
 			}
 
 			if ownerIdx == i {
@@ -473,10 +1044,11 @@ func sortModules(modules []Module) ([]Module, []moduleMeta, error) {
 
 			edges[ownerIdx] = append(edges[ownerIdx], i)
 			inDegree[i]++
+			metaByIdx[i].links = append(metaByIdx[i].links, moduleLink{from: ownerIdx, key: k})
 		}
 
-		for _, t := range optional {
-			ownerIdx, found := typeOwner[t]
+		for _, k := range metaByIdx[i].optional {
+			ownerIdx, found := keyOwner[k]
 			if !found {
 				continue
 			}
@@ -487,6 +1059,7 @@ func sortModules(modules []Module) ([]Module, []moduleMeta, error) {
 
 			edges[ownerIdx] = append(edges[ownerIdx], i)
 			inDegree[i]++
+			metaByIdx[i].links = append(metaByIdx[i].links, moduleLink{from: ownerIdx, key: k, optional: true})
 		}
 	}
 
@@ -501,10 +1074,12 @@ func sortModules(modules []Module) ([]Module, []moduleMeta, error) {
 
 	sorted := make([]Module, 0, len(modules))
 	meta := make([]moduleMeta, 0, len(modules))
+	sortedIdx := make([]int, len(modules))
 
 	for len(queue) > 0 {
 		idx := queue[0]
 		queue = queue[1:]
+		sortedIdx[idx] = len(sorted)
 		sorted = append(sorted, modules[idx])
 		meta = append(meta, metaByIdx[idx])
 
@@ -517,8 +1092,88 @@ func sortModules(modules []Module) ([]Module, []moduleMeta, error) {
 	}
 
 	if len(sorted) != len(modules) {
-		return nil, nil, oops.Errorf("cycle detected in module dependencies")
+		unsorted := make([]bool, len(modules))
+		for i := range modules {
+			unsorted[i] = inDegree[i] > 0
+		}
+
+		return nil, nil, cycleError(modules, metaByIdx, unsorted)
 	}
 
+	for idx, next := range edges {
+		for _, n := range next {
+			meta[sortedIdx[n]].dependsOn = append(meta[sortedIdx[n]].dependsOn, sortedIdx[idx])
+		}
+	}
+
+	for order := range meta {
+		for l := range meta[order].links {
+			meta[order].links[l].from = sortedIdx[meta[order].links[l].from]
+		}
+	}
+
+	linkBarriers(sorted, meta)
+
 	return sorted, meta, nil
 }
+
+// declarations collects m's Provider/Dependent types as unqualified keys,
+// followed by its NamedProvider/NamedDependent keys.
+func declarations(m Module) moduleMeta {
+	var meta moduleMeta
+
+	if p, ok := m.(Provider); ok {
+		meta.provides = unqualifiedKeys(p.Provides())
+	}
+
+	if np, ok := m.(NamedProvider); ok {
+		meta.provides = append(meta.provides, np.NamedProvides()...)
+	}
+
+	if d, ok := m.(Dependent); ok {
+		required, optional := d.Dependencies()
+		meta.required = unqualifiedKeys(required)
+		meta.optional = unqualifiedKeys(optional)
+	}
+
+	if nd, ok := m.(NamedDependent); ok {
+		required, optional := nd.NamedDependencies()
+		meta.required = append(meta.required, required...)
+		meta.optional = append(meta.optional, optional...)
+	}
+
+	return meta
+}
+
+// declaresDependencies reports whether m implements Dependent or NamedDependent.
+func declaresDependencies(m Module) bool {
+	_, ok := m.(Dependent)
+	_, named := m.(NamedDependent)
+	return ok || named
+}
+
+// linkBarriers adds implicit dependsOn edges for modules that do not implement
+// Dependent or NamedDependent. Such a module may Invoke anything without declaring it, so it
+// waits for every module sorted before it, and every module sorted after it
+// waits for it — preserving the sequential behavior undeclared modules have
+// always relied on. Declared modules between barriers stay free to run
+// concurrently.
+func linkBarriers(sorted []Module, meta []moduleMeta) {
+	barrier := -1
+
+	for order, m := range sorted {
+		if declaresDependencies(m) {
+			if barrier >= 0 {
+				meta[order].dependsOn = append(meta[order].dependsOn, barrier)
+			}
+		} else {
+			for prev := max(barrier, 0); prev < order; prev++ {
+				meta[order].dependsOn = append(meta[order].dependsOn, prev)
+			}
+			barrier = order
+		}
+
+		slices.Sort(meta[order].dependsOn)
+		meta[order].dependsOn = slices.Compact(meta[order].dependsOn)
+	}
+}
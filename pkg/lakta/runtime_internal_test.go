@@ -92,9 +92,10 @@ func TestShutdown_DeadlineExceeded_SkipsRemaining(t *testing.T) {
 	defer cancel()
 
 	r := &Runtime{}
-	// Reverse order: blocker shuts down first and runs past the deadline, so the
-	// remaining module is skipped and a deadline error is returned.
-	err := r.shutdown(ctx, []Module{skipped, blocker}, nil)
+	// blocker depends on skipped, so it shuts down first and runs past the
+	// deadline; the remaining module is skipped and a deadline error is returned.
+	meta := []moduleMeta{{}, {dependsOn: []int{0}}}
+	err := r.shutdown(ctx, []Module{skipped, blocker}, meta, nil)
 
 	testza.AssertNotNil(t, err)
 	testza.AssertContains(t, err.Error(), "deadline exceeded")
@@ -224,6 +225,8 @@ func TestRunContext_PopulatesRuntimeInfo(t *testing.T) {
 	testza.AssertEqual(t, 1, snap[1].InitOrder)
 	testza.AssertEqual(t, []string{"*lakta.diType"}, snap[1].Requires)
 	testza.AssertEqual(t, LifecycleSync, snap[1].Lifecycle)
+	testza.AssertEqual(t, 0, snap[0].InitWave)
+	testza.AssertEqual(t, 1, snap[1].InitWave)
 
 	waitForState(t, info, 1, StateStarted)
 
@@ -344,3 +347,93 @@ func TestRunContext_NoInjectorPathUnchanged(t *testing.T) {
 	testza.AssertNotNil(t, m.initErr)
 	testza.AssertNil(t, m.got)
 }
+
+// undeclaredModule implements neither Provider nor Dependent.
+type undeclaredModule struct{}
+
+func (undeclaredModule) Init(context.Context) error     { return nil }
+func (undeclaredModule) Shutdown(context.Context) error { return nil }
+
+func TestSortModules_InitWaves(t *testing.T) {
+	t.Parallel()
+
+	provider := &declModule{provides: []reflect.Type{reflect.TypeFor[*depA]()}}
+	consumer := &declModule{required: []reflect.Type{reflect.TypeFor[*depA]()}}
+	independent := &declModule{}
+	barrier := undeclaredModule{}
+
+	sorted, meta, err := sortModules([]Module{provider, consumer, independent, barrier})
+	testza.AssertNil(t, err)
+
+	// Kahn seeds zero-in-degree modules in original order, then releases consumer.
+	testza.AssertEqual(t, []Module{provider, independent, barrier, consumer}, sorted)
+
+	// provider and independent share wave 0; the undeclared module waits for
+	// both; consumer waits for its provider and the barrier before it.
+	testza.AssertEqual(t, [][]int{{0, 1}, {2}, {3}}, initWaves(meta))
+	testza.AssertEqual(t, []int{0, 2}, meta[3].dependsOn)
+}
+
+func TestInitWaves_UndeclaredModulesStaySequential(t *testing.T) {
+	t.Parallel()
+
+	_, meta, err := sortModules([]Module{undeclaredModule{}, undeclaredModule{}, undeclaredModule{}})
+	testza.AssertNil(t, err)
+
+	testza.AssertEqual(t, [][]int{{0}, {1}, {2}}, initWaves(meta))
+}
+
+type timeoutModule struct {
+	quickModule
+
+	timeout time.Duration
+}
+
+func (m timeoutModule) ShutdownTimeout() time.Duration { return m.timeout }
+
+func TestEffectiveShutdownTimeout(t *testing.T) {
+	t.Parallel()
+
+	budget := 10 * time.Second
+
+	testza.AssertEqual(t, budget, effectiveShutdownTimeout(quickModule{}, budget))
+	testza.AssertEqual(t, time.Second, effectiveShutdownTimeout(timeoutModule{timeout: time.Second}, budget))
+	testza.AssertEqual(t, budget, effectiveShutdownTimeout(timeoutModule{timeout: time.Minute}, budget), "own timeout never extends the budget")
+	testza.AssertEqual(t, budget, effectiveShutdownTimeout(timeoutModule{}, budget), "non-positive own timeout is ignored")
+}
+
+// slowTimedModule blocks in Shutdown but bounds itself with its own timeout.
+type slowTimedModule struct {
+	blockingShutdownModule
+
+	timeout time.Duration
+}
+
+func (m slowTimedModule) ShutdownTimeout() time.Duration { return m.timeout }
+
+func TestShutdown_ModuleTimeoutBoundsOnlyThatModule(t *testing.T) {
+	t.Parallel()
+
+	dependent := slowTimedModule{
+		blockingShutdownModule: blockingShutdownModule{release: make(chan struct{})},
+		timeout:                50 * time.Millisecond,
+	}
+	t.Cleanup(func() { close(dependent.release) })
+
+	provider := &countingShutdownModule{}
+
+	r := &Runtime{}
+	ctx, cancel := r.shutdownContext(context.Background())
+	defer cancel()
+
+	// The dependent overruns its own 50ms window and is abandoned; its provider
+	// still gets shut down within the (much longer) runtime budget.
+	start := time.Now()
+	meta := []moduleMeta{{}, {dependsOn: []int{0}}}
+	err := r.shutdown(ctx, []Module{provider, dependent}, meta, nil)
+
+	testza.AssertNotNil(t, err)
+	testza.AssertContains(t, err.Error(), "deadline exceeded")
+	testza.AssertEqual(t, int32(1), provider.calls.Load())
+	testza.AssertTrue(t, time.Since(start) < time.Second)
+}
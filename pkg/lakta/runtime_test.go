@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"testing"
@@ -689,3 +690,403 @@ func TestRun_SIGTERMTriggersGracefulShutdown(t *testing.T) {
 		t.Fatal("Run did not return after SIGTERM")
 	}
 }
+
+func TestRuntime_InitIndependentModulesConcurrently(t *testing.T) {
+	t.Parallel()
+
+	// Both modules block in Init until the other has entered it too, which only
+	// completes if they share an Init wave.
+	var entered sync.WaitGroup
+	entered.Add(2)
+
+	rendezvous := func(context.Context) error {
+		entered.Done()
+
+		done := make(chan struct{})
+		go func() { entered.Wait(); close(done) }()
+
+		select {
+		case <-done:
+			return nil
+		case <-time.After(2 * time.Second):
+			return errors.New("independent modules did not init concurrently")
+		}
+	}
+
+	m1 := testkit.NewMockProviderModule()
+	m1.OnInit = rendezvous
+	m2 := testkit.NewMockProviderModule()
+	m2.OnInit = rendezvous
+
+	rh := testkit.NewRuntimeHarness(t, m1, m2)
+	testza.AssertNil(t, rh.Shutdown())
+}
+
+func TestRuntime_InitWaveFailureTearsDownSiblings(t *testing.T) {
+	t.Parallel()
+
+	typeA := reflect.TypeFor[*depTypeA]()
+
+	failer := testkit.NewMockProviderModule()
+	failer.ProvidesTypes = []reflect.Type{typeA}
+	failer.InitErr = errors.New("wave boom")
+
+	sibling := testkit.NewMockProviderModule()
+
+	consumer := testkit.NewMockProviderModule()
+	consumer.RequiredDeps = []reflect.Type{typeA}
+
+	rh := testkit.NewRuntimeHarness(t, failer, sibling, consumer)
+	err := rh.Shutdown()
+
+	testza.AssertNotNil(t, err)
+	testza.AssertContains(t, err.Error(), "wave boom")
+	testza.AssertEqual(t, int32(1), sibling.InitCalls.Load())
+	testza.AssertEqual(t, int32(1), sibling.ShutdownCalls.Load())
+	testza.AssertEqual(t, int32(0), failer.ShutdownCalls.Load())
+	testza.AssertEqual(t, int32(0), consumer.InitCalls.Load())
+}
+
+func TestRuntime_ShutdownIndependentModulesConcurrently(t *testing.T) {
+	t.Parallel()
+
+	// Both modules block in Shutdown until the other has entered it too, which
+	// only completes if neither waits for the other.
+	var entered sync.WaitGroup
+	entered.Add(2)
+
+	rendezvous := func(context.Context) error {
+		entered.Done()
+
+		done := make(chan struct{})
+		go func() { entered.Wait(); close(done) }()
+
+		select {
+		case <-done:
+			return nil
+		case <-time.After(2 * time.Second):
+			return errors.New("independent modules did not shut down concurrently")
+		}
+	}
+
+	m1 := testkit.NewMockProviderModule()
+	m1.OnShutdown = rendezvous
+	m2 := testkit.NewMockProviderModule()
+	m2.OnShutdown = rendezvous
+
+	rh := testkit.NewRuntimeHarness(t, m1, m2)
+	testza.AssertNil(t, rh.Shutdown())
+}
+
+func TestRuntime_ShutdownWaitsForDependents(t *testing.T) {
+	t.Parallel()
+
+	var counter atomic.Int64
+	var shutProvider, shutConsumer, shutIndependent int64
+
+	typeA := reflect.TypeFor[*depTypeA]()
+
+	provider := testkit.NewMockProviderModule()
+	provider.ProvidesTypes = []reflect.Type{typeA}
+	provider.OnShutdown = func(context.Context) error { shutProvider = counter.Add(1); return nil }
+
+	// A slow dependent holds its provider back, but not an unrelated module.
+	consumer := testkit.NewMockProviderModule()
+	consumer.RequiredDeps = []reflect.Type{typeA}
+	consumer.OnShutdown = func(context.Context) error {
+		time.Sleep(50 * time.Millisecond)
+		shutConsumer = counter.Add(1)
+		return nil
+	}
+
+	independent := testkit.NewMockProviderModule()
+	independent.OnShutdown = func(context.Context) error { shutIndependent = counter.Add(1); return nil }
+
+	rh := testkit.NewRuntimeHarness(t, provider, consumer, independent)
+	testza.AssertNil(t, rh.Shutdown())
+
+	testza.AssertTrue(t, shutConsumer < shutProvider)
+	testza.AssertTrue(t, shutIndependent < shutConsumer)
+}
+
+type timedShutdownModule struct {
+	*testkit.MockModule
+
+	timeout time.Duration
+}
+
+func (m timedShutdownModule) ShutdownTimeout() time.Duration { return m.timeout }
+
+func TestRunContext_ShutdownTimeoutFromConfig(t *testing.T) {
+	t.Parallel()
+
+	k := koanf.New(".")
+	_ = k.Set("runtime.shutdown_timeout", "25s")
+
+	plain := testkit.NewMockModule()
+	timed := timedShutdownModule{MockModule: testkit.NewMockModule(), timeout: 5 * time.Second}
+
+	injector := do.New()
+	ctx, cancel := context.WithCancel(lakta.WithInjector(context.Background(), injector))
+	defer cancel()
+
+	inited := make(chan struct{})
+	plain.OnInit = func(context.Context) error { close(inited); return nil }
+
+	done := make(chan error, 1)
+	go func() { done <- lakta.NewRuntime(koanfProviderModule(k), timed, plain).RunContext(ctx) }()
+
+	info := waitForRuntimeInfo(t, injector, inited)
+
+	cancel()
+	testza.AssertNil(t, <-done)
+
+	snap := info.Snapshot()
+	testza.AssertEqual(t, 25*time.Second, snap[0].ShutdownTimeout)
+	testza.AssertEqual(t, 5*time.Second, snap[1].ShutdownTimeout)
+	testza.AssertEqual(t, 25*time.Second, snap[2].ShutdownTimeout)
+}
+
+func TestRunContext_InvalidShutdownTimeout(t *testing.T) {
+	t.Parallel()
+
+	k := koanf.New(".")
+	_ = k.Set("runtime.shutdown_timeout", "soon")
+
+	m := testkit.NewMockModule()
+
+	rh := testkit.NewRuntimeHarness(t, koanfProviderModule(k), m)
+	err := rh.Shutdown()
+
+	testza.AssertNotNil(t, err)
+	testza.AssertContains(t, err.Error(), "runtime.shutdown_timeout")
+	testza.AssertEqual(t, int32(1), m.ShutdownCalls.Load())
+}
+
+// koanfSource is a ConfigSource serving k.
+type koanfSource struct {
+	testkit.MockModule
+
+	k *koanf.Koanf
+}
+
+func (s *koanfSource) LoadConfigSource(context.Context) (*koanf.Koanf, error) { return s.k, nil }
+
+func TestRunContext_InitFailureUsesConfiguredShutdownTimeout(t *testing.T) {
+	t.Parallel()
+
+	k := koanf.New(".")
+	_ = k.Set("runtime.shutdown_timeout", "50ms")
+
+	stuck := testkit.NewMockModule()
+	stuck.OnShutdown = func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	failing := testkit.NewMockModule()
+	failing.InitErr = errors.New("init boom")
+
+	// The teardown of a failed Init already runs on the configured budget,
+	// not DefaultShutdownTimeout.
+	start := time.Now()
+	err := lakta.NewRuntime(&koanfSource{k: k}, stuck, failing).RunContext(lakta.WithInjector(context.Background(), do.New()))
+
+	testza.AssertNotNil(t, err)
+	testza.AssertEqual(t, int32(1), stuck.ShutdownCalls.Load())
+	testza.AssertTrue(t, time.Since(start) < 5*time.Second)
+}
+
+// waitForRuntimeInfo waits for inited to close, then resolves *RuntimeInfo.
+func waitForRuntimeInfo(t *testing.T, injector do.Injector, inited <-chan struct{}) *lakta.RuntimeInfo {
+	t.Helper()
+
+	select {
+	case <-inited:
+	case <-time.After(2 * time.Second):
+		t.Fatal("module never initialized")
+	}
+
+	info, err := do.Invoke[*lakta.RuntimeInfo](injector)
+	testza.AssertNil(t, err)
+
+	return info
+}
+
+func TestRunContext_ReadinessAndLivenessFollowModuleStates(t *testing.T) {
+	t.Parallel()
+
+	injector := do.New()
+	ctx, cancel := context.WithCancel(lakta.WithInjector(context.Background(), injector))
+	defer cancel()
+
+	var (
+		atInit     error
+		atStart    error
+		atShutdown error
+	)
+
+	server := testkit.NewMockSyncModule()
+	server.BlockStart = make(chan struct{})
+	server.OnInit = func(ctx context.Context) error {
+		info, err := lakta.Invoke[*lakta.RuntimeInfo](ctx)
+		if err != nil {
+			return err
+		}
+		atInit = info.Readiness()
+		return nil
+	}
+
+	started := make(chan struct{})
+	probe := testkit.NewMockAsyncModule()
+	probe.OnStartAsync = func(context.Context) error {
+		close(started)
+		return nil
+	}
+	probe.OnShutdown = func(ctx context.Context) error {
+		info, err := lakta.Invoke[*lakta.RuntimeInfo](ctx)
+		if err != nil {
+			return err
+		}
+		atShutdown = info.Readiness()
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- lakta.NewRuntime(server, probe).RunContext(ctx) }()
+
+	info := waitForRuntimeInfo(t, injector, started)
+	waitFor(t, func() bool { return info.Readiness() == nil })
+	atStart = info.Readiness()
+	testza.AssertNil(t, info.Liveness())
+
+	cancel()
+	testza.AssertNil(t, <-done)
+
+	testza.AssertErrorIs(t, atInit, lakta.ErrNotReady)
+	testza.AssertNil(t, atStart)
+	testza.AssertErrorIs(t, atShutdown, lakta.ErrDraining)
+}
+
+func TestRunContext_StartFailureFailsLiveness(t *testing.T) {
+	t.Parallel()
+
+	injector := do.New()
+	ctx := lakta.WithInjector(context.Background(), injector)
+
+	failer := testkit.NewMockSyncModule()
+	failer.StartErr = errors.New("start boom")
+
+	testza.AssertNotNil(t, lakta.NewRuntime(failer).RunContext(ctx))
+
+	info, err := do.Invoke[*lakta.RuntimeInfo](injector)
+	testza.AssertNil(t, err)
+	testza.AssertErrorIs(t, info.Liveness(), lakta.ErrModuleFailed)
+}
+
+type drainableModule struct {
+	*testkit.MockSyncModule
+
+	drainedAt atomic.Pointer[time.Time]
+	readiness atomic.Pointer[error]
+}
+
+func (m *drainableModule) Drain(ctx context.Context) error {
+	now := time.Now()
+	m.drainedAt.Store(&now)
+
+	info, err := lakta.Invoke[*lakta.RuntimeInfo](ctx)
+	if err != nil {
+		return err
+	}
+	readiness := info.Readiness()
+	m.readiness.Store(&readiness)
+
+	return nil
+}
+
+func newDrainableModule() *drainableModule {
+	m := &drainableModule{MockSyncModule: testkit.NewMockSyncModule()}
+	m.BlockStart = make(chan struct{})
+	return m
+}
+
+func TestRunContext_DrainsBeforeShutdown(t *testing.T) {
+	t.Parallel()
+
+	const delay = 50 * time.Millisecond
+
+	m := newDrainableModule()
+
+	var shutdownAt time.Time
+	m.OnShutdown = func(context.Context) error {
+		shutdownAt = time.Now()
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(lakta.WithInjector(context.Background(), do.New()))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- lakta.NewRuntimeWithOptions([]lakta.Module{m}, lakta.WithDrainDelay(delay)).RunContext(ctx)
+	}()
+
+	waitFor(t, func() bool { return m.StartCalls.Load() == 1 })
+	cancel()
+	testza.AssertNil(t, <-done)
+
+	drainedAt := m.drainedAt.Load()
+	testza.AssertNotNil(t, drainedAt)
+	testza.AssertErrorIs(t, *m.readiness.Load(), lakta.ErrDraining)
+	testza.AssertTrue(t, shutdownAt.Sub(*drainedAt) >= delay)
+}
+
+func TestRunContext_DrainDelayFromConfig(t *testing.T) {
+	t.Parallel()
+
+	k := koanf.New(".")
+	_ = k.Set("runtime.drain_delay", "40ms")
+
+	m := newDrainableModule()
+
+	var shutdownAt time.Time
+	m.OnShutdown = func(context.Context) error {
+		shutdownAt = time.Now()
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() { done <- lakta.NewRuntime(koanfProviderModule(k), m).RunContext(ctx) }()
+
+	waitFor(t, func() bool { return m.StartCalls.Load() == 1 })
+	cancel()
+	testza.AssertNil(t, <-done)
+
+	testza.AssertTrue(t, shutdownAt.Sub(*m.drainedAt.Load()) >= 40*time.Millisecond)
+}
+
+func TestRunContext_InvalidDrainDelay(t *testing.T) {
+	t.Parallel()
+
+	k := koanf.New(".")
+	_ = k.Set("runtime.drain_delay", "-1s")
+
+	rh := testkit.NewRuntimeHarness(t, koanfProviderModule(k), testkit.NewMockModule())
+	err := rh.Shutdown()
+
+	testza.AssertNotNil(t, err)
+	testza.AssertContains(t, err.Error(), "runtime.drain_delay")
+}
+
+func TestRunContext_InitFailureSkipsDrain(t *testing.T) {
+	t.Parallel()
+
+	m := newDrainableModule()
+	failer := testkit.NewMockModule()
+	failer.InitErr = errors.New("init boom")
+
+	testza.AssertNotNil(t, lakta.NewRuntime(m, failer).RunContext(context.Background()))
+	testza.AssertEqual(t, int32(1), m.ShutdownCalls.Load())
+	testza.AssertNil(t, m.drainedAt.Load())
+}
@@ -0,0 +1,96 @@
+package lakta
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/Vilsol/slox"
+	"github.com/samber/do/v2"
+)
+
+// moduleScopes holds the child do.Scope each module runs in, keyed by
+// InitOrder. A module's context carries its scope as the injector and the
+// runtime injector as the shared one, so Invoke resolves the module's private
+// services before the shared ones, ProvidePrivate registers privately and
+// Provide publishes to every module.
+type moduleScopes struct {
+	mu     sync.Mutex
+	parent do.Injector
+	scopes map[int]do.Injector
+}
+
+func newModuleScopes(parent do.Injector) *moduleScopes {
+	return &moduleScopes{parent: parent, scopes: make(map[int]do.Injector)}
+}
+
+// create opens the scope of the module at order, named after the module and
+// its order so it stays unique across attach and detach.
+func (s *moduleScopes) create(order int, module Module) {
+	name := fmt.Sprintf("%s#%d", moduleLabel(module), order)
+	for n := 2; ; n++ {
+		if _, taken := s.parent.ChildByName(name); !taken {
+			break
+		}
+		name = fmt.Sprintf("%s#%d.%d", moduleLabel(module), order, n)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scopes[order] = s.parent.Scope(name)
+}
+
+// context returns ctx running in the scope of the module at order. ctx is
+// returned as is on a nil receiver or for an order without a scope.
+func (s *moduleScopes) context(ctx context.Context, order int) context.Context {
+	if s == nil {
+		return ctx
+	}
+
+	s.mu.Lock()
+	scope, ok := s.scopes[order]
+	s.mu.Unlock()
+
+	if !ok {
+		return ctx
+	}
+
+	return withModuleScope(ctx, scope)
+}
+
+// release shuts down the scope of the module at order, disposing of its
+// private services, and forgets it.
+func (s *moduleScopes) release(ctx context.Context, order int) {
+	s.mu.Lock()
+	scope, ok := s.scopes[order]
+	delete(s.scopes, order)
+	s.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	if report := scope.ShutdownWithContext(ctx); !report.Succeed {
+		slox.Warn(ctx, "failed shutting down module scope",
+			slog.String("scope", scope.Name()), slog.Any("error", report))
+	}
+}
+
+// releaseAll releases every scope still open, the latest order first, once
+// their modules have stopped.
+func (s *moduleScopes) releaseAll(ctx context.Context) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	orders := slices.Sorted(maps.Keys(s.scopes))
+	s.mu.Unlock()
+
+	for _, order := range slices.Backward(orders) {
+		s.release(ctx, order)
+	}
+}
@@ -0,0 +1,160 @@
+package lakta
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/samber/do/v2"
+)
+
+// privateService is a module-private service recording its disposal.
+type privateService struct {
+	closed *atomic.Int32
+}
+
+func (s *privateService) Shutdown() { s.closed.Add(1) }
+
+// privateModule registers *privateService privately and, when public is set,
+// *diType publicly, and checks it still resolves its private service in Start.
+type privateModule struct {
+	public bool
+
+	closed       atomic.Int32
+	startPrivate atomic.Bool
+}
+
+func (m *privateModule) Init(ctx context.Context) error {
+	ProvidePrivateValue(ctx, &privateService{closed: &m.closed})
+	if m.public {
+		ProvideValue(ctx, &diType{val: "public"})
+	}
+	return nil
+}
+
+func (*privateModule) Shutdown(context.Context) error { return nil }
+
+func (m *privateModule) StartAsync(ctx context.Context) error {
+	_, err := Invoke[*privateService](ctx)
+	m.startPrivate.Store(err == nil)
+	return nil
+}
+
+func (m *privateModule) Provides() []reflect.Type {
+	if m.public {
+		return []reflect.Type{reflect.TypeFor[*diType]()}
+	}
+	return nil
+}
+
+// peekModule records what it resolves from a privateModule.
+type peekModule struct {
+	publicErr  error
+	privateErr error
+}
+
+func (m *peekModule) Init(ctx context.Context) error {
+	_, m.publicErr = Invoke[*diType](ctx)
+	_, m.privateErr = Invoke[*privateService](ctx)
+	return nil
+}
+
+func (*peekModule) Shutdown(context.Context) error { return nil }
+
+func (*peekModule) Dependencies() ([]reflect.Type, []reflect.Type) {
+	return []reflect.Type{reflect.TypeFor[*diType]()}, nil
+}
+
+func TestRunContext_ModuleScopesKeepProvidersPrivate(t *testing.T) {
+	t.Parallel()
+
+	owner := &privateModule{public: true}
+	peek := &peekModule{}
+
+	injector := do.New()
+	ctx, cancel := context.WithCancel(WithInjector(context.Background(), injector))
+	cancel()
+
+	testza.AssertNil(t, NewRuntime(peek, owner).RunContext(ctx))
+
+	testza.AssertTrue(t, owner.startPrivate.Load(), "a module resolves its own private services")
+	testza.AssertNil(t, peek.publicErr)
+	testza.AssertNotNil(t, peek.privateErr, "private services must not leak to other modules")
+
+	_, err := do.Invoke[*privateService](injector)
+	testza.AssertNotNil(t, err)
+	_, err = do.Invoke[*diType](injector)
+	testza.AssertNil(t, err)
+}
+
+func TestDetach_ShutsDownModuleScope(t *testing.T) {
+	t.Parallel()
+
+	r, done, cancel := runningRuntime(t, do.New())
+
+	m := &privateModule{}
+	testza.AssertNil(t, r.Attach(t.Context(), m))
+
+	_, err := do.Invoke[*privateService](r.scopes.scopes[2])
+	testza.AssertNil(t, err)
+
+	testza.AssertNil(t, r.Detach(t.Context(), m))
+	testza.AssertEqual(t, int32(1), m.closed.Load())
+
+	cancel()
+	testza.AssertNil(t, <-done)
+}
+
+// recordedService is a private service recording its disposal by name.
+type recordedService struct {
+	name     string
+	recorder *stopRecorder
+}
+
+func (s *recordedService) Shutdown() { s.recorder.record(s.name) }
+
+// lazyPrivateModule registers *recordedService with ProvidePrivate and
+// resolves it in Start.
+type lazyPrivateModule struct {
+	NamedBase
+
+	recorder *stopRecorder
+}
+
+func (m *lazyPrivateModule) Init(ctx context.Context) error {
+	ProvidePrivate(ctx, func(do.Injector) (*recordedService, error) {
+		return &recordedService{name: m.Name(), recorder: m.recorder}, nil
+	})
+	return nil
+}
+
+func (*lazyPrivateModule) Shutdown(context.Context) error { return nil }
+
+func (*lazyPrivateModule) StartAsync(ctx context.Context) error {
+	_, err := Invoke[*recordedService](ctx)
+	return err
+}
+
+func TestRunContext_ShutsDownModuleScopes(t *testing.T) {
+	t.Parallel()
+
+	recorder := &stopRecorder{}
+	first := &lazyPrivateModule{NamedBase: NewNamedBase("first"), recorder: recorder}
+	second := &lazyPrivateModule{NamedBase: NewNamedBase("second"), recorder: recorder}
+
+	ctx, cancel := context.WithCancel(WithInjector(t.Context(), do.New()))
+	r := NewRuntime(first, second, &infoProviderModule{}, &infoSyncModule{started: make(chan struct{})})
+	done := make(chan error, 1)
+	go func() { done <- r.RunContext(ctx) }()
+
+	testza.AssertNil(t, r.WaitReady(t.Context()))
+	testza.AssertLen(t, recorder.stopped(), 0)
+
+	cancel()
+	testza.AssertNil(t, <-done)
+
+	testza.AssertEqual(t, []string{"second", "first"}, recorder.stopped())
+	testza.AssertLen(t, r.scopes.scopes, 0)
+}
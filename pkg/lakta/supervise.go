@@ -0,0 +1,233 @@
+package lakta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Vilsol/slox"
+	"github.com/samber/oops"
+)
+
+// Supervision defaults applied when a SupervisionPolicy leaves them unset.
+const (
+	DefaultRestartBackoff    = time.Second
+	DefaultMaxRestartBackoff = 30 * time.Second
+)
+
+// ErrRestartsExhausted is wrapped by the error RunContext returns when a
+// supervised module fails after using up its restart budget.
+var ErrRestartsExhausted = errors.New("restart budget exhausted")
+
+// RestartPolicy selects when a supervised AsyncModule's StartAsync is called again.
+type RestartPolicy int
+
+const (
+	RestartNever     RestartPolicy = iota // unsupervised: an error cancels the runtime (default)
+	RestartOnFailure                      // restart after StartAsync returns an error
+	RestartAlways                         // restart after every return, clean or not
+)
+
+func (p RestartPolicy) String() string {
+	switch p {
+	case RestartNever:
+		return "never"
+	case RestartOnFailure:
+		return "on-failure"
+	case RestartAlways:
+		return "always"
+	default:
+		return "unknown"
+	}
+}
+
+// SupervisionPolicy configures restarts for a supervised AsyncModule.
+type SupervisionPolicy struct {
+	// Restart selects when StartAsync is called again.
+	Restart RestartPolicy
+
+	// MaxRestarts bounds the number of restarts; once used up, the next
+	// failure is escalated and shuts the runtime down. Zero means unlimited.
+	MaxRestarts int
+
+	// Backoff is the delay before the first restart, doubling on each
+	// subsequent one. Defaults to DefaultRestartBackoff.
+	Backoff time.Duration
+
+	// MaxBackoff caps the doubling delay. Defaults to DefaultMaxRestartBackoff.
+	MaxBackoff time.Duration
+}
+
+// Supervised lets an AsyncModule opt in to restarts. A policy set through
+// WithSupervision takes precedence. Ignored on modules that are not AsyncModule.
+type Supervised interface {
+	SupervisionPolicy() SupervisionPolicy
+}
+
+// backoff returns the delay before the given restart (1-based).
+func (p SupervisionPolicy) backoff(restart int) time.Duration {
+	delay := p.Backoff
+	if delay <= 0 {
+		delay = DefaultRestartBackoff
+	}
+
+	limit := p.MaxBackoff
+	if limit <= 0 {
+		limit = DefaultMaxRestartBackoff
+	}
+
+	for range restart - 1 {
+		if delay >= limit/2 {
+			return limit
+		}
+		delay *= 2
+	}
+
+	return min(delay, limit)
+}
+
+// supervisionPolicy resolves module's policy: WithSupervision first, then the
+// Supervised interface. ok is false for unsupervised modules.
+func (r *Runtime) supervisionPolicy(module Module) (SupervisionPolicy, bool) {
+	policy := r.declaredSupervision(module)
+	return policy, policy.Restart != RestartNever
+}
+
+// declaredSupervision returns module's policy from WithSupervision or the
+// Supervised interface, the zero policy when it has none. Its backoff also
+// paces PanicRestart.
+func (r *Runtime) declaredSupervision(module Module) SupervisionPolicy {
+	// Matched with sameModule, never hashed: a module value may not be
+	// comparable.
+	for _, s := range slices.Backward(r.config.Supervision) {
+		if sameModule(s.Module, module) {
+			return s.Policy
+		}
+	}
+
+	if s, ok := module.(Supervised); ok {
+		return s.SupervisionPolicy()
+	}
+
+	return SupervisionPolicy{}
+}
+
+// supervisor runs supervised AsyncModules outside the async pool, so their
+// StartAsync may block for the module's lifetime. Exhausting a restart budget
+// cancels the runtime context with a cause wrapping ErrRestartsExhausted.
+type supervisor struct {
+	runtime  *Runtime
+	info     *RuntimeInfo
+	escalate context.CancelCauseFunc
+
+	ctx    context.Context //nolint:containedctx
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newSupervisor(ctx context.Context, r *Runtime, info *RuntimeInfo, escalate context.CancelCauseFunc) *supervisor {
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &supervisor{
+		runtime:  r,
+		info:     info,
+		escalate: escalate,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// start supervises m at order under policy until stop is called.
+func (s *supervisor) start(order int, m AsyncModule, policy SupervisionPolicy) {
+	s.wg.Go(func() {
+		s.run(s.ctx, order, m, policy)
+	})
+}
+
+func (s *supervisor) run(ctx context.Context, order int, m AsyncModule, policy SupervisionPolicy) {
+	ctx = s.runtime.scopes.context(ctx, order)
+	name := fmt.Sprintf("%T", m)
+
+	for restarts := 0; ; {
+		s.info.setState(order, StateStarted)
+		s.runtime.emit(ctx, s.info, order, LifecycleEvent{Phase: PhaseBeforeStart, Module: m})
+
+		startedAt := time.Now()
+		err := safeCall(func() error { return m.StartAsync(ctx) })
+
+		// A panic is a failure like any other, restarted while restarts last,
+		// unless PanicMarkFailed takes the module out of supervision.
+		contained := errors.Is(err, ErrPanic) && s.runtime.config.PanicPolicy == PanicMarkFailed
+		switch {
+		case contained:
+			s.runtime.reportCrash(ctx, s.info, order, StepStart, PanicMarkFailed, err)
+			s.info.setLastError(order, err)
+			s.info.setState(order, StateFailed)
+		case policy.MaxRestarts > 0 && restarts >= policy.MaxRestarts:
+			s.runtime.reportCrash(ctx, s.info, order, StepStart, PanicPropagate, err)
+		default:
+			s.runtime.reportCrash(ctx, s.info, order, StepStart, PanicRestart, err)
+		}
+
+		s.runtime.emit(ctx, s.info, order, LifecycleEvent{Phase: PhaseAfterStart, Module: m, Duration: time.Since(startedAt), Err: err})
+
+		if contained || ctx.Err() != nil || (err == nil && policy.Restart != RestartAlways) {
+			return
+		}
+
+		if err != nil {
+			s.info.setLastError(order, err)
+		}
+
+		if policy.MaxRestarts > 0 && restarts >= policy.MaxRestarts {
+			if err != nil {
+				s.info.setState(order, StateFailed)
+				slox.Error(ctx, "supervised module exhausted its restarts",
+					slog.String("name", name), slog.Int("restarts", restarts), slog.Any("error", err))
+
+				s.escalate(exitError(ExitStartFailed, m, oops.
+					With("name", name).
+					With("restarts", restarts).
+					Wrapf(errors.Join(ErrRestartsExhausted, err), "failed after %d restarts", restarts)))
+			}
+
+			return
+		}
+
+		restarts++
+		s.info.setRestarts(order, restarts)
+
+		delay := policy.backoff(restarts)
+		slox.Warn(ctx, "restarting supervised module",
+			slog.String("name", name), slog.Int("restart", restarts),
+			slog.Duration("backoff", delay), slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// stop cancels every supervised StartAsync and waits for them to return,
+// giving up when ctx expires.
+func (s *supervisor) stop(ctx context.Context) {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		slox.Warn(ctx, "supervised modules did not return before the shutdown deadline")
+	}
+}
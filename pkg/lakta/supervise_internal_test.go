@@ -0,0 +1,31 @@
+package lakta
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+func TestRestartPolicy_String(t *testing.T) {
+	t.Parallel()
+
+	testza.AssertEqual(t, "never", RestartNever.String())
+	testza.AssertEqual(t, "on-failure", RestartOnFailure.String())
+	testza.AssertEqual(t, "always", RestartAlways.String())
+}
+
+func TestSupervisionPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+
+	p := SupervisionPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	testza.AssertEqual(t, 100*time.Millisecond, p.backoff(1))
+	testza.AssertEqual(t, 200*time.Millisecond, p.backoff(2))
+	testza.AssertEqual(t, 800*time.Millisecond, p.backoff(4))
+	testza.AssertEqual(t, time.Second, p.backoff(5))
+	testza.AssertEqual(t, time.Second, p.backoff(1000))
+
+	defaults := SupervisionPolicy{}
+	testza.AssertEqual(t, DefaultRestartBackoff, defaults.backoff(1))
+	testza.AssertEqual(t, DefaultMaxRestartBackoff, defaults.backoff(100))
+}
@@ -0,0 +1,160 @@
+package lakta_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/Vilsol/lakta/pkg/lakta"
+	"github.com/Vilsol/lakta/pkg/testkit"
+	"github.com/samber/do/v2"
+)
+
+type supervisedModule struct {
+	*testkit.MockAsyncModule
+
+	policy lakta.SupervisionPolicy
+}
+
+func (m supervisedModule) SupervisionPolicy() lakta.SupervisionPolicy { return m.policy }
+
+// taggedAsyncModule is an AsyncModule value whose type is not comparable.
+type taggedAsyncModule struct {
+	*testkit.MockAsyncModule
+
+	tags []string
+}
+
+func TestSupervised_RestartsOnFailureUntilStarted(t *testing.T) {
+	t.Parallel()
+
+	mock := testkit.NewMockAsyncModule()
+	mock.OnStartAsync = func(context.Context) error {
+		if mock.StartAsyncCalls.Load() < 3 {
+			return errors.New("broker unavailable")
+		}
+		return nil
+	}
+	m := supervisedModule{MockAsyncModule: mock, policy: lakta.SupervisionPolicy{
+		Restart:     lakta.RestartOnFailure,
+		MaxRestarts: 5,
+		Backoff:     time.Millisecond,
+	}}
+
+	injector := do.New()
+	ctx, cancel := context.WithCancel(lakta.WithInjector(context.Background(), injector))
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- lakta.NewRuntime(m).RunContext(ctx) }()
+
+	waitFor(t, func() bool { return mock.StartAsyncCalls.Load() == 3 })
+	time.Sleep(20 * time.Millisecond) // no further restarts after a clean return
+
+	cancel()
+	testza.AssertNil(t, <-done)
+
+	info, err := do.Invoke[*lakta.RuntimeInfo](injector)
+	testza.AssertNil(t, err)
+
+	snap := info.Snapshot()
+	testza.AssertEqual(t, int32(3), mock.StartAsyncCalls.Load())
+	testza.AssertEqual(t, 2, snap[0].Restarts)
+	testza.AssertEqual(t, "broker unavailable", snap[0].LastError)
+	testza.AssertEqual(t, lakta.StateStopped, snap[0].State)
+}
+
+func TestSupervised_EscalatesWhenRestartsExhausted(t *testing.T) {
+	t.Parallel()
+
+	mock := testkit.NewMockAsyncModule()
+	mock.StartAsyncErr = errors.New("consumer crashed")
+	m := supervisedModule{MockAsyncModule: mock, policy: lakta.SupervisionPolicy{
+		Restart:     lakta.RestartOnFailure,
+		MaxRestarts: 2,
+		Backoff:     time.Millisecond,
+	}}
+
+	server := testkit.NewMockSyncModule()
+	server.BlockStart = make(chan struct{}) // blocks until the escalation cancels it
+
+	injector := do.New()
+	ctx := lakta.WithInjector(context.Background(), injector)
+
+	err := lakta.NewRuntime(m, server).RunContext(ctx)
+	testza.AssertErrorIs(t, err, lakta.ErrRestartsExhausted)
+	testza.AssertContains(t, err.Error(), "consumer crashed")
+
+	testza.AssertEqual(t, int32(3), mock.StartAsyncCalls.Load())
+	testza.AssertEqual(t, int32(1), mock.ShutdownCalls.Load())
+	testza.AssertEqual(t, int32(1), server.ShutdownCalls.Load())
+
+	info, infoErr := do.Invoke[*lakta.RuntimeInfo](injector)
+	testza.AssertNil(t, infoErr)
+
+	snap := info.Snapshot()
+	testza.AssertEqual(t, 2, snap[0].Restarts)
+	testza.AssertEqual(t, lakta.StateFailed, snap[0].State)
+	testza.AssertErrorIs(t, info.Liveness(), lakta.ErrModuleFailed)
+}
+
+func TestSupervised_AlwaysRestartsCleanReturnsViaOption(t *testing.T) {
+	t.Parallel()
+
+	m := testkit.NewMockAsyncModule()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{m}, lakta.WithSupervision(m, lakta.SupervisionPolicy{
+		Restart: lakta.RestartAlways,
+		Backoff: time.Millisecond,
+	}))
+
+	done := make(chan error, 1)
+	go func() { done <- rt.RunContext(ctx) }()
+
+	waitFor(t, func() bool { return m.StartAsyncCalls.Load() >= 3 })
+
+	cancel()
+	testza.AssertNil(t, <-done)
+}
+
+func TestSupervision_NonComparableModule(t *testing.T) {
+	t.Parallel()
+
+	m := taggedAsyncModule{MockAsyncModule: testkit.NewMockAsyncModule(), tags: []string{"a"}}
+	other := testkit.NewMockAsyncModule()
+	server := testkit.NewMockSyncModule() // returns immediately, stopping the runtime
+
+	// The value is never hashed, and matches no policy: it runs once.
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{m, other, server},
+		lakta.WithSupervision(other, lakta.SupervisionPolicy{Restart: lakta.RestartOnFailure}),
+		lakta.WithSupervision(taggedAsyncModule{tags: []string{"a"}}, lakta.SupervisionPolicy{Restart: lakta.RestartAlways}))
+
+	testza.AssertNil(t, rt.RunContext(context.Background()))
+	testza.AssertEqual(t, int32(1), m.StartAsyncCalls.Load())
+}
+
+func TestSupervised_BlockingStartAsyncDoesNotDelaySyncModules(t *testing.T) {
+	t.Parallel()
+
+	var cancelled atomic.Bool
+
+	mock := testkit.NewMockAsyncModule()
+	mock.OnStartAsync = func(ctx context.Context) error {
+		<-ctx.Done() // runs for the module's lifetime
+		cancelled.Store(true)
+		return nil
+	}
+	m := supervisedModule{MockAsyncModule: mock, policy: lakta.SupervisionPolicy{Restart: lakta.RestartOnFailure}}
+
+	server := testkit.NewMockSyncModule() // returns immediately, stopping the runtime
+
+	testza.AssertNil(t, lakta.NewRuntime(m, server).RunContext(context.Background()))
+	testza.AssertEqual(t, int32(1), server.StartCalls.Load())
+	testza.AssertTrue(t, cancelled.Load())
+}
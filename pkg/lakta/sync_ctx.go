@@ -22,6 +22,10 @@ type SyncCtx struct {
 // RuntimeCtx returns the runtime context injected before Start is called.
 func (s *SyncCtx) RuntimeCtx() context.Context { return s.ctx }
 
+// MarkReady calls MarkReady with the runtime context, for a ReadyNotifier to
+// report readiness from Start. A no-op before Start or outside a runtime.
+func (s *SyncCtx) MarkReady() { MarkReady(s.ctx) }
+
 // contextSetter is detected by the runtime to inject ctx before Start.
 type contextSetter interface {
 	setCtx(ctx context.Context)
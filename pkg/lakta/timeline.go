@@ -0,0 +1,140 @@
+package lakta
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Vilsol/slox"
+)
+
+// DefaultSlowModuleThreshold is how long a module's config load, Init, start
+// or Shutdown may take before the runtime warns about it, used when
+// neither WithSlowModuleThreshold nor runtime.slow_module_threshold set one.
+const DefaultSlowModuleThreshold = 5 * time.Second
+
+// TimelineStep names one timed step of the runtime timeline.
+type TimelineStep string
+
+const (
+	StepConfigSource TimelineStep = "config_source" // the runtime loading its ConfigSource, before the graph is sorted
+	StepConfig       TimelineStep = "config"        // LoadConfig of a Configurable module
+	StepInit         TimelineStep = "init"          // Init
+	StepStart        TimelineStep = "start"         // StartAsync of an unsupervised AsyncModule, or a ReadyNotifier's Start until ready
+	StepShutdown     TimelineStep = "shutdown"      // Shutdown
+)
+
+// TimelineEntry is one finished step of the runtime timeline.
+type TimelineEntry struct {
+	Order    int    // InitOrder of the module; -1 for a runtime-level step
+	Module   string // "Type (Name)" of the module; "" for a runtime-level step
+	Step     TimelineStep
+	Start    time.Time
+	Duration time.Duration
+	Err      string // the step's error, "" if it succeeded
+}
+
+// Timeline returns the steps finished so far in the order they finished: the
+// ConfigSource load, then every module's config load, Init, start and
+// Shutdown. Entries are only ever appended, so a consumer may remember how
+// many it has seen. Safe for concurrent use.
+func (ri *RuntimeInfo) Timeline() []TimelineEntry {
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+
+	out := make([]TimelineEntry, len(ri.timeline))
+	copy(out, ri.timeline)
+
+	return out
+}
+
+// record appends entry to the timeline, first filling in the module's label
+// and setting the matching per-phase duration of the module at entry.Order.
+// Nil receiver is a no-op.
+func (ri *RuntimeInfo) record(entry TimelineEntry) TimelineEntry {
+	if ri == nil {
+		return entry
+	}
+
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+
+	if entry.Order >= 0 && entry.Order < len(ri.modules) {
+		m := &ri.modules[entry.Order]
+		entry.Module = m.displayName()
+
+		switch entry.Step {
+		case StepConfig:
+			m.ConfigDuration = entry.Duration
+		case StepInit:
+			m.InitDuration = entry.Duration
+		case StepStart:
+			m.StartDuration = entry.Duration
+		case StepShutdown:
+			m.ShutdownDuration = entry.Duration
+		case StepConfigSource:
+		}
+	}
+
+	ri.timeline = append(ri.timeline, entry)
+
+	return entry
+}
+
+// recordStep records the step of the module at order (-1 for the runtime
+// itself) that began at start and just finished with err, and warns when it
+// was slow. Config and Init steps are only warned about live once the runtime
+// settings are loaded; earlier ones are checked by warnSlowBoot.
+func (r *Runtime) recordStep(ctx context.Context, info *RuntimeInfo, order int, step TimelineStep, start time.Time, err error) time.Duration {
+	entry := TimelineEntry{Order: order, Step: step, Start: start, Duration: time.Since(start)}
+	if err != nil {
+		entry.Err = err.Error()
+	}
+
+	entry = info.record(entry)
+
+	if step == StepStart || step == StepShutdown || r.settled.Load() {
+		r.warnSlow(ctx, entry)
+	}
+
+	return entry.Duration
+}
+
+// warnSlowBoot warns about the config and Init steps recorded before the
+// runtime settings, and with them the slow threshold, were loaded.
+func (r *Runtime) warnSlowBoot(ctx context.Context, info *RuntimeInfo) {
+	for _, entry := range info.Timeline() {
+		r.warnSlow(ctx, entry)
+	}
+}
+
+// warnSlow logs a warning when entry took longer than the slow threshold.
+func (r *Runtime) warnSlow(ctx context.Context, entry TimelineEntry) {
+	threshold := r.slowThreshold()
+	if threshold <= 0 || entry.Duration <= threshold {
+		return
+	}
+
+	attrs := []any{
+		slog.String("step", string(entry.Step)),
+		slog.Duration("duration", entry.Duration),
+		slog.Duration("threshold", threshold),
+	}
+	if entry.Module != "" {
+		attrs = append(attrs, slog.String("module", entry.Module))
+	}
+
+	slox.Warn(ctx, "slow lifecycle step", attrs...)
+}
+
+// slowThreshold returns the effective slow threshold; zero disables warnings.
+func (r *Runtime) slowThreshold() time.Duration {
+	switch {
+	case r.config.SlowModuleThreshold < 0:
+		return 0
+	case r.config.SlowModuleThreshold == 0:
+		return DefaultSlowModuleThreshold
+	default:
+		return r.config.SlowModuleThreshold
+	}
+}
@@ -0,0 +1,155 @@
+package lakta_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/Vilsol/lakta/pkg/lakta"
+	"github.com/Vilsol/lakta/pkg/testkit"
+	"github.com/knadh/koanf/v2"
+	"github.com/samber/do/v2"
+)
+
+// lockedBuffer is a log sink safe for the runtime's concurrent goroutines.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestRuntimeInfo_Timeline(t *testing.T) {
+	t.Parallel()
+
+	k := koanf.New(".")
+	_ = k.Set("test", "value")
+
+	configurable := &configurableMock{counter: &atomic.Int64{}}
+
+	started := make(chan struct{})
+	async := testkit.NewMockAsyncModule()
+	async.OnStartAsync = func(context.Context) error { close(started); return nil }
+
+	injector := do.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rt := lakta.NewRuntimeWithOptions([]lakta.Module{koanfProviderModule(k), configurable, async},
+		lakta.WithRuntimeInjector(injector))
+
+	done := make(chan error, 1)
+	go func() { done <- rt.RunContext(ctx) }()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("async module never started")
+	}
+
+	cancel()
+	testza.AssertNil(t, <-done)
+
+	info, err := do.Invoke[*lakta.RuntimeInfo](injector)
+	testza.AssertNil(t, err)
+
+	steps := make(map[int][]lakta.TimelineStep)
+	durations := make(map[int]map[lakta.TimelineStep]time.Duration)
+	for _, entry := range info.Timeline() {
+		testza.AssertFalse(t, entry.Start.IsZero())
+		testza.AssertNotEqual(t, "", entry.Module)
+
+		steps[entry.Order] = append(steps[entry.Order], entry.Step)
+		if durations[entry.Order] == nil {
+			durations[entry.Order] = make(map[lakta.TimelineStep]time.Duration)
+		}
+		durations[entry.Order][entry.Step] = entry.Duration
+	}
+
+	testza.AssertEqual(t, []lakta.TimelineStep{lakta.StepInit, lakta.StepShutdown}, steps[0])
+	testza.AssertEqual(t, []lakta.TimelineStep{lakta.StepConfig, lakta.StepInit, lakta.StepShutdown}, steps[1])
+	testza.AssertEqual(t, []lakta.TimelineStep{lakta.StepInit, lakta.StepStart, lakta.StepShutdown}, steps[2])
+
+	snap := info.Snapshot()
+	testza.AssertEqual(t, durations[1][lakta.StepConfig], snap[1].ConfigDuration)
+	testza.AssertEqual(t, durations[1][lakta.StepInit], snap[1].InitDuration)
+	testza.AssertEqual(t, durations[2][lakta.StepStart], snap[2].StartDuration)
+	testza.AssertEqual(t, durations[2][lakta.StepShutdown], snap[2].ShutdownDuration)
+}
+
+func TestRunContext_SlowModuleWarning(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		config  string
+		options []lakta.RuntimeOption
+		warn    bool
+	}{
+		{name: "option", options: []lakta.RuntimeOption{lakta.WithSlowModuleThreshold(time.Millisecond)}, warn: true},
+		{name: "config", config: "1ms", warn: true},
+		{name: "config overrides option", config: "1h", options: []lakta.RuntimeOption{lakta.WithSlowModuleThreshold(time.Millisecond)}},
+		{name: "negative disables", options: []lakta.RuntimeOption{lakta.WithSlowModuleThreshold(-1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			k := koanf.New(".")
+			if tt.config != "" {
+				_ = k.Set("runtime.slow_module_threshold", tt.config)
+			}
+
+			slow := testkit.NewMockModule()
+			slow.OnInit = func(context.Context) error {
+				time.Sleep(20 * time.Millisecond)
+				return nil
+			}
+
+			var logs lockedBuffer
+			options := append([]lakta.RuntimeOption{
+				lakta.WithFallbackLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+			}, tt.options...)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			rt := lakta.NewRuntimeWithOptions([]lakta.Module{koanfProviderModule(k), slow}, options...)
+			testza.AssertNil(t, rt.RunContext(ctx))
+
+			warned := strings.Contains(logs.String(), "slow lifecycle step") &&
+				strings.Contains(logs.String(), "step=init")
+			testza.AssertEqual(t, tt.warn, warned)
+		})
+	}
+}
+
+func TestRunContext_InvalidSlowModuleThreshold(t *testing.T) {
+	t.Parallel()
+
+	k := koanf.New(".")
+	_ = k.Set("runtime.slow_module_threshold", "slow")
+
+	rh := testkit.NewRuntimeHarness(t, koanfProviderModule(k), testkit.NewMockModule())
+	err := rh.Shutdown()
+
+	testza.AssertNotNil(t, err)
+	testza.AssertContains(t, err.Error(), "runtime.slow_module_threshold")
+}
@@ -70,6 +70,11 @@ func (m *Module) LoadConfig(k *koanf.Koanf) error {
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
 // Init creates the slog.Logger with stack rewriting, level filtering, and registers it in DI.
 func (m *Module) Init(ctx context.Context) error {
 	injector := lakta.GetInjector(ctx)
@@ -37,6 +37,11 @@ func (m *Module) LoadConfig(k *koanf.Koanf) error {
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
 // Init loads configuration, creates the tint handler, and registers it in DI.
 func (m *Module) Init(ctx context.Context) error {
 	m.handler = m.config.NewHandler()
@@ -73,6 +73,9 @@ type Config struct {
 	// Signals lists which telemetry signals to enable: "traces", "metrics", "logs".
 	Signals []string `koanf:"signals"`
 
+	// LifecycleTrace exports the runtime timeline as a trace: one span per module config load, Init, StartAsync and Shutdown.
+	LifecycleTrace bool `koanf:"lifecycle_trace"`
+
 	// SetupFn overrides the default OTLP SDK setup. Useful for testing or custom exporters.
 	SetupFn func(ctx context.Context, serviceName string) (func(context.Context) error, error) `koanf:"-"`
 
@@ -101,6 +104,7 @@ func NewDefaultConfig() Config {
 		Enabled:         true,
 		Required:        false,
 		Signals:         []string{signalTraces, signalMetrics, signalLogs},
+		LifecycleTrace:  true,
 		Propagators:     []propagation.TextMapPropagator{propagation.TraceContext{}, propagation.Baggage{}},
 	}
 }
@@ -193,6 +197,11 @@ func WithSignals(signals ...string) Option {
 	return func(m *Config) { m.Signals = signals }
 }
 
+// WithLifecycleTrace enables or disables exporting the runtime timeline as a trace.
+func WithLifecycleTrace(enabled bool) Option {
+	return func(m *Config) { m.LifecycleTrace = enabled }
+}
+
 // WithSetupFn overrides the default OTLP SDK setup function.
 func WithSetupFn(fn func(ctx context.Context, serviceName string) (func(context.Context) error, error)) Option {
 	return func(m *Config) { m.SetupFn = fn }
@@ -0,0 +1,151 @@
+package otel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Vilsol/lakta/pkg/lakta"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const lifecycleTracerName = "github.com/Vilsol/lakta/pkg/otel"
+
+// lifecycleTracer exports the runtime timeline as spans: every startup step
+// (config source, config load, Init, start) under one lakta.startup
+// span, every Shutdown under one lakta.shutdown span. Steps finished before
+// the module's Init are backfilled with their recorded timestamps.
+type lifecycleTracer struct {
+	tracer oteltrace.Tracer
+	info   *lakta.RuntimeInfo
+
+	mu          sync.Mutex
+	done        bool // set by end; later events are not exported
+	exported    int  // timeline entries already exported
+	startup     oteltrace.Span
+	startupCtx  context.Context //nolint:containedctx // parent of the startup step spans
+	startupEnd  time.Time       // when the runtime became ready; zero until then
+	shutdown    oteltrace.Span
+	shutdownCtx context.Context //nolint:containedctx // parent of the shutdown step spans
+}
+
+// traceLifecycle starts exporting the runtime timeline through tp and
+// subscribes to lifecycle events to keep exporting it. Returns nil when ctx
+// carries no runtime (Init called outside RunContext).
+func traceLifecycle(ctx context.Context, tp oteltrace.TracerProvider) *lifecycleTracer {
+	info, err := lakta.Invoke[*lakta.RuntimeInfo](ctx)
+	if err != nil {
+		return nil
+	}
+
+	hooks, err := lakta.Invoke[*lakta.LifecycleHooks](ctx)
+	if err != nil {
+		return nil
+	}
+
+	t := &lifecycleTracer{
+		tracer: tp.Tracer(lifecycleTracerName),
+		info:   info,
+	}
+
+	start := time.Now()
+	if timeline := info.Timeline(); len(timeline) > 0 && timeline[0].Start.Before(start) {
+		start = timeline[0].Start
+	}
+
+	t.startupCtx, t.startup = t.tracer.Start(context.Background(), "lakta.startup",
+		oteltrace.WithTimestamp(start))
+
+	t.sync(false)
+	hooks.On(func(_ context.Context, ev lakta.LifecycleEvent) {
+		t.sync(ev.Phase == lakta.PhaseAfterInit || ev.Phase == lakta.PhaseAfterStart || ev.Phase == lakta.PhaseReady)
+	})
+
+	return t
+}
+
+// sync exports the timeline entries finished since the last call. With
+// checkReady, sent once a step finished, it ends the startup span if the
+// runtime is ready.
+func (t *lifecycleTracer) sync(checkReady bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.done {
+		return
+	}
+
+	timeline := t.info.Timeline()
+	for _, entry := range timeline[t.exported:] {
+		t.export(entry)
+	}
+	t.exported = len(timeline)
+
+	if checkReady && t.startupEnd.IsZero() && t.info.Readiness() == nil {
+		t.startupEnd = time.Now()
+		t.startup.End(oteltrace.WithTimestamp(t.startupEnd))
+	}
+}
+
+// export records entry as a span under its phase's root span. A startup step
+// beginning after the runtime became ready, such as the Init of an attached
+// module, becomes a root span of its own.
+func (t *lifecycleTracer) export(entry lakta.TimelineEntry) {
+	parent := context.Background()
+
+	switch {
+	case entry.Step == lakta.StepShutdown:
+		if t.shutdown == nil {
+			t.shutdownCtx, t.shutdown = t.tracer.Start(context.Background(), "lakta.shutdown",
+				oteltrace.WithTimestamp(entry.Start))
+		}
+		parent = t.shutdownCtx
+	case t.startupEnd.IsZero() || entry.Start.Before(t.startupEnd):
+		parent = t.startupCtx
+	}
+
+	name := string(entry.Step)
+	attrs := []attribute.KeyValue{attribute.String("lakta.step", name)}
+	if entry.Module != "" {
+		name += " " + entry.Module
+		attrs = append(attrs,
+			attribute.String("lakta.module", entry.Module),
+			attribute.Int("lakta.init_order", entry.Order))
+	}
+
+	_, span := t.tracer.Start(parent, name,
+		oteltrace.WithTimestamp(entry.Start),
+		oteltrace.WithAttributes(attrs...))
+
+	if entry.Err != "" {
+		span.SetStatus(codes.Error, entry.Err)
+	}
+
+	span.End(oteltrace.WithTimestamp(entry.Start.Add(entry.Duration)))
+}
+
+// end exports the remaining timeline and ends the root spans, ahead of the
+// provider flush. Nil receiver is a no-op.
+func (t *lifecycleTracer) end() {
+	if t == nil {
+		return
+	}
+
+	t.sync(false)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.done = true
+
+	if t.startupEnd.IsZero() {
+		t.startup.End()
+	}
+
+	if t.shutdown != nil {
+		t.shutdown.End()
+		t.shutdown = nil
+	}
+}
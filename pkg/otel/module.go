@@ -26,6 +26,7 @@ type Module struct {
 
 	config    Config
 	providers otelProviders
+	lifecycle *lifecycleTracer
 }
 
 // NewModule creates a new OTEL module
@@ -47,6 +48,11 @@ func (m *Module) LoadConfig(k *koanf.Koanf) error {
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
 // Config returns the current module configuration.
 func (m *Module) Config() Config {
 	return m.config
@@ -67,6 +73,9 @@ func (m *Module) Init(ctx context.Context) error {
 		}
 		m.providers.shutdown = shutdown
 		provideNoopProviders(ctx)
+		if m.config.LifecycleTrace {
+			m.lifecycle = traceLifecycle(ctx, otel.GetTracerProvider())
+		}
 		return nil
 	}
 
@@ -78,6 +87,9 @@ func (m *Module) Init(ctx context.Context) error {
 
 	if m.providers.tracerProvider != nil {
 		lakta.ProvideValue[oteltrace.TracerProvider](ctx, m.providers.tracerProvider)
+		if m.config.LifecycleTrace {
+			m.lifecycle = traceLifecycle(ctx, m.providers.tracerProvider)
+		}
 	} else {
 		lakta.ProvideValue[oteltrace.TracerProvider](ctx, nooptrace.NewTracerProvider())
 	}
@@ -140,7 +152,8 @@ func (m *Module) Dependencies() ([]reflect.Type, []reflect.Type) {
 	}
 }
 
-// Shutdown gracefully stops the OTEL exporters.
+// Shutdown ends the lifecycle trace and gracefully stops the OTEL exporters.
 func (m *Module) Shutdown(ctx context.Context) error {
+	m.lifecycle.end()
 	return m.providers.shutdown(ctx)
 }
@@ -15,6 +15,8 @@ import (
 	"github.com/samber/do/v2"
 	otellog "go.opentelemetry.io/otel/log"
 	otelmetric "go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
@@ -146,6 +148,7 @@ func TestOtelModule_DefaultConfig(t *testing.T) {
 	testza.AssertEqual(t, 60*time.Second, cfg.MetricInterval)
 	testza.AssertEqual(t, time.Second, cfg.RuntimeInterval)
 	testza.AssertEqual(t, true, cfg.Enabled)
+	testza.AssertEqual(t, true, cfg.LifecycleTrace)
 	testza.AssertEqual(t, []string{signalTraces, "metrics", signalLogs}, cfg.Signals)
 	testza.AssertEqual(t, 2, len(cfg.Propagators))
 }
@@ -167,6 +170,7 @@ func TestOtelModule_WithOptions(t *testing.T) {
 		otel.WithRuntimeInterval(2*time.Second),
 		otel.WithEnabled(false),
 		otel.WithSignals(signalTraces),
+		otel.WithLifecycleTrace(false),
 	)
 
 	testza.AssertEqual(t, "svc", cfg.ServiceName)
@@ -182,6 +186,7 @@ func TestOtelModule_WithOptions(t *testing.T) {
 	testza.AssertEqual(t, 2*time.Second, cfg.RuntimeInterval)
 	testza.AssertEqual(t, false, cfg.Enabled)
 	testza.AssertEqual(t, []string{signalTraces}, cfg.Signals)
+	testza.AssertEqual(t, false, cfg.LifecycleTrace)
 }
 
 func TestOtelModule_KoanfLoad(t *testing.T) {
@@ -220,3 +225,54 @@ func TestOtelModule_KoanfLoad(t *testing.T) {
 	testza.AssertEqual(t, 5*time.Second, m.Config().RuntimeInterval)
 	testza.AssertEqual(t, []string{signalTraces, signalLogs}, m.Config().Signals)
 }
+
+// keptExporter is an in-memory exporter that keeps its spans past Shutdown.
+type keptExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func (keptExporter) Shutdown(context.Context) error { return nil }
+
+func TestOtelModule_LifecycleTrace(t *testing.T) {
+	t.Parallel()
+
+	exporter := keptExporter{tracetest.NewInMemoryExporter()}
+	m := otel.NewModule(otel.WithSignals(signalTraces), otel.WithTraceExporter(exporter))
+
+	h := testkit.NewRuntimeHarness(t, m, testkit.NewMockAsyncModule())
+	testza.AssertNil(t, h.Shutdown())
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range exporter.GetSpans().Snapshots() {
+		spans[span.Name()] = span
+	}
+
+	startup, ok := spans["lakta.startup"]
+	testza.AssertTrue(t, ok)
+	shutdown, ok := spans["lakta.shutdown"]
+	testza.AssertTrue(t, ok)
+
+	for name, root := range map[string]sdktrace.ReadOnlySpan{
+		"init *otel.Module (default)":       startup,
+		"init *testkit.MockAsyncModule":     startup,
+		"start *testkit.MockAsyncModule":    startup,
+		"shutdown *testkit.MockAsyncModule": shutdown,
+	} {
+		span, ok := spans[name]
+		testza.AssertTrue(t, ok, name)
+		if ok {
+			testza.AssertEqual(t, root.SpanContext().SpanID(), span.Parent().SpanID(), name)
+		}
+	}
+}
+
+func TestOtelModule_LifecycleTraceDisabled(t *testing.T) {
+	t.Parallel()
+
+	exporter := keptExporter{tracetest.NewInMemoryExporter()}
+	m := otel.NewModule(otel.WithSignals(signalTraces), otel.WithTraceExporter(exporter), otel.WithLifecycleTrace(false))
+
+	h := testkit.NewRuntimeHarness(t, m)
+	testza.AssertNil(t, h.Shutdown())
+	testza.AssertEqual(t, 0, len(exporter.GetSpans()))
+}
@@ -37,6 +37,11 @@ func (m *Module) LoadConfig(k *koanf.Koanf) error {
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
 // Init builds the policy executors and provides the [Registry] to the injector.
 func (m *Module) Init(ctx context.Context) error {
 	executors := make(map[string]failsafe.Executor[any], len(m.config.CodePolicies)+len(m.config.Policies))
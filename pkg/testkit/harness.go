@@ -3,6 +3,7 @@ package testkit
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
 
 	"github.com/Vilsol/lakta/pkg/config"
@@ -81,9 +82,11 @@ type MapProvider map[string]any
 func (m MapProvider) ReadBytes() ([]byte, error)    { return nil, errors.New("not supported") }
 func (m MapProvider) Read() (map[string]any, error) { return map[string]any(m), nil }
 
-// ReloadNotifier is a test double for config.ReloadNotifier.
+// ReloadNotifier is a test double for config.ReloadNotifier that also runs
+// two-phase reloads like the config module.
 type ReloadNotifier struct {
 	callbacks []func(*koanf.Koanf)
+	prepares  []func(*koanf.Koanf) (lakta.PreparedReload, error)
 }
 
 // OnReload implements config.ReloadNotifier.
@@ -94,9 +97,41 @@ func (r *ReloadNotifier) OnReload(fn func(*koanf.Koanf)) {
 // OnValidate implements config.ReloadNotifier (no-op for the test double).
 func (r *ReloadNotifier) OnValidate(_ func(*koanf.Koanf) error) {}
 
-// FireReload invokes all registered callbacks with the given koanf instance.
+// OnPrepare implements lakta.TransactionalReloadNotifier.
+func (r *ReloadNotifier) OnPrepare(_ string, fn func(*koanf.Koanf) (lakta.PreparedReload, error)) {
+	r.prepares = append(r.prepares, fn)
+}
+
+// FireReload reloads with the given koanf instance, ignoring a failed prepare.
 func (r *ReloadNotifier) FireReload(k *koanf.Koanf) {
+	_ = r.Reload(k)
+}
+
+// Reload runs every registered prepare phase with k, then commits them all and
+// invokes the callbacks. If a prepare fails, those already prepared are
+// aborted, nothing else runs and its error is returned.
+func (r *ReloadNotifier) Reload(k *koanf.Koanf) error {
+	prepared := make([]lakta.PreparedReload, 0, len(r.prepares))
+	for _, prepare := range r.prepares {
+		reload, err := prepare(k)
+		if err != nil {
+			for _, p := range slices.Backward(prepared) {
+				if p.Abort != nil {
+					p.Abort()
+				}
+			}
+			return err
+		}
+		prepared = append(prepared, reload)
+	}
+
+	for _, p := range prepared {
+		if p.Commit != nil {
+			p.Commit()
+		}
+	}
 	for _, fn := range r.callbacks {
 		fn(k)
 	}
+	return nil
 }
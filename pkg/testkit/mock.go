@@ -57,6 +57,9 @@ type MockSyncModule struct {
 	OnStart    func(ctx context.Context) error
 	// BlockStart blocks Start until the channel is closed or ctx is cancelled.
 	BlockStart chan struct{}
+	// NotifyReady makes the mock a lakta.ReadyNotifier that marks itself ready
+	// once BlockStart is released, before OnStart runs.
+	NotifyReady bool
 }
 
 // NewMockSyncModule creates a new MockSyncModule.
@@ -73,12 +76,18 @@ func (m *MockSyncModule) Start(ctx context.Context) error {
 			return fmt.Errorf("%w", ctx.Err())
 		}
 	}
+	if m.NotifyReady {
+		lakta.MarkReady(ctx)
+	}
 	if m.OnStart != nil {
 		return m.OnStart(ctx)
 	}
 	return m.StartErr
 }
 
+// NotifiesReady reports whether NotifyReady is set.
+func (m *MockSyncModule) NotifiesReady() bool { return m.NotifyReady }
+
 // MockAsyncModule implements lakta.AsyncModule and tracks call counts.
 type MockAsyncModule struct {
 	MockModule
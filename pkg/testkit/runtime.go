@@ -17,11 +17,12 @@ const naturalCompletionGrace = 100 * time.Millisecond
 
 // RuntimeHarness starts a Runtime in a goroutine and provides a way to trigger graceful shutdown.
 type RuntimeHarness struct {
-	t      *testing.T
-	once   sync.Once
-	cancel context.CancelFunc
-	done   chan error
-	err    error
+	t       *testing.T
+	once    sync.Once
+	cancel  context.CancelFunc
+	runtime *lakta.Runtime
+	done    chan error
+	err     error
 }
 
 // NewRuntimeHarness creates a Runtime with the given modules and starts it immediately.
@@ -39,14 +40,14 @@ func newRuntimeHarnessCtx(t *testing.T, parent context.Context, modules ...lakta
 	ctx, cancel := context.WithCancel(parent)
 
 	h := &RuntimeHarness{
-		t:      t,
-		cancel: cancel,
-		done:   make(chan error, 1),
+		t:       t,
+		cancel:  cancel,
+		runtime: lakta.NewRuntime(modules...),
+		done:    make(chan error, 1),
 	}
 
-	rt := lakta.NewRuntime(modules...)
 	go func() {
-		h.done <- rt.RunContext(ctx)
+		h.done <- h.runtime.RunContext(ctx)
 	}()
 
 	t.Cleanup(func() {
@@ -79,7 +80,23 @@ func (h *RuntimeHarness) Err() error {
 	return h.err
 }
 
+// WaitReady blocks until every module is ready (see lakta.Runtime.WaitReady),
+// failing the test if the runtime fails first or is not ready within 5 seconds.
+// Server modules implementing lakta.ReadyNotifier have bound their listener by
+// then, so their Addr is set.
+func (h *RuntimeHarness) WaitReady() {
+	h.t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), waitReadyTimeout)
+	defer cancel()
+
+	if err := h.runtime.WaitReady(ctx); err != nil {
+		h.t.Fatalf("runtime did not become ready: %v", err)
+	}
+}
+
 const (
+	waitReadyTimeout    = 5 * time.Second
 	waitForAddrTimeout  = 2 * time.Second
 	waitForAddrInterval = 10 * time.Millisecond
 )
@@ -90,7 +107,8 @@ type addrProvider interface {
 }
 
 // WaitForAddr polls until m.Addr() returns a non-nil value or times out after 2 seconds.
-// Used in server module tests to wait for the listener to be bound before making connections.
+// Used in server module tests to wait for the listener to be bound before making connections;
+// RuntimeHarness.WaitReady waits without polling for modules that implement lakta.ReadyNotifier.
 func WaitForAddr(t *testing.T, m addrProvider) net.Addr {
 	t.Helper()
 	deadline := time.Now().Add(waitForAddrTimeout)
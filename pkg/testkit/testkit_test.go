@@ -99,6 +99,30 @@ func TestReloadNotifier_FireReloadInvokesCallbacks(t *testing.T) {
 	testza.AssertEqual(t, k, got)
 }
 
+func TestReloadNotifier_ReloadRollsBackOnFailedPrepare(t *testing.T) {
+	t.Parallel()
+
+	n := &testkit.ReloadNotifier{}
+	var events []string
+	n.OnPrepare("a", func(*koanf.Koanf) (lakta.PreparedReload, error) {
+		return lakta.PreparedReload{
+			Commit: func() { events = append(events, "commit a") },
+			Abort:  func() { events = append(events, "abort a") },
+		}, nil
+	})
+	n.OnReload(func(*koanf.Koanf) { events = append(events, "reload") })
+
+	testza.AssertNoError(t, n.Reload(koanf.New(".")))
+	testza.AssertEqual(t, []string{"commit a", "reload"}, events)
+
+	boom := errors.New("boom")
+	n.OnPrepare("b", func(*koanf.Koanf) (lakta.PreparedReload, error) { return lakta.PreparedReload{}, boom })
+
+	events = nil
+	testza.AssertErrorIs(t, n.Reload(koanf.New(".")), boom)
+	testza.AssertEqual(t, []string{"abort a"}, events)
+}
+
 func TestMockModule_CountsAndErrors(t *testing.T) {
 	t.Parallel()
 
@@ -185,6 +209,25 @@ func TestRuntimeHarness_ShutdownIdempotentAndErr(t *testing.T) {
 	testza.AssertEqual(t, int32(1), m.ShutdownCalls.Load())
 }
 
+func TestRuntimeHarness_WaitReady(t *testing.T) {
+	t.Parallel()
+
+	m := testkit.NewMockSyncModule()
+	m.NotifyReady = true
+	m.BlockStart = make(chan struct{})
+	m.OnStart = func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}
+
+	rh := testkit.NewRuntimeHarness(t, m)
+	close(m.BlockStart)
+	rh.WaitReady()
+
+	testza.AssertEqual(t, int32(1), m.StartCalls.Load())
+	testza.AssertNil(t, rh.Shutdown())
+}
+
 type fakeAddrProvider struct{ addr net.Addr }
 
 func (f fakeAddrProvider) Addr() net.Addr { return f.addr }
@@ -37,6 +37,11 @@ func (m *Module) LoadConfig(k *koanf.Koanf) error {
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
 // Init builds the pools and provides the [Registry] to the injector.
 func (m *Module) Init(ctx context.Context) error {
 	pools := make(map[string]*Pool)
@@ -26,6 +26,7 @@ type Module struct {
 	lakta.NamedBase
 
 	config Config
+	koanf  *koanf.Koanf // the config last loaded; reloads are diffed against it
 	sched  *Scheduler
 }
 
@@ -45,9 +46,15 @@ func (m *Module) ConfigPath() string {
 
 // LoadConfig loads configuration from koanf.
 func (m *Module) LoadConfig(k *koanf.Koanf) error {
+	m.koanf = k
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
 // Init builds the gocron scheduler, registers every merged job, and provides
 // the [Scheduler] to the injector so app modules can Register more jobs during
 // their own Init (topo-sort guarantees scheduler inits first when declared a dep).
@@ -125,10 +132,10 @@ func (m *Module) Shutdown(ctx context.Context) error {
 	}
 }
 
-// OnReload re-loads config then diffs MergedJobs against the live specs: added
-// names get Registered, removed names get removed, and any name whose
-// schedule/tz/jitter/overlap/enabled changed is re-Registered. Handlers are
-// code-owned and persist across reload (config never carries a func).
+// OnReload re-loads config then reconciles MergedJobs with the live specs:
+// added names get Registered, removed names get removed, and any name whose
+// config keys the reload changed is re-Registered. Handlers are code-owned and
+// persist across reload (config never carries a func).
 func (m *Module) OnReload(k *koanf.Koanf) {
 	ctx := m.sched.runCtx
 
@@ -141,7 +148,11 @@ func (m *Module) OnReload(k *koanf.Koanf) {
 		return
 	}
 
+	jobsPath := m.ConfigPath() + ".jobs."
+	changed := config.DiffKoanf(m.koanf, k).Under(m.ConfigPath())
+
 	m.config = reloaded
+	m.koanf = k
 	desired := reloaded.MergedJobs()
 
 	m.sched.mu.Lock()
@@ -157,8 +168,7 @@ func (m *Module) OnReload(k *koanf.Koanf) {
 	}
 
 	for name, spec := range desired {
-		old, existed := live[name]
-		if existed && !specChanged(old, spec) {
+		if _, existed := live[name]; existed && len(changed.Under(jobsPath+name)) == 0 {
 			continue
 		}
 
@@ -168,16 +178,6 @@ func (m *Module) OnReload(k *koanf.Koanf) {
 	}
 }
 
-// specChanged reports whether any config-derived field of two specs differs.
-// Handler is code-owned and excluded (funcs are not comparable anyway).
-func specChanged(a, b JobSpec) bool {
-	return a.Schedule != b.Schedule ||
-		a.Timezone != b.Timezone ||
-		a.Jitter != b.Jitter ||
-		a.Overlap != b.Overlap ||
-		a.enabled() != b.enabled()
-}
-
 // optionalTracer resolves a Tracer from DI's TracerProvider, or a noop tracer
 // when otel is absent.
 func optionalTracer(ctx context.Context) oteltrace.Tracer { //nolint:ireturn // oteltrace.Tracer is the library interface
@@ -81,14 +81,3 @@ func TestJobDefinition_EveryAndCron(t *testing.T) {
 	_, err = JobSpec{Schedule: "0 0 12 * * *", Timezone: "America/New_York"}.jobDefinition("UTC")
 	testza.AssertNoError(t, err)
 }
-
-func TestSpecChanged(t *testing.T) {
-	t.Parallel()
-	base := JobSpec{Schedule: everyHour, Overlap: OverlapSkip}
-
-	testza.AssertFalse(t, specChanged(base, JobSpec{Schedule: everyHour, Overlap: OverlapSkip}))
-	testza.AssertTrue(t, specChanged(base, JobSpec{Schedule: "@every 2h", Overlap: OverlapSkip}))
-
-	disabled := false
-	testza.AssertTrue(t, specChanged(base, JobSpec{Schedule: everyHour, Overlap: OverlapSkip, Enabled: &disabled}))
-}
@@ -52,6 +52,11 @@ func (m *Module) LoadConfig(k *koanf.Koanf) error {
 	return m.config.LoadFromKoanf(k, m.ConfigPath())
 }
 
+// ConfigStruct returns the config struct the module loads.
+func (m *Module) ConfigStruct() any {
+	return &m.config
+}
+
 // Provides returns the types this module registers in DI.
 func (m *Module) Provides() []reflect.Type {
 	return []reflect.Type{